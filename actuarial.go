@@ -0,0 +1,15 @@
+package goint
+
+// PresentValue computes the actuarial present value
+//
+//	integral over [a, b] of v(t) p(t) benefit(t) dt
+//
+// of a continuous benefit stream, where v is the discount function and p
+// the survival probability, to within tol. Any of the three curves may be
+// closed-form Functions or the At method of a Table; the knots of tabulated
+// curves should be passed as breaks (see Knots) so that the integration
+// splits exactly where the interpolated curves have kinks.
+func PresentValue(v, p, benefit Function, a, b, tol float64, breaks ...float64) float64 {
+	f := func(t float64) float64 { return v(t) * p(t) * benefit(t) }
+	return integrateBreakpoints(f, a, b, tol, breaks)
+}
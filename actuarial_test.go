@@ -0,0 +1,45 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTable(t *testing.T) {
+	table := &Table{X: []float64{0, 1, 3}, Y: []float64{1, 3, -1}}
+
+	cases := map[float64]float64{-1: 1, 0: 1, .5: 2, 1: 3, 2: 1, 3: -1, 10: -1}
+	for x, want := range cases {
+		if got := table.At(x); got != want {
+			t.Errorf("At(%g) = %g, want %g", x, got, want)
+		}
+	}
+}
+
+func TestPresentValue(t *testing.T) {
+	const (
+		tol   = 1e-10
+		delta = .03
+		mu    = .02
+	)
+
+	// A continuous unit annuity over [0, 10] under constant force of
+	// interest and mortality is (1 - exp(-10 (delta + mu))) / (delta + mu)
+	v := func(t float64) float64 { return math.Exp(-delta * t) }
+	p := func(t float64) float64 { return math.Exp(-mu * t) }
+	one := func(t float64) float64 { return 1 }
+
+	want := (1 - math.Exp(-10*(delta+mu))) / (delta + mu)
+	if msg, ok := checkValue(PresentValue(v, p, one, 0, 10, tol), want, tol); !ok {
+		t.Error(msg)
+	}
+
+	// A tabulated survival curve with a kink inside the term, which is
+	// integrated exactly when its knots are given as breakpoints
+	table := &Table{X: []float64{0, 2.5, 10}, Y: []float64{1, .9, .2}}
+	got := PresentValue(one, table.At, one, 0, 10, tol, Knots(table)...)
+	want = 2.5*(1+.9)/2 + 7.5*(.9+.2)/2
+	if msg, ok := checkValue(got, want, tol); !ok {
+		t.Error(msg)
+	}
+}
@@ -0,0 +1,231 @@
+package goint
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+)
+
+// The default cap on the number of panels in an adaptive partition.
+const maxPanels = 1 << 16
+
+// A panelRule estimates the integral of f over [a, b] along with an
+// absolute error estimate for that value.
+type panelRule func(f Function, a, b float64) (value, err float64)
+
+// Estimates the integral over [a, b] with Boole's rule, using the
+// difference from composite Simpson's rule on the same five points as the
+// error estimate.
+func boolePanel(f Function, a, b float64) (float64, float64) {
+	h := (b - a) / 4.0
+	fa := f(a)
+	f2 := f(a + h)
+	f3 := f(a + 2*h)
+	f4 := f(a + 3*h)
+	fb := f(b)
+
+	boole := 2 * h * (7*fa + 32*f2 + 12*f3 + 32*f4 + 7*fb) / 45.0
+	simpson := h * (fa + 4*f2 + 2*f3 + 4*f4 + fb) / 3.0
+
+	return boole, math.Abs(boole - simpson)
+}
+
+// A panelSet holds an adaptive partition as parallel slices, so that the
+// i-th panel is [lefts[i], rights[i]] with integral estimate values[i] and
+// error estimate errors[i]. Keeping each quantity contiguous lets bulk
+// passes, such as summing the errors of hundreds of thousands of panels,
+// run over dense memory. order is a heap of panel indices with the
+// largest error on top. Panels merged away to save memory contribute
+// mergedValue and mergedErr.
+type panelSet struct {
+	lefts, rights  []float64
+	values, errors []float64
+	order          []int
+
+	mergedValue, mergedErr float64
+}
+
+// The storage used by one panel of a panelSet.
+const panelBytes = 5 * 8
+
+func (s *panelSet) Len() int           { return len(s.order) }
+func (s *panelSet) Less(i, j int) bool { return s.errors[s.order[i]] > s.errors[s.order[j]] }
+func (s *panelSet) Swap(i, j int)      { s.order[i], s.order[j] = s.order[j], s.order[i] }
+func (s *panelSet) Push(x interface{}) { s.order = append(s.order, x.(int)) }
+func (s *panelSet) Pop() interface{} {
+	i := s.order[len(s.order)-1]
+	s.order = s.order[:len(s.order)-1]
+	return i
+}
+
+// Appends the panel [a, b] and returns its index, without touching the
+// heap.
+func (s *panelSet) add(a, b, value, err float64) int {
+	s.lefts = append(s.lefts, a)
+	s.rights = append(s.rights, b)
+	s.values = append(s.values, value)
+	s.errors = append(s.errors, err)
+	return len(s.lefts) - 1
+}
+
+// Returns the summed integral and error estimates over all panels.
+func (s *panelSet) sum() (value, err float64) {
+	value, err = s.mergedValue, s.mergedErr
+	for _, v := range s.values {
+		value += v
+	}
+	for _, e := range s.errors {
+		err += e
+	}
+	return value, err
+}
+
+// Returns the summed integral and error estimates over all panels, each
+// reduced by a balanced pairwise tree in index order.
+func (s *panelSet) pairwiseSum() (value, err float64) {
+	return s.mergedValue + pairwiseSum(s.values), s.mergedErr + pairwiseSum(s.errors)
+}
+
+// Sums xs by recursive halving, whose rounding error grows with the
+// logarithm of the length rather than the length, and whose association
+// depends only on the length.
+func pairwiseSum(xs []float64) float64 {
+	if len(xs) <= 8 {
+		sum := 0.0
+		for _, x := range xs {
+			sum += x
+		}
+		return sum
+	}
+	m := len(xs) / 2
+	return pairwiseSum(xs[:m]) + pairwiseSum(xs[m:])
+}
+
+// Folds the half of the panels with the smallest errors into the merged
+// contribution and compacts the rest, reporting whether any were freed.
+func (s *panelSet) merge() bool {
+	n := len(s.lefts)
+	if n < 2 {
+		return false
+	}
+
+	byErr := make([]int, n)
+	for i := range byErr {
+		byErr[i] = i
+	}
+	sort.SliceStable(byErr, func(i, j int) bool { return s.errors[byErr[i]] < s.errors[byErr[j]] })
+
+	drop := make([]bool, n)
+	for _, i := range byErr[:n/2] {
+		drop[i] = true
+		s.mergedValue += s.values[i]
+		s.mergedErr += s.errors[i]
+	}
+
+	kept := 0
+	for i := 0; i < n; i++ {
+		if !drop[i] {
+			s.lefts[kept], s.rights[kept] = s.lefts[i], s.rights[i]
+			s.values[kept], s.errors[kept] = s.values[i], s.errors[i]
+			kept++
+		}
+	}
+	s.lefts, s.rights = s.lefts[:kept], s.rights[:kept]
+	s.values, s.errors = s.values[:kept], s.errors[:kept]
+
+	s.order = s.order[:kept]
+	for i := range s.order {
+		s.order[i] = i
+	}
+	heap.Init(s)
+
+	return true
+}
+
+// Bisects the panel with the largest error, returning the resulting
+// changes in the integral and error estimates. Reports false, changing
+// nothing, if the panel is too narrow to bisect.
+func (s *panelSet) bisect(f Function, rule panelRule) (dvalue, derr float64, ok bool) {
+	worst := s.order[0]
+
+	a, b := s.lefts[worst], s.rights[worst]
+	m := a + (b-a)/2
+	if m <= a || m >= b {
+		return 0, 0, false
+	}
+
+	lvalue, lerr := rule(f, a, m)
+	rvalue, rerr := rule(f, m, b)
+	dvalue = lvalue + rvalue - s.values[worst]
+	derr = lerr + rerr - s.errors[worst]
+
+	// The left half reuses the worst panel's slot
+	s.rights[worst], s.values[worst], s.errors[worst] = m, lvalue, lerr
+	heap.Fix(s, 0)
+	heap.Push(s, s.add(m, b, rvalue, rerr))
+
+	return dvalue, derr, true
+}
+
+// Returns the partition of the interval spanned by points into panels
+// between consecutive points, each estimated with rule, along with the
+// summed integral and error estimates.
+func newPanelSet(f Function, points []float64, rule panelRule) (s *panelSet, sum, total float64) {
+	n := len(points) - 1
+	s = &panelSet{
+		lefts:  make([]float64, 0, n),
+		rights: make([]float64, 0, n),
+		values: make([]float64, 0, n),
+		errors: make([]float64, 0, n),
+		order:  make([]int, 0, n),
+	}
+	for i := 1; i < len(points); i++ {
+		value, err := rule(f, points[i-1], points[i])
+		s.order = append(s.order, s.add(points[i-1], points[i], value, err))
+		sum += value
+		total += err
+	}
+	heap.Init(s)
+
+	return s, sum, total
+}
+
+// Adaptively partitions the finite interval spanned by points, which must
+// be increasing, by repeatedly bisecting the panel with the largest error
+// until done reports that the partition is acceptable. done is passed the
+// estimated integral, the total error and the largest panel error. Once
+// limit panels exist, full is called to free space; refinement stops if it
+// is nil or reports that it freed nothing. Refinement also stops when the
+// worst panel can no longer be bisected, or when the total error is NaN,
+// which no bisection can cure. The totals are tracked incrementally and
+// recomputed in bulk before they are trusted.
+func adapt(f Function, points []float64, rule panelRule, limit int,
+	full func(s *panelSet) bool, done func(value, total, worst float64) bool) *panelSet {
+	s, sum, total := newPanelSet(f, points, rule)
+	for len(s.order) > 0 {
+		if math.IsNaN(total) {
+			// Infinite panel errors can cancel in the running total
+			if sum, total = s.sum(); math.IsNaN(total) {
+				break
+			}
+		}
+		if done(sum, total, s.errors[s.order[0]]) {
+			// Rounding accumulates in the running totals; confirm them
+			if sum, total = s.sum(); done(sum, total, s.errors[s.order[0]]) {
+				break
+			}
+		}
+		if len(s.order) >= limit && (full == nil || !full(s)) {
+			break
+		}
+
+		dvalue, derr, ok := s.bisect(f, rule)
+		if !ok {
+			break
+		}
+		sum += dvalue
+		total += derr
+	}
+
+	return s
+}
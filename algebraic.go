@@ -0,0 +1,142 @@
+package goint
+
+import (
+	"math"
+)
+
+const (
+	// The degrees of the Chebyshev interpolants compared on the panels at
+	// the endpoints by IntegrateAlgebraicLog.
+	algebraicCoarse = 16
+	algebraicFine   = 2 * algebraicCoarse
+)
+
+// IntegrateAlgebraicLog integrates
+//
+//	f(x) (x-a)^alpha (b-x)^beta log^i(x-a) log^j(b-x)
+//
+// over the finite interval [a, b], a < b, to within tol, for alpha, beta >
+// -1 and non-negative integers i and j, in the manner of QUADPACK's
+// dqaws. The interval is bisected adaptively. On the two panels touching
+// a or b, the singular factor at that endpoint is treated as a weight and
+// the rest of the integrand is interpolated by a Chebyshev series, whose
+// terms are integrated against the weight exactly through modified
+// Chebyshev moments; interpolants of degree 16 and 32 are compared for the
+// error estimate. Interior panels, where the weight is smooth, use the
+// 15-point Gauss-Kronrod rule. The moments are computed once per call by
+// tanh-sinh quadrature to full precision. f should be smooth on [a, b].
+// Invalid arguments give a NaN estimate and ErrInvalidInput; if the
+// tolerance cannot be met, the best estimate is returned with
+// ErrNotConverged.
+func IntegrateAlgebraicLog(f Function, a, b, alpha, beta float64, i, j int, tol float64) (Result, error) {
+	if math.IsNaN(a) || math.IsNaN(b) || math.IsInf(a, 0) || math.IsInf(b, 0) || !(a < b) ||
+		!(alpha > -1) || !(beta > -1) || i < 0 || j < 0 || !(tol >= 0) {
+		return Result{Value: math.NaN()}, ErrInvalidInput
+	}
+
+	left := algebraicMoments(alpha, i)
+	right := algebraicMoments(beta, j)
+	w := func(x float64) float64 {
+		return math.Pow(x-a, alpha) * math.Pow(b-x, beta) *
+			math.Pow(math.Log(x-a), float64(i)) * math.Pow(math.Log(b-x), float64(j))
+	}
+
+	evals := 0
+	counted := func(x float64) float64 {
+		evals++
+		return f(x)
+	}
+	weighted := func(x float64) float64 { return counted(x) * w(x) }
+
+	// The weight at the far endpoint is smooth on an end panel and is
+	// interpolated along with f
+	panel := func(_ Function, lo, hi float64) (float64, float64) {
+		switch {
+		case lo == a:
+			g := func(x float64) float64 {
+				return counted(x) * math.Pow(b-x, beta) * math.Pow(math.Log(b-x), float64(j))
+			}
+			return algebraicPanel(g, lo, hi, alpha, left, 1)
+		case hi == b:
+			g := func(x float64) float64 {
+				return counted(x) * math.Pow(x-a, alpha) * math.Pow(math.Log(x-a), float64(i))
+			}
+			return algebraicPanel(g, lo, hi, beta, right, -1)
+		default:
+			return gk15.panel(weighted, lo, hi)
+		}
+	}
+
+	done := func(value, total, worst float64) bool { return total <= tol }
+	panels := adapt(weighted, []float64{a, a + (b-a)/2, b}, panel, maxPanels, nil, done)
+
+	r := Result{Evals: evals, Panels: len(panels.lefts)}
+	r.Value, r.Error = panels.sum()
+	if !(r.Error <= tol) {
+		return r, ErrNotConverged
+	}
+	return r, nil
+}
+
+// Returns the modified Chebyshev moments of the weight (1+t)^alpha
+// log^m(1+t) on [-1, 1], for m up to n and degrees up to algebraicFine,
+// indexed by m and then degree. The singularity is placed at zero for
+// tanh-sinh quadrature by integrating over u = 1 + t.
+func algebraicMoments(alpha float64, n int) [][]float64 {
+	moments := make([][]float64, n+1)
+	T := make([]float64, algebraicFine+1)
+	dT := make([]float64, algebraicFine+1)
+	for m := range moments {
+		moments[m] = make([]float64, algebraicFine+1)
+		for k := range moments[m] {
+			g := func(u float64) float64 {
+				chebBasis(u-1, T, dT)
+				return math.Pow(u, alpha) * math.Pow(math.Log(u), float64(m)) * T[k]
+			}
+			r, _ := TanhSinh(g, 0, 2, 1e-15)
+			moments[m][k] = r.Value
+		}
+	}
+
+	return moments
+}
+
+// Integrates g times the weight |x-e|^alpha log^m|x-e| over [lo, hi],
+// where e is lo when side is 1 and hi when side is -1, with the moments
+// of the weight. Returns the fine estimate and its difference from the
+// coarse one.
+func algebraicPanel(g Function, lo, hi, alpha float64, moments [][]float64, side float64) (float64, float64) {
+	h := (hi - lo) / 2
+	logH := math.Log(h)
+
+	// Expand log^n(h (1 + t)) binomially in powers of log(1 + t)
+	n := len(moments) - 1
+	weights := make([]float64, algebraicFine+1)
+	binomial := 1.0
+	for m := 0; m <= n; m++ {
+		scale := binomial * math.Pow(logH, float64(n-m))
+		for k := range weights {
+			weights[k] += scale * moments[m][k]
+		}
+		binomial = binomial * float64(n-m) / float64(m+1)
+	}
+	if side < 0 {
+		// T_k(-t) = (-1)^k T_k(t)
+		for k := 1; k < len(weights); k += 2 {
+			weights[k] = -weights[k]
+		}
+	}
+
+	fine := NewChebyshev(g, lo, hi, algebraicFine).Coefficients()
+	coarse := NewChebyshev(g, lo, hi, algebraicCoarse).Coefficients()
+	estimate := func(coefs []float64) float64 {
+		sum := 0.0
+		for k, c := range coefs {
+			sum += c * weights[k]
+		}
+		return math.Pow(h, alpha+1) * sum
+	}
+
+	value := estimate(fine)
+	return value, math.Abs(value - estimate(coarse))
+}
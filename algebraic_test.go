@@ -0,0 +1,50 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestIntegrateAlgebraicLog(t *testing.T) {
+	const tol = 1e-10
+	one := func(float64) float64 { return 1 }
+	cases := []struct {
+		f           Function
+		a, b        float64
+		alpha, beta float64
+		i, j        int
+		correct     float64
+	}{
+		{one, 0, 1, 0, 0, 1, 0, -1},
+		{one, 0, 1, -.5, 0, 0, 0, 2},
+		{one, 0, 1, -.5, 0, 1, 0, -4},
+		{one, 0, 1, 0, 0, 1, 1, 2 - math.Pi*math.Pi/6},
+		{one, 0, 1, -.5, -.5, 0, 0, math.Pi},
+		{one, 2, 3, .7, -.9, 0, 0, math.Exp(lbeta(1.7, .1))},
+		{one, 0, 4, 0, 0, 2, 0, 4*math.Pow(math.Log(4), 2) - 8*math.Log(4) + 8},
+		// sqrt(2 pi) times the Fresnel integral C(sqrt(2 / pi))
+		{math.Cos, 0, 1, -.5, 0, 0, 0, 1.8090484758005438},
+	}
+
+	for n, c := range cases {
+		r, err := IntegrateAlgebraicLog(c.f, c.a, c.b, c.alpha, c.beta, c.i, c.j, tol)
+		if err != nil {
+			t.Errorf("case %d: %v", n, err)
+		}
+		if msg, ok := checkValue(r.Value, c.correct, 10*tol); !ok {
+			t.Errorf("case %d: %s", n, msg)
+		}
+	}
+
+	if _, err := IntegrateAlgebraicLog(one, 0, 1, -1, 0, 0, 0, tol); err != ErrInvalidInput {
+		t.Errorf("alpha = -1 gave %v", err)
+	}
+}
+
+// Returns the logarithm of the beta function.
+func lbeta(x, y float64) float64 {
+	a, _ := math.Lgamma(x)
+	b, _ := math.Lgamma(y)
+	c, _ := math.Lgamma(x + y)
+	return a + b - c
+}
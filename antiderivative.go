@@ -0,0 +1,48 @@
+package goint
+
+import (
+	"math"
+	"sort"
+)
+
+// Antiderivative returns the function F(x) = integral of f over [a, x],
+// each piece integrated to within tol. Every point at which F is evaluated
+// is remembered, and later evaluations only integrate f from the nearest
+// remembered point, so sweeping F across an interval costs about as much
+// as a single integral over it. For x < a, F(x) is the negated integral
+// over [x, a]. The returned Function is not safe for concurrent use.
+func Antiderivative(f Function, a, tol float64) Function {
+	return antiderivative(f, a, 0, tol)
+}
+
+// Returns the antiderivative of f taking the value Fa at a.
+func antiderivative(f Function, a, Fa, tol float64) Function {
+	xs := []float64{a}
+	Fs := []float64{Fa}
+
+	return func(x float64) float64 {
+		i := sort.SearchFloat64s(xs, x)
+		if i < len(xs) && xs[i] == x {
+			return Fs[i]
+		}
+
+		// Integrate from whichever neighbouring knot is closer
+		var Fx float64
+		if i == len(xs) || (i > 0 && x-xs[i-1] <= xs[i]-x) {
+			Fx = Fs[i-1] + Integrate(f, xs[i-1], x, tol)
+		} else {
+			Fx = Fs[i] - Integrate(f, x, xs[i], tol)
+		}
+
+		if !math.IsNaN(Fx) && !math.IsInf(x, 0) {
+			xs = append(xs, 0)
+			Fs = append(Fs, 0)
+			copy(xs[i+1:], xs[i:])
+			copy(Fs[i+1:], Fs[i:])
+			xs[i] = x
+			Fs[i] = Fx
+		}
+
+		return Fx
+	}
+}
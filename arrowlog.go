@@ -0,0 +1,307 @@
+package goint
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+)
+
+// The most records an ArrowLog holds before writing them as a batch.
+const arrowBatchRows = 1 << 14
+
+// The columns of an evaluation log in Arrow, with whether each is a
+// float64 rather than an int64.
+var arrowColumns = []struct {
+	name  string
+	float bool
+}{
+	{"x", true},
+	{"y", true},
+	{"panel", false},
+	{"level", false},
+}
+
+// An ArrowLog is a RecordWriter writing an Arrow IPC stream, the format
+// read by pyarrow.ipc.open_stream and Arrow's RecordBatchStreamReader, so
+// that evaluation logs can be analyzed, or converted to Parquet, with
+// standard tooling. The stream has non-nullable columns x and y of
+// float64 and panel and level of int64. Records are buffered and written
+// as record batches of up to 16384 rows; Close must be called to write
+// the rest and end the stream.
+type ArrowLog struct {
+	w      io.Writer
+	schema bool
+	x, y   []float64
+	panel  []int64
+	level  []int64
+	err    error
+}
+
+// NewArrowLog returns an ArrowLog writing to w.
+func NewArrowLog(w io.Writer) *ArrowLog {
+	return &ArrowLog{w: w}
+}
+
+// WriteRecord buffers r, writing a record batch once enough records have
+// been buffered. After the first error from the underlying writer, it is
+// returned by every call.
+func (l *ArrowLog) WriteRecord(r EvaluationRecord) error {
+	if l.err != nil {
+		return l.err
+	}
+	l.x = append(l.x, r.X)
+	l.y = append(l.y, r.Y)
+	l.panel = append(l.panel, int64(r.Panel))
+	l.level = append(l.level, int64(r.Level))
+	if len(l.x) >= arrowBatchRows {
+		return l.Flush()
+	}
+	return nil
+}
+
+// Flush writes the buffered records as a record batch, preceded by the
+// schema if it has not been written, returning the first error met.
+func (l *ArrowLog) Flush() error {
+	if l.err == nil && !l.schema {
+		l.schema = true
+		l.err = writeArrowMessage(l.w, 1, arrowSchema(), nil)
+	}
+	if l.err != nil || len(l.x) == 0 {
+		return l.err
+	}
+
+	n := len(l.x)
+	body := make([]byte, 0, 8*n*len(arrowColumns))
+	for _, vs := range [][]float64{l.x, l.y} {
+		for _, v := range vs {
+			body = binary.LittleEndian.AppendUint64(body, math.Float64bits(v))
+		}
+	}
+	for _, vs := range [][]int64{l.panel, l.level} {
+		for _, v := range vs {
+			body = binary.LittleEndian.AppendUint64(body, uint64(v))
+		}
+	}
+
+	l.err = writeArrowMessage(l.w, 3, arrowRecordBatch(n), body)
+	l.x, l.y, l.panel, l.level = l.x[:0], l.y[:0], l.panel[:0], l.level[:0]
+	return l.err
+}
+
+// Close writes any buffered records and ends the stream, returning the
+// first error met. It does not close the underlying writer, and no
+// records may be written after it.
+func (l *ArrowLog) Close() error {
+	if err := l.Flush(); err != nil {
+		return err
+	}
+	_, l.err = l.w.Write([]byte{0xff, 0xff, 0xff, 0xff, 0, 0, 0, 0})
+	return l.err
+}
+
+// Returns the Schema table of an evaluation log.
+func arrowSchema() fbTable {
+	fields := make(fbTables, len(arrowColumns))
+	for i, c := range arrowColumns {
+		typ, kind := fbTable{fbScalar(2, 2)}, uint64(3) // FloatingPoint(DOUBLE)
+		if !c.float {
+			typ, kind = fbTable{fbScalar(4, 64), fbScalar(1, 1)}, 2 // Int(64, signed)
+		}
+		fields[i] = fbTable{
+			fbRef(fbString(c.name)),
+			fbScalar(1, 0), // not nullable
+			fbScalar(1, kind),
+			fbRef(typ),
+			{},
+			fbRef(fbTables{}), // no children
+		}
+	}
+
+	// Little-endian, with the fields
+	return fbTable{fbScalar(2, 0), fbRef(fields)}
+}
+
+// Returns the RecordBatch table of a batch of n records, whose body holds
+// each column in turn with no validity bitmaps.
+func arrowRecordBatch(n int) fbTable {
+	nodes := make([]byte, 0, 16*len(arrowColumns))
+	buffers := make([]byte, 0, 32*len(arrowColumns))
+	for i := range arrowColumns {
+		nodes = binary.LittleEndian.AppendUint64(nodes, uint64(n))
+		nodes = binary.LittleEndian.AppendUint64(nodes, 0)
+
+		offset := uint64(8 * n * i)
+		buffers = binary.LittleEndian.AppendUint64(buffers, offset)
+		buffers = binary.LittleEndian.AppendUint64(buffers, 0)
+		buffers = binary.LittleEndian.AppendUint64(buffers, offset)
+		buffers = binary.LittleEndian.AppendUint64(buffers, uint64(8*n))
+	}
+
+	return fbTable{
+		fbScalar(8, uint64(n)),
+		fbRef(fbStructs{len(arrowColumns), nodes}),
+		fbRef(fbStructs{2 * len(arrowColumns), buffers}),
+	}
+}
+
+// Writes an encapsulated Arrow IPC message with the given header type and
+// header, followed by body, which must be a multiple of 8 bytes long: a
+// continuation marker, the padded length of the Message flatbuffer, the
+// flatbuffer itself and the body.
+func writeArrowMessage(w io.Writer, kind uint64, header fbTable, body []byte) error {
+	message := fbTable{
+		fbScalar(2, 4), // MetadataVersion V5
+		fbScalar(1, kind),
+		fbRef(header),
+		fbScalar(8, uint64(len(body))),
+	}
+	meta := fbBuild(message)
+	for len(meta)%8 != 0 {
+		meta = append(meta, 0)
+	}
+
+	buf := make([]byte, 0, 8+len(meta))
+	buf = binary.LittleEndian.AppendUint32(buf, 0xffffffff)
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(meta)))
+	buf = append(buf, meta...)
+	if _, err := w.Write(buf); err != nil {
+		return err
+	}
+	_, err := w.Write(body)
+	return err
+}
+
+// ReadArrowLog reads the records written by an ArrowLog. Streams with a
+// different schema, null values or compressed buffers are rejected with
+// ErrInvalidInput.
+func ReadArrowLog(r io.Reader) ([]EvaluationRecord, error) {
+	var records []EvaluationRecord
+	schema := false
+	for {
+		kind, header, body, err := readArrowMessage(r)
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case kind == 1 && !schema && arrowSchemaMatches(header):
+			schema = true
+		case kind == 3 && schema:
+			batch, ok := arrowRecords(header, body)
+			if !ok {
+				return nil, ErrInvalidInput
+			}
+			records = append(records, batch...)
+		default:
+			return nil, ErrInvalidInput
+		}
+	}
+}
+
+// Reads one encapsulated message, returning its header type, header table
+// and body, or io.EOF at the end-of-stream marker or the end of r.
+func readArrowMessage(r io.Reader) (uint64, fbView, []byte, error) {
+	var prefix [8]byte
+	if _, err := io.ReadFull(r, prefix[:]); err != nil {
+		return 0, fbView{}, nil, err
+	}
+	if binary.LittleEndian.Uint32(prefix[:]) != 0xffffffff {
+		return 0, fbView{}, nil, ErrInvalidInput
+	}
+	size := binary.LittleEndian.Uint32(prefix[4:])
+	if size == 0 {
+		return 0, fbView{}, nil, io.EOF
+	}
+
+	meta := make([]byte, size)
+	if _, err := io.ReadFull(r, meta); err != nil {
+		return 0, fbView{}, nil, unexpected(err)
+	}
+	message, ok := fbRoot(meta)
+	if !ok {
+		return 0, fbView{}, nil, ErrInvalidInput
+	}
+	header, ok := message.table(2)
+	length := message.scalar(3, 8)
+	if !ok || length > 1<<40 {
+		return 0, fbView{}, nil, ErrInvalidInput
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return 0, fbView{}, nil, unexpected(err)
+	}
+	return message.scalar(1, 1), header, body, nil
+}
+
+// Returns err, with io.EOF replaced by io.ErrUnexpectedEOF.
+func unexpected(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// Reports whether the Schema table s is that written by an ArrowLog.
+func arrowSchemaMatches(s fbView) bool {
+	fields, ok := s.tables(1)
+	if s.scalar(0, 2) != 0 || !ok || len(fields) != len(arrowColumns) {
+		return false
+	}
+	for i, c := range arrowColumns {
+		f := fields[i]
+		name, _ := f.vector(0, 1)
+		typ, ok := f.table(3)
+		if string(name) != c.name || !ok {
+			return false
+		}
+		if c.float && (f.scalar(2, 1) != 3 || typ.scalar(0, 2) != 2) {
+			return false
+		}
+		if !c.float && (f.scalar(2, 1) != 2 || typ.scalar(0, 4) != 64 || typ.scalar(1, 1) != 1) {
+			return false
+		}
+	}
+	return true
+}
+
+// Decodes the records of the RecordBatch table b with the given body,
+// reporting whether it is well formed and free of nulls and compression.
+func arrowRecords(b fbView, body []byte) ([]EvaluationRecord, bool) {
+	n := b.scalar(0, 8)
+	nodes, _ := b.vector(1, 16)
+	buffers, _ := b.vector(2, 16)
+	if _, compressed := b.table(3); compressed || n > uint64(len(body)/8) ||
+		len(nodes) != 16*len(arrowColumns) || len(buffers) != 32*len(arrowColumns) {
+		return nil, false
+	}
+
+	records := make([]EvaluationRecord, n)
+	for i, c := range arrowColumns {
+		node, buffer := nodes[16*i:], buffers[32*i+16:]
+		offset, size := binary.LittleEndian.Uint64(buffer), binary.LittleEndian.Uint64(buffer[8:])
+		if binary.LittleEndian.Uint64(node) != n || binary.LittleEndian.Uint64(node[8:]) != 0 ||
+			size < 8*n || offset > uint64(len(body)) || size > uint64(len(body))-offset {
+			return nil, false
+		}
+
+		data := body[offset:]
+		for j := range records {
+			v := binary.LittleEndian.Uint64(data[8*j:])
+			switch c.name {
+			case "x":
+				records[j].X = math.Float64frombits(v)
+			case "y":
+				records[j].Y = math.Float64frombits(v)
+			case "panel":
+				records[j].Panel = int(int64(v))
+			case "level":
+				records[j].Level = int(int64(v))
+			}
+		}
+	}
+	return records, true
+}
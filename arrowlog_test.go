@@ -0,0 +1,85 @@
+package goint
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+)
+
+func TestArrowLog(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewArrowLog(&buf)
+	r, err := IntegrateGK(math.Exp, 0, 1, 1e-10, WithEvaluationLog(w))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Each message starts with a continuation marker and 8-byte aligned
+	// metadata, and the stream ends with the end-of-stream marker
+	stream := buf.Bytes()
+	if binary.LittleEndian.Uint32(stream) != 0xffffffff || binary.LittleEndian.Uint32(stream[4:])%8 != 0 {
+		t.Errorf("stream starts with % x", stream[:8])
+	}
+	if !bytes.HasSuffix(stream, []byte{0xff, 0xff, 0xff, 0xff, 0, 0, 0, 0}) {
+		t.Errorf("stream ends with % x", stream[len(stream)-8:])
+	}
+
+	records, err := ReadArrowLog(bytes.NewReader(stream))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != r.Evals {
+		t.Fatalf("%d records for %d evaluations", len(records), r.Evals)
+	}
+	replayed, err := Replay(records, 0, 1, 1e-10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replayed.Value != r.Value {
+		t.Errorf("replayed %g, recorded %g", replayed.Value, r.Value)
+	}
+
+	// Truncated or corrupted streams are rejected
+	if _, err := ReadArrowLog(bytes.NewReader(stream[:len(stream)/2])); err == nil {
+		t.Error("expected error for a truncated stream")
+	}
+	corrupt := append([]byte(nil), stream...)
+	corrupt[8] ^= 0xff
+	if _, err := ReadArrowLog(bytes.NewReader(corrupt)); err == nil {
+		t.Error("expected error for a corrupted stream")
+	}
+}
+
+func TestArrowLogBatches(t *testing.T) {
+	// An empty log is a schema alone
+	var buf bytes.Buffer
+	if err := NewArrowLog(&buf).Close(); err != nil {
+		t.Fatal(err)
+	}
+	if records, err := ReadArrowLog(&buf); err != nil || len(records) != 0 {
+		t.Errorf("empty log read as %v, %v", records, err)
+	}
+
+	// Logs longer than a batch are split
+	w := NewArrowLog(&buf)
+	n := 2*arrowBatchRows + 3
+	for i := 0; i < n; i++ {
+		w.WriteRecord(EvaluationRecord{X: float64(i), Y: -float64(i), Panel: i, Level: -i})
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	records, err := ReadArrowLog(&buf)
+	if err != nil || len(records) != n {
+		t.Fatalf("read %d records, %v", len(records), err)
+	}
+	for i, r := range records {
+		if r != (EvaluationRecord{X: float64(i), Y: -float64(i), Panel: i, Level: -i}) {
+			t.Fatalf("record %d read as %v", i, r)
+		}
+	}
+}
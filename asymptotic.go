@@ -0,0 +1,271 @@
+package goint
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+const (
+	// The number of samples used to locate extrema and stationary points.
+	asymptoticSamples = 256
+
+	// The number of golden-section steps used to refine an extremum.
+	goldenSteps = 100
+)
+
+// A Branch reports which method produced a result.
+type Branch int
+
+const (
+	// The result came from adaptive quadrature that met its tolerance.
+	QuadratureBranch Branch = iota
+
+	// The result came from an asymptotic expansion in the large
+	// parameter, because quadrature did not converge.
+	AsymptoticBranch
+)
+
+func (b Branch) String() string {
+	switch b {
+	case QuadratureBranch:
+		return "quadrature"
+	case AsymptoticBranch:
+		return "asymptotic"
+	}
+	return "unknown"
+}
+
+// LaplaceIntegral computes the integral of h(x) exp(lambda g(x)) over
+// [a, b], either of which may be infinite. Adaptive quadrature is tried
+// first with at most limit panels, or a default number if limit is not
+// positive; if it fails to reach tol, the result falls back to a two-term
+// Laplace expansion about the maximum of g, or Watson's lemma if the
+// maximum is at a finite endpoint. The returned Branch reports which was
+// used. The expansion is accurate to relative order 1/lambda^2, so the
+// fallback suits large lambda.
+func LaplaceIntegral(h, g Function, a, b, lambda, tol float64, limit int) (float64, Branch) {
+	if a > b {
+		v, branch := LaplaceIntegral(h, g, b, a, lambda, tol, limit)
+		return -v, branch
+	}
+
+	// Work relative to the peak to keep the exponential in range
+	x0, g0, interior := maximize(g, a, b)
+	scale := math.Exp(lambda * g0)
+	f := func(x float64) float64 { return h(x) * math.Exp(lambda*(g(x)-g0)) }
+	if v, ok := boundedIntegral(f, a, b, tol/scale, limit); ok {
+		return v * scale, QuadratureBranch
+	}
+
+	if interior {
+		// Expand in f = -g, which has a minimum at x0
+		f2 := -SecondDerivative(g, x0)
+		f3 := -Derivative(func(x float64) float64 { return SecondDerivative(g, x) }, x0)
+		f4 := -SecondDerivative(func(x float64) float64 { return SecondDerivative(g, x) }, x0)
+		h0 := h(x0)
+		h1 := Derivative(h, x0)
+		h2 := SecondDerivative(h, x0)
+
+		correction := h2/(2*f2) - h1*f3/(2*f2*f2) - h0*f4/(8*f2*f2) + 5*h0*f3*f3/(24*f2*f2*f2)
+		return scale * math.Sqrt(2*math.Pi/(lambda*f2)) * (h0 + correction/lambda), AsymptoticBranch
+	}
+
+	// Integrate by parts twice from the endpoint; g' < 0 at a lower
+	// endpoint and g' > 0 at an upper one
+	g1 := Derivative(g, x0)
+	q := func(x float64) float64 { return h(x) / Derivative(g, x) }
+	ret := h(x0)/(lambda*g1) - Derivative(q, x0)/(lambda*lambda*g1)
+	if x0 == a {
+		ret = -ret
+	}
+
+	return scale * ret, AsymptoticBranch
+}
+
+// OscillatoryIntegral computes the integral of h(x) exp(i lambda g(x))
+// over the finite interval [a, b]. Adaptive quadrature of the real and
+// imaginary parts is tried first with at most limit panels each, or a
+// default number if limit is not positive; if it fails to reach tol, the
+// result falls back to the method of stationary phase, summing the
+// leading contribution of every interior stationary point of g and the
+// leading endpoint terms. The returned Branch reports which was used.
+// The fallback is accurate to order 1/lambda for large lambda, provided
+// the second derivative of g does not vanish at the stationary points.
+func OscillatoryIntegral(h, g Function, a, b, lambda, tol float64, limit int) (complex128, Branch) {
+	if math.IsInf(a, 0) || math.IsInf(b, 0) {
+		return cmplx.NaN(), QuadratureBranch
+	}
+	if a > b {
+		v, branch := OscillatoryIntegral(h, g, b, a, lambda, tol, limit)
+		return -v, branch
+	}
+
+	re := func(x float64) float64 { return h(x) * math.Cos(lambda*g(x)) }
+	im := func(x float64) float64 { return h(x) * math.Sin(lambda*g(x)) }
+	vr, okr := boundedIntegral(re, a, b, tol/2, limit)
+	vi, oki := boundedIntegral(im, a, b, tol/2, limit)
+	if okr && oki {
+		return complex(vr, vi), QuadratureBranch
+	}
+
+	phase := func(x float64) complex128 { return cmplx.Exp(complex(0, lambda*g(x))) }
+	dg := func(x float64) float64 { return Derivative(g, x) }
+
+	ret := complex(0, 0)
+	for _, x0 := range roots(dg, a, b) {
+		g2 := SecondDerivative(g, x0)
+		amp := h(x0) * math.Sqrt(2*math.Pi/(lambda*math.Abs(g2)))
+		ret += complex(amp, 0) * phase(x0) * cmplx.Exp(complex(0, math.Pi/4*sign(g2)))
+	}
+
+	// The endpoint terms [h exp(i lambda g) / (i lambda g')] from a to b
+	endpoint := func(x float64) complex128 {
+		return complex(h(x), 0) * phase(x) / complex(0, lambda*dg(x))
+	}
+	ret += endpoint(b) - endpoint(a)
+
+	return ret, AsymptoticBranch
+}
+
+// Integrates f over [a, b], either of which may be infinite, with at most
+// limit adaptive panels, reporting whether the error estimate met tol.
+func boundedIntegral(f Function, a, b, tol float64, limit int) (float64, bool) {
+	if limit <= 0 {
+		limit = maxPanels
+	}
+
+	g, lo, hi := compactify(f, a, b)
+	done := func(value, total, worst float64) bool { return total <= tol }
+	value, err := adapt(g, []float64{lo, hi}, boolePanel, limit, nil, done).sum()
+
+	return value, err <= tol && !math.IsNaN(value)
+}
+
+// Maps an integral of f over [a, b], with infinite limits allowed, to an
+// equal integral of g over the finite interval [lo, hi]. The images of
+// infinite limits are assigned the value zero, as f must decay there.
+func compactify(f Function, a, b float64) (g Function, lo, hi float64) {
+	guard := func(x, dx float64) float64 {
+		if math.IsInf(x, 0) || math.IsInf(dx, 0) {
+			return 0
+		}
+		return f(x) * dx
+	}
+
+	switch {
+	case math.IsInf(a, -1) && math.IsInf(b, 1):
+		return func(t float64) float64 {
+			t2 := float64(t * t)
+			s := 1 - t2
+			return guard(t/s, (1+t2)/(s*s))
+		}, -1, 1
+	case math.IsInf(b, 1):
+		return func(t float64) float64 {
+			return guard(a+t/(1-t), 1/((1-t)*(1-t)))
+		}, 0, 1
+	case math.IsInf(a, -1):
+		return func(t float64) float64 {
+			return guard(b-t/(1-t), 1/((1-t)*(1-t)))
+		}, 0, 1
+	}
+
+	return f, a, b
+}
+
+// Returns the map from the variable of compactify(f, a, b) back to x.
+func uncompact(a, b float64) func(t float64) float64 {
+	switch {
+	case math.IsInf(a, -1) && math.IsInf(b, 1):
+		return func(t float64) float64 { return t / (1 - t*t) }
+	case math.IsInf(b, 1):
+		return func(t float64) float64 { return a + t/(1-t) }
+	case math.IsInf(a, -1):
+		return func(t float64) float64 { return b - t/(1-t) }
+	}
+	return func(t float64) float64 { return t }
+}
+
+// Locates the maximum of f over [a, b] by sampling at the nodes of the
+// double exponential map, which reach far into infinite intervals, and
+// refining with golden-section search. Reports the maximizer, the
+// maximum, and whether it lies inside the interval rather than at a
+// finite endpoint.
+func maximize(f Function, a, b float64) (float64, float64, bool) {
+	phi := conformalMap(a, b, nativeMath)
+	xs := []float64{}
+	if !math.IsInf(a, 0) {
+		xs = append(xs, a)
+	}
+	for i := 0; i <= asymptoticSamples; i++ {
+		t := -4 + 8*float64(i)/asymptoticSamples
+		if x, _ := phi(t); x > a && x < b && (len(xs) == 0 || x > xs[len(xs)-1]) {
+			xs = append(xs, x)
+		}
+	}
+	if !math.IsInf(b, 0) {
+		xs = append(xs, b)
+	}
+
+	best := 0
+	for i := range xs {
+		if f(xs[i]) > f(xs[best]) {
+			best = i
+		}
+	}
+	if (best == 0 && xs[0] == a) || (best == len(xs)-1 && xs[best] == b) {
+		return xs[best], f(xs[best]), false
+	}
+
+	lo, hi := xs[best], xs[best]
+	if best > 0 {
+		lo = xs[best-1]
+	}
+	if best < len(xs)-1 {
+		hi = xs[best+1]
+	}
+	r := (math.Sqrt(5) - 1) / 2
+	c, d := hi-r*(hi-lo), lo+r*(hi-lo)
+	for i := 0; i < goldenSteps && c < d; i++ {
+		if f(c) > f(d) {
+			hi, d = d, c
+			c = hi - r*(hi-lo)
+		} else {
+			lo, c = c, d
+			d = lo + r*(hi-lo)
+		}
+	}
+
+	x := (lo + hi) / 2
+	return x, f(x), true
+}
+
+// Returns the sign changes of f strictly inside the finite interval
+// [a, b], located by sampling and refined by bisection.
+func roots(f Function, a, b float64) []float64 {
+	var ret []float64
+	L, fL := a, f(a)
+	for i := 1; i <= asymptoticSamples; i++ {
+		R := a + (b-a)*float64(i)/asymptoticSamples
+		fR := f(R)
+		if fR == 0 && i < asymptoticSamples {
+			ret = append(ret, R)
+		} else if fL*fR < 0 {
+			lo, hi, flo := L, R, fL
+			for hi-lo > 1e-15*math.Max(1, math.Abs(lo)) {
+				m := lo + (hi-lo)/2
+				if m <= lo || m >= hi {
+					break
+				}
+				if fm := f(m); fm*flo > 0 {
+					lo, flo = m, fm
+				} else {
+					hi = m
+				}
+			}
+			ret = append(ret, lo+(hi-lo)/2)
+		}
+		L, fL = R, fR
+	}
+
+	return ret
+}
@@ -0,0 +1,68 @@
+package goint
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+)
+
+func TestLaplaceIntegral(t *testing.T) {
+	h := func(x float64) float64 { return 1 }
+	g := func(x float64) float64 { return math.Log(x) - x + 1 }
+
+	// Gamma(lambda + 1) / lambda^(lambda + 1) e^lambda
+	gamma := func(lambda float64) float64 {
+		lg, _ := math.Lgamma(lambda + 1)
+		return math.Exp(lg - (lambda+1)*math.Log(lambda) + lambda)
+	}
+
+	v, branch := LaplaceIntegral(h, g, 0, math.Inf(1), 10, 1e-10, 0)
+	if branch != QuadratureBranch {
+		t.Errorf("expected quadrature, got %v", branch)
+	}
+	if msg, ok := checkValue(v, gamma(10), 1e-9); !ok {
+		t.Error(msg)
+	}
+
+	// Starved of panels, the expansion about x = 1 takes over
+	lambda := 1000.0
+	v, branch = LaplaceIntegral(h, g, 0, math.Inf(1), lambda, 1e-12, 1)
+	if branch != AsymptoticBranch {
+		t.Errorf("expected asymptotic, got %v", branch)
+	}
+	if msg, ok := checkValue(v, gamma(lambda), 1e-6*gamma(lambda)); !ok {
+		t.Error(msg)
+	}
+
+	// Watson's lemma at the endpoint maximum
+	lambda = 1e4
+	h = func(x float64) float64 { return 1 / (1 + x) }
+	g = func(x float64) float64 { return -x }
+	exact, _ := LaplaceIntegral(h, g, 0, 1, lambda, 1e-15, 0)
+	v, branch = LaplaceIntegral(h, g, 0, 1, lambda, 1e-15, 1)
+	if branch != AsymptoticBranch {
+		t.Errorf("expected asymptotic, got %v", branch)
+	}
+	if msg, ok := checkValue(v, exact, 1e-7*exact); !ok {
+		t.Error(msg)
+	}
+}
+
+func TestOscillatoryIntegral(t *testing.T) {
+	h := func(x float64) float64 { return 1 }
+	g := func(x float64) float64 { return x * x }
+	lambda := 1000.0
+
+	exact, branch := OscillatoryIntegral(h, g, -1, 1, lambda, 1e-10, 0)
+	if branch != QuadratureBranch {
+		t.Errorf("expected quadrature, got %v", branch)
+	}
+
+	v, branch := OscillatoryIntegral(h, g, -1, 1, lambda, 1e-10, 1)
+	if branch != AsymptoticBranch {
+		t.Errorf("expected asymptotic, got %v", branch)
+	}
+	if d := cmplx.Abs(v - exact); d > 5e-6 {
+		t.Errorf("stationary phase differs from quadrature by %g", d)
+	}
+}
@@ -0,0 +1,67 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAUC(t *testing.T) {
+	const (
+		h = 1e-12
+		k = .3
+	)
+
+	// A mono-exponential decay sampled sparsely
+	x := []float64{0, 1, 2, 4, 8}
+	y := make([]float64, len(x))
+	for i, xi := range x {
+		y[i] = 10 * math.Exp(-k*xi)
+	}
+
+	// Log-down interpolation is exact for exponential decay
+	want := 10 * (1 - math.Exp(-8*k)) / k
+	if msg, ok := checkValue(AUC(x, y, LinearUpLogDown), want, h); !ok {
+		t.Error(msg)
+	}
+	if AUC(x, y, LinearTrapezoid) <= want {
+		t.Error("linear trapezoids should overestimate a convex decay")
+	}
+
+	wantm := 10 * (1 - math.Exp(-8*k)*(1+8*k)) / (k * k)
+	if msg, ok := checkValue(AUMC(x, y, LinearUpLogDown), wantm, h); !ok {
+		t.Error(msg)
+	}
+	if msg, ok := checkValue(MeanResidenceTime(x, y, LinearUpLogDown), wantm/want, h); !ok {
+		t.Error(msg)
+	}
+
+	want = 10 * (math.Exp(-1.5*k) - math.Exp(-5*k)) / k
+	if msg, ok := checkValue(PartialAUC(x, y, 1.5, 5, LinearUpLogDown), want, h); !ok {
+		t.Error(msg)
+	}
+}
+
+func TestThresholdAUC(t *testing.T) {
+	const (
+		h = 1e-12
+	)
+
+	// A triangle peaking at 4
+	x := []float64{0, 4, 8}
+	y := []float64{0, 4, 0}
+
+	// The area above 2 is a triangle of base 4 and height 2
+	if msg, ok := checkValue(ThresholdAUC(x, y, 2, math.Inf(1), LinearTrapezoid), 4, h); !ok {
+		t.Error(msg)
+	}
+
+	// The band between 1 and 3 is the trapezoid with parallel sides 6
+	// and 2 and height 2
+	if msg, ok := checkValue(ThresholdAUC(x, y, 1, 3, LinearTrapezoid), 8, h); !ok {
+		t.Error(msg)
+	}
+
+	if msg, ok := checkValue(ThresholdAUC(x, y, 0, math.Inf(1), LinearTrapezoid), AUC(x, y, LinearTrapezoid), h); !ok {
+		t.Error(msg)
+	}
+}
@@ -0,0 +1,184 @@
+package goint
+
+import (
+	"math"
+)
+
+// The degree of the Chebyshev interpolant IntegrateAuto probes finite
+// intervals with.
+const autoProbeDegree = 32
+
+// The number of sign changes among the probe values from which
+// IntegrateAuto treats an integrand as oscillatory.
+const autoOscillations = 6
+
+// The strategies IntegrateAuto dispatches to.
+type autoMethod int
+
+const (
+	autoGK autoMethod = iota
+	autoChebyshev
+	autoOscillatory
+	autoTanhSinh
+	autoInfinite
+	autoInfiniteDE
+)
+
+// IntegrateAuto integrates f over [a, b] to within tol, probing f first
+// to choose how, for callers who do not know which integrator suits their
+// integrand:
+//
+//   - On an infinite interval, IntegrateGK, with the double exponential
+//     map of WithInfiniteMap if f is singular at a finite limit or its
+//     tails decay slowly, and the default rational map otherwise.
+//   - On a finite interval where f grows without bound towards a limit,
+//     or is not finite there, TanhSinh.
+//   - Otherwise f is sampled at the 33 Chebyshev points of [a, b]. If the
+//     samples change sign often, f is taken to oscillate and integrated by
+//     IntegrateGK with the 61-point Gauss-Kronrod pair; if their
+//     Chebyshev coefficients decay quickly, by IntegrateChebyshev, which
+//     reuses them; and otherwise by IntegrateGK.
+//
+// The probes are heuristics, and a feature narrower than their spacing
+// goes unseen; the choice then falls back to adaptive IntegrateGK, which
+// finds it if anything does. Result.Evals includes the probes. NaN limits
+// or a NaN or negative tolerance give a NaN estimate and ErrInvalidInput;
+// otherwise the result and error are those of the integrator chosen.
+func IntegrateAuto(f Function, a, b, tol float64) (Result, error) {
+	if math.IsNaN(a) || math.IsNaN(b) || !(tol >= 0) {
+		return Result{Value: math.NaN()}, ErrInvalidInput
+	}
+	if a == b {
+		return Result{}, nil
+	}
+	if a > b {
+		r, err := IntegrateAuto(f, b, a, tol)
+		r.Value = -r.Value
+		return r, err
+	}
+
+	evals := 0
+	counted := func(x float64) float64 {
+		evals++
+		return f(x)
+	}
+	method, probe := chooseMethod(counted, a, b)
+
+	var r Result
+	var err error
+	switch method {
+	case autoInfinite:
+		r, err = IntegrateGK(counted, a, b, tol)
+	case autoInfiniteDE:
+		r, err = IntegrateGK(counted, a, b, tol, WithInfiniteMap(DoubleExponentialMap))
+	case autoTanhSinh:
+		r, err = TanhSinh(counted, a, b, tol)
+	case autoOscillatory:
+		r, err = IntegrateGK(counted, a, b, tol, WithKronrodOrder(61))
+	case autoChebyshev:
+		// The probe values are those of the first interpolants, so
+		// IntegrateChebyshev answers them from a table
+		_, r, err = IntegrateChebyshev(probe.lookup(counted), a, b, tol)
+	default:
+		r, err = IntegrateGK(counted, a, b, tol)
+	}
+
+	r.Evals = evals
+	return r, err
+}
+
+// The values of the integrand sampled by chooseMethod.
+type autoProbe map[float64]float64
+
+// Returns f answering from the probe where it can.
+func (p autoProbe) lookup(f Function) Function {
+	return func(x float64) float64 {
+		if y, ok := p[x]; ok {
+			return y
+		}
+		return f(x)
+	}
+}
+
+// Chooses the strategy of IntegrateAuto for f over [a, b], a < b,
+// returning the values sampled on a finite interval.
+func chooseMethod(f Function, a, b float64) (autoMethod, autoProbe) {
+	if math.IsInf(a, 0) || math.IsInf(b, 0) {
+		// The tails are probed from the finite limit, if there is one, so
+		// that f is only evaluated inside [a, b]
+		from := 0.0
+		if !math.IsInf(a, 0) {
+			from = a
+		} else if !math.IsInf(b, 0) {
+			from = b
+		}
+		slow := (!math.IsInf(a, 0) && singularAt(f, a, 1)) ||
+			(!math.IsInf(b, 0) && singularAt(f, b, -1)) ||
+			(math.IsInf(a, -1) && slowTail(f, from, -1)) ||
+			(math.IsInf(b, 1) && slowTail(f, from, 1))
+		if slow {
+			return autoInfiniteDE, nil
+		}
+		return autoInfinite, nil
+	}
+
+	width := b - a
+	if singularAt(f, a, width) || singularAt(f, b, -width) {
+		return autoTanhSinh, nil
+	}
+
+	probe := autoProbe{}
+	values := make([]float64, autoProbeDegree+1)
+	changes := 0
+	for j := range values {
+		x := chebPoint(a, b, j, autoProbeDegree)
+		values[j] = f(x)
+		probe[x] = values[j]
+		if math.IsNaN(values[j]) || math.IsInf(values[j], 0) {
+			return autoTanhSinh, nil
+		}
+		if j > 0 && values[j]*values[j-1] < 0 {
+			changes++
+		}
+	}
+	if changes >= autoOscillations {
+		return autoOscillatory, probe
+	}
+
+	if d := spectralDecay(chebCoefficients(values)); d.Rate < .5 || d.Plateau {
+		return autoChebyshev, probe
+	}
+	return autoGK, probe
+}
+
+// Reports whether f appears singular at the finite limit x, probing
+// towards it from x + span by steps of 2^-10 in the distance: whether f is
+// not finite at any probe, or its magnitude keeps growing markedly.
+func singularAt(f Function, x, span float64) bool {
+	prev := math.NaN()
+	growing := true
+	for k := 1; k <= 4; k++ {
+		h := span * math.Pow(2, -10*float64(k))
+		y := math.Abs(f(x + h))
+		if math.IsNaN(y) || math.IsInf(y, 0) {
+			return true
+		}
+		if k > 1 {
+			growing = growing && y > 2*prev
+		}
+		prev = y
+	}
+	return growing
+}
+
+// Reports whether the tail of f from x0 towards the infinity with the
+// given sign decays slowly, so that d f(x0 + d) is still appreciable at
+// |d| = 1e6 relative to its size at 1e2.
+func slowTail(f Function, x0, sign float64) bool {
+	near := math.Abs(1e2 * f(x0+sign*1e2))
+	far := math.Abs(1e6 * f(x0+sign*1e6))
+	if math.IsNaN(far) || math.IsInf(far, 0) {
+		return true
+	}
+	return far > 1e-6*near
+}
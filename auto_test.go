@@ -0,0 +1,63 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestIntegrateAuto(t *testing.T) {
+	inf := math.Inf(1)
+	for _, c := range []struct {
+		name    string
+		f       Function
+		a, b    float64
+		method  autoMethod
+		correct float64
+	}{
+		{"smooth", math.Exp, 0, 1, autoChebyshev, math.E - 1},
+		{"kink", func(x float64) float64 { return math.Abs(x - 1./3) }, 0, 1, autoGK, 5. / 18},
+		{"oscillatory", func(x float64) float64 { return math.Sin(50 * x) }, 0, 10, autoOscillatory, (1 - math.Cos(500)) / 50},
+		{"inverse sqrt", func(x float64) float64 { return 1 / math.Sqrt(x) }, 0, 1, autoTanhSinh, 2},
+		{"log", math.Log, 0, 1, autoTanhSinh, -1},
+		{"gaussian", func(x float64) float64 { return math.Exp(-x * x) }, -inf, inf, autoInfinite, math.SqrtPi},
+		{"lorentzian", func(x float64) float64 { return 1 / (1 + x*x) }, -inf, inf, autoInfiniteDE, math.Pi},
+		{"gamma", func(x float64) float64 { return math.Exp(-x) / math.Sqrt(x) }, 0, inf, autoInfiniteDE, math.SqrtPi},
+	} {
+		if method, _ := chooseMethod(c.f, c.a, c.b); method != c.method {
+			t.Errorf("%s: chose %d, want %d", c.name, method, c.method)
+		}
+		r, err := IntegrateAuto(c.f, c.a, c.b, 1e-9)
+		if err != nil {
+			t.Errorf("%s: %v", c.name, err)
+		}
+		if msg, ok := checkValue(r.Value, c.correct, 1e-8); !ok {
+			t.Errorf("%s: %s", c.name, msg)
+		}
+	}
+
+	// The Chebyshev probe is reused
+	calls := 0
+	r, _ := IntegrateAuto(func(x float64) float64 {
+		calls++
+		return math.Exp(x)
+	}, 1, 0, 1e-12)
+	if msg, ok := checkValue(r.Value, 1-math.E, 1e-12); !ok || r.Evals != calls || calls > 33+12 {
+		t.Errorf("%s in %d evaluations, %d reported", msg, calls, r.Evals)
+	}
+
+	// The tail is probed from the finite limit, not the origin
+	outside := 0
+	r, _ = IntegrateAuto(func(x float64) float64 {
+		if x < 1e7 {
+			outside++
+		}
+		return 1 / (x * x)
+	}, 1e7, inf, 1e-15)
+	if msg, ok := checkValue(r.Value, 1e-7, 1e-15); !ok || outside > 0 {
+		t.Errorf("%s with %d evaluations outside the interval", msg, outside)
+	}
+
+	if r, err := IntegrateAuto(math.Exp, 0, math.NaN(), 1e-9); err != ErrInvalidInput || !math.IsNaN(r.Value) {
+		t.Errorf("got %g, %v for a NaN limit", r.Value, err)
+	}
+}
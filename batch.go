@@ -0,0 +1,128 @@
+package goint
+
+import (
+	"math"
+	"sort"
+)
+
+// A BatchFunction evaluates a function at every point of xs, storing the
+// values in the corresponding elements of ys. Integrands backed by tables,
+// search trees or remote services can amortize their per-call costs over
+// the points of a whole panel.
+type BatchFunction func(xs, ys []float64)
+
+// An EvaluationOrder determines the order in which the points of a batch
+// are passed to a BatchFunction.
+type EvaluationOrder int
+
+const (
+	// RuleOrder passes the points in the order the rule requests them,
+	// which for a Gauss-Kronrod rule alternates about the panel center.
+	RuleOrder EvaluationOrder = iota
+
+	// AscendingOrder sorts the points into increasing order, which keeps
+	// lookups into piecewise tables moving in one direction and makes
+	// branches on x predictable.
+	AscendingOrder
+
+	// DescendingOrder sorts the points into decreasing order.
+	DescendingOrder
+)
+
+// WithEvaluationOrder sets the order in which IntegrateBatch passes the
+// points of each batch to its BatchFunction. The default is RuleOrder.
+// Other integrators ignore it.
+func WithEvaluationOrder(order EvaluationOrder) Option {
+	return func(c *config) { c.evalOrder = order }
+}
+
+// IntegrateBatch integrates f over [a, b] to within tol as IntegrateGK
+// does, but evaluates f a panel at a time: each panel's points, mapped
+// through the substitution of an infinite interval, are passed to f in a
+// single call, ordered as WithEvaluationOrder selects. The points of each
+// call are distinct. Result.Evals counts the points passed to f.
+// WithMonotone, WithEvaluationBudget and WithRateLimit are not supported
+// and give ErrInvalidOption, as does an unknown EvaluationOrder.
+func IntegrateBatch(f BatchFunction, a, b, tol float64, opts ...Option) (Result, error) {
+	cfg := newConfig(opts)
+	if cfg.monotone || cfg.meter() != nil || cfg.evalOrder < RuleOrder || cfg.evalOrder > DescendingOrder {
+		return Result{Value: math.NaN()}, ErrInvalidOption
+	}
+	cfg.batch = &batcher{f: f, order: cfg.evalOrder, table: make(map[float64]float64)}
+
+	r, err := integrateGK(cfg.batch.eval, a, b, tol, cfg, nil)
+	r.Evals = cfg.batch.evals
+	return r, err
+}
+
+// A batcher turns a BatchFunction into a Function for a panel rule by
+// running the rule twice: once recording the points it asks for, which
+// are then evaluated in one batch, and once answering from the results.
+type batcher struct {
+	f     BatchFunction
+	order EvaluationOrder
+
+	recording bool
+	pending   []float64
+	table     map[float64]float64
+	evals     int
+}
+
+// Evaluates the function at x, recording x instead while a panel's points
+// are being collected. Points missing from the batch, as when the rule
+// depends on the values it sees, are evaluated on their own.
+func (b *batcher) eval(x float64) float64 {
+	if b.recording {
+		b.pending = append(b.pending, x)
+		return 0
+	}
+	if y, ok := b.table[x]; ok {
+		return y
+	}
+	ys := []float64{0}
+	b.f([]float64{x}, ys)
+	b.evals++
+	return ys[0]
+}
+
+// Returns rule with the function evaluations of each panel batched.
+func (b *batcher) rule(rule panelRule) panelRule {
+	return func(g Function, lo, hi float64) (float64, float64) {
+		b.recording, b.pending = true, b.pending[:0]
+		rule(g, lo, hi)
+		b.recording = false
+		b.flush()
+
+		return rule(g, lo, hi)
+	}
+}
+
+// Evaluates the recorded points in one batch, replacing the table.
+func (b *batcher) flush() {
+	for x := range b.table {
+		delete(b.table, x)
+	}
+
+	xs := make([]float64, 0, len(b.pending))
+	for _, x := range b.pending {
+		if _, ok := b.table[x]; !ok {
+			b.table[x] = 0
+			xs = append(xs, x)
+		}
+	}
+	switch b.order {
+	case AscendingOrder:
+		sort.Float64s(xs)
+	case DescendingOrder:
+		sort.Sort(sort.Reverse(sort.Float64Slice(xs)))
+	}
+
+	ys := make([]float64, len(xs))
+	if len(xs) > 0 {
+		b.f(xs, ys)
+	}
+	b.evals += len(xs)
+	for i, x := range xs {
+		b.table[x] = ys[i]
+	}
+}
@@ -0,0 +1,50 @@
+package goint
+
+import (
+	"math"
+	"sort"
+	"testing"
+)
+
+func TestIntegrateBatch(t *testing.T) {
+	const tol = 1e-10
+
+	calls, points := 0, 0
+	sorted := true
+	f := func(xs, ys []float64) {
+		calls++
+		points += len(xs)
+		sorted = sorted && sort.Float64sAreSorted(xs)
+		for i, x := range xs {
+			ys[i] = math.Exp(-x * x)
+		}
+	}
+
+	plain, _ := IntegrateGK(func(x float64) float64 { return math.Exp(-x * x) }, -3, 5, tol)
+	r, err := IntegrateBatch(f, -3, 5, tol, WithEvaluationOrder(AscendingOrder))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Value != plain.Value || r.Evals != plain.Evals {
+		t.Errorf("batched %v with %d evaluations, plain %v with %d", r.Value, r.Evals, plain.Value, plain.Evals)
+	}
+	if !sorted {
+		t.Error("points were not passed in ascending order")
+	}
+	if points != r.Evals || calls > r.Evals/15 {
+		t.Errorf("%d points in %d calls for %d evaluations", points, calls, r.Evals)
+	}
+
+	// An infinite interval passes the mapped points
+	r, err = IntegrateBatch(f, math.Inf(-1), math.Inf(1), tol, WithEvaluationOrder(DescendingOrder))
+	if err != nil {
+		t.Error(err)
+	}
+	if msg, ok := checkValue(r.Value, math.Sqrt(math.Pi), tol); !ok {
+		t.Error(msg)
+	}
+
+	if _, err := IntegrateBatch(f, 0, 1, tol, WithEvaluationOrder(EvaluationOrder(5))); err != ErrInvalidOption {
+		t.Errorf("expected ErrInvalidOption, got %v", err)
+	}
+}
@@ -0,0 +1,52 @@
+package goint
+
+import (
+	"math"
+)
+
+// IntegrateAtLeast reports whether the integral of f over [a, b] is at
+// least bound, refining with IntegrateGK only until the estimate and its
+// error place the integral clearly on one side of bound, or until the
+// error falls below tol and the estimate decides. This is far cheaper than
+// a full integration when the integral is not close to bound. The verdict
+// is as reliable as the error estimates; if neither condition can be met,
+// the verdict of the best estimate is returned with ErrNotConverged or
+// ErrMemoryLimit.
+func IntegrateAtLeast(f Function, a, b, bound, tol float64, opts ...Option) (bool, error) {
+	stop := func(value, err float64) bool {
+		return value-err >= bound || value+err < bound
+	}
+
+	r, err := integrateGK(f, a, b, tol, newConfig(opts), stop)
+	return r.Value >= bound, err
+}
+
+// IntegrateAtMost reports whether the integral of f over [a, b] is at
+// most bound, in the manner of IntegrateAtLeast.
+func IntegrateAtMost(f Function, a, b, bound, tol float64, opts ...Option) (bool, error) {
+	neg := func(x float64) float64 { return -f(x) }
+	return IntegrateAtLeast(neg, a, b, -bound, tol, opts...)
+}
+
+// CompareIntegrals reports which of the integrals of f and g over [a, b]
+// is larger: 1 if that of f is, -1 if that of g is, and 0 if they agree
+// to within tol. Only the difference f - g is integrated, and refinement
+// stops as soon as its sign is settled by its error estimate, which is far
+// cheaper than computing both integrals accurately. If the sign cannot be
+// settled, the sign of the best estimate is returned with ErrNotConverged
+// or ErrMemoryLimit.
+func CompareIntegrals(f, g Function, a, b, tol float64, opts ...Option) (int, error) {
+	diff := func(x float64) float64 { return f(x) - g(x) }
+	stop := func(value, err float64) bool { return math.Abs(value) > err }
+
+	r, err := integrateGK(diff, a, b, tol, newConfig(opts), stop)
+	switch {
+	case err == nil && math.Abs(r.Value) <= r.Error:
+		return 0, nil
+	case r.Value > 0:
+		return 1, err
+	case r.Value < 0:
+		return -1, err
+	}
+	return 0, err
+}
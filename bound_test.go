@@ -0,0 +1,77 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestIntegrateAtLeast(t *testing.T) {
+	// The upper tail of the standard normal beyond 5 is about 2.87e-7
+	evals := 0
+	pdf := func(x float64) float64 {
+		evals++
+		return math.Exp(-x*x/2) / math.Sqrt(2*math.Pi)
+	}
+	inf := math.Inf(1)
+
+	cases := []struct {
+		bound       float64
+		least, most bool
+	}{
+		{1e-6, false, true},
+		{1e-7, true, false},
+		{2.8665e-7, true, false},
+		{2.8667e-7, false, true},
+	}
+
+	for _, c := range cases {
+		least, err := IntegrateAtLeast(pdf, 5, inf, c.bound, 1e-14)
+		if err != nil || least != c.least {
+			t.Errorf("at least %g: got %v, %v", c.bound, least, err)
+		}
+		most, err := IntegrateAtMost(pdf, 5, inf, c.bound, 1e-14)
+		if err != nil || most != c.most {
+			t.Errorf("at most %g: got %v, %v", c.bound, most, err)
+		}
+	}
+
+	// A clear verdict costs less than the full integral
+	evals = 0
+	IntegrateGK(pdf, 5, inf, 1e-14)
+	full := evals
+	evals = 0
+	IntegrateAtLeast(pdf, 5, inf, 1e-6, 1e-14)
+	if evals >= full {
+		t.Errorf("%d evaluations against %d for the full integral", evals, full)
+	}
+
+	// Reversed limits negate the integral
+	if least, _ := IntegrateAtLeast(pdf, inf, 5, -1e-6, 1e-14); !least {
+		t.Error("expected the reversed integral to exceed -1e-6")
+	}
+}
+
+func TestCompareIntegrals(t *testing.T) {
+	// x^2 and x^2 + 1e-6 sin(pi x)^2 on [0, 1] differ by 5e-7
+	f := func(x float64) float64 { return x * x }
+	g := func(x float64) float64 {
+		s := math.Sin(math.Pi * x)
+		return x*x + 1e-6*s*s
+	}
+
+	cases := []struct {
+		f, g Function
+		want int
+	}{
+		{f, g, -1},
+		{g, f, 1},
+		{f, f, 0},
+		{math.Exp, f, 1},
+	}
+	for i, c := range cases {
+		got, err := CompareIntegrals(c.f, c.g, 0, 1, 1e-12)
+		if err != nil || got != c.want {
+			t.Errorf("case %d: got %d, %v, want %d", i, got, err, c.want)
+		}
+	}
+}
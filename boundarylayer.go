@@ -0,0 +1,69 @@
+package goint
+
+import (
+	"math"
+)
+
+// The extent of a boundary layer in multiples of its width, beyond which
+// an exp(-x/width) term has fallen below the unit roundoff.
+const boundaryLayerSpan = 40
+
+// WithBoundaryLayer declares that the integrand may have a boundary layer
+// of the given width at either end of a finite interval, such as a term
+// exp(-(x-a)/width) with width far below the length of the interval,
+// which IntegrateGK would otherwise miss entirely: no node of the initial
+// panel falls inside the layer, so the estimate converges confidently to
+// the wrong value. The 40 widths at each end are stretched by the
+// substitution x = a + width (e^u - 1), and its reflection at b, which
+// makes the layer occupy a unit range of u, and the rest of the interval
+// is integrated unchanged. The option is ignored on infinite intervals
+// and for a width that is not positive and finite.
+func WithBoundaryLayer(width float64) Option {
+	return func(c *config) { c.layer = width }
+}
+
+// Reports whether the boundary layer substitution applies to [a, b].
+func (c *config) layered(a, b float64) bool {
+	return c.layer > 0 && !math.IsInf(c.layer, 0) && !math.IsInf(a, 0) && !math.IsInf(b, 0)
+}
+
+// Returns the substitution of WithBoundaryLayer for a layer of width w at
+// each end of the finite interval [a, b], giving x and dx/du for u in
+// [lo, hi], and the values of u at which the stretched pieces meet the
+// linear middle.
+func layerTransform(a, b, w float64) (phi func(u float64) (float64, float64), lo, hi float64, joints [2]float64) {
+	span := math.Min(boundaryLayerSpan*w, (b-a)/2)
+	s := math.Log1p(span / w)
+	mid := b - a - 2*span
+	joints = [2]float64{s, s + mid}
+	hi = 2*s + mid
+
+	return func(u float64) (float64, float64) {
+		switch {
+		case u < joints[0]:
+			return math.Min(a+w*math.Expm1(u), b), w * math.Exp(u)
+		case u > joints[1]:
+			return math.Max(b-w*math.Expm1(hi-u), a), w * math.Exp(hi-u)
+		}
+		return a + span + (u - s), 1
+	}, 0, hi, joints
+}
+
+// Returns f under the boundary layer substitution for [a, b], with the
+// limits of the new variable.
+func layerCompactify(f Function, a, b, w float64) (Function, float64, float64) {
+	phi, lo, hi, _ := layerTransform(a, b, w)
+	return func(u float64) float64 {
+		x, dx := phi(u)
+		return f(x) * dx
+	}, lo, hi
+}
+
+// Returns the map from the variable of layerCompactify back to x.
+func layerUncompact(a, b, w float64) func(u float64) float64 {
+	phi, _, _, _ := layerTransform(a, b, w)
+	return func(u float64) float64 {
+		x, _ := phi(u)
+		return x
+	}
+}
@@ -0,0 +1,49 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBoundaryLayer(t *testing.T) {
+	const width = 1e-8
+	lower := func(x float64) float64 { return math.Exp(-x/width) + x }
+	upper := func(x float64) float64 { return math.Exp((x-1)/width) + x }
+	correct := width + .5
+
+	// Without the option the layer is never sampled
+	if r, _ := IntegrateGK(lower, 0, 1, 1e-12); math.Abs(r.Value-correct) < width/2 {
+		t.Errorf("layer resolved without the option: %g", r.Value)
+	}
+
+	for _, f := range []Function{lower, upper} {
+		r, err := IntegrateGK(f, 0, 1, 1e-14, WithBoundaryLayer(width))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if msg, ok := checkValue(r.Value, correct, 1e-14); !ok {
+			t.Error(msg)
+		}
+	}
+
+	// Breakpoints are mapped through the substitution
+	step := func(x float64) float64 {
+		if x < .3 {
+			return math.Exp(-x / width)
+		}
+		return 1
+	}
+	r, err := IntegrateGK(step, 0, 1, 1e-14, WithBoundaryLayer(width), WithBreakpoints(.3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg, ok := checkValue(r.Value, width+.7, 1e-14); !ok {
+		t.Error(msg)
+	}
+
+	// A layer wider than half the interval covers it
+	r, _ = IntegrateGK(math.Exp, 0, 1, 1e-14, WithBoundaryLayer(1))
+	if msg, ok := checkValue(r.Value, math.E-1, 1e-14); !ok {
+		t.Error(msg)
+	}
+}
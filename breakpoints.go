@@ -0,0 +1,94 @@
+package goint
+
+import (
+	"math"
+	"sort"
+)
+
+// Integrates f over [a, b] to within err, first splitting the interval at
+// every breakpoint strictly inside it so that no piece straddles a kink or
+// jump of f. The tolerance is shared between the pieces in proportion to
+// their widths, or evenly if the interval is infinite.
+func integrateBreakpoints(f Function, a, b, err float64, breaks []float64) float64 {
+	points := []float64{a}
+	inside := append([]float64(nil), breaks...)
+	sort.Float64s(inside)
+	for _, x := range inside {
+		if x > a && x < b && x > points[len(points)-1] {
+			points = append(points, x)
+		}
+	}
+	points = append(points, b)
+
+	pieces := float64(len(points) - 1)
+	ret := 0.0
+	for i := 1; i < len(points); i++ {
+		piece_err := err / pieces
+		if !math.IsInf(b-a, 0) {
+			piece_err = err * (points[i] - points[i-1]) / (b - a)
+		}
+		ret += Integrate(f, points[i-1], points[i], piece_err)
+	}
+
+	return ret
+}
+
+// WithBreakpoints splits the interval of integration at each of xs that
+// lies strictly inside it before any refinement, so that no panel
+// straddles a known discontinuity, kink or interior singularity of the
+// integrand, which the adaptive rule would otherwise resolve only by
+// bisecting towards it many times. The integrand is never evaluated at the
+// breakpoints themselves. On an infinite interval they are mapped through
+// the substitution that compactifies it.
+func WithBreakpoints(xs ...float64) Option {
+	return func(c *config) { c.breakpoints = append(c.breakpoints, xs...) }
+}
+
+// Returns the initial partition of [lo, hi], the image of [a, b] under the
+// configured substitution, split at the images of the breakpoints and at
+// the edges of any boundary layers.
+func (c *config) initialPoints(a, b, lo, hi float64) []float64 {
+	points := []float64{lo}
+	layered := c.layered(a, b)
+	if len(c.breakpoints) > 0 || layered {
+		inside := make([]float64, 0, len(c.breakpoints)+2)
+		x := c.uncompact(a, b)
+		for _, p := range c.breakpoints {
+			if p > a && p < b {
+				inside = append(inside, invertMonotone(x, p, lo, hi))
+			}
+		}
+		if layered {
+			_, _, _, joints := layerTransform(a, b, c.layer)
+			inside = append(inside, joints[:]...)
+		}
+		sort.Float64s(inside)
+		for _, t := range inside {
+			if t > points[len(points)-1] && t < hi {
+				points = append(points, t)
+			}
+		}
+	}
+
+	return append(points, hi)
+}
+
+// Returns the t in [lo, hi] at which the monotone function x takes the
+// value p, by bisection.
+func invertMonotone(x func(t float64) float64, p, lo, hi float64) float64 {
+	if x(lo+(hi-lo)/4) > x(hi-(hi-lo)/4) {
+		// Decreasing: invert -x instead
+		return invertMonotone(func(t float64) float64 { return -x(t) }, -p, lo, hi)
+	}
+	for {
+		m := lo + (hi-lo)/2
+		if m <= lo || m >= hi {
+			return m
+		}
+		if x(m) < p {
+			lo = m
+		} else {
+			hi = m
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGaussHermite(t *testing.T) {
+	nodes, weights := gaussHermite(10)
+
+	// The rule integrates x^(2k) exp(-x^2) exactly for 2k < 20
+	correct := math.SqrtPi
+	for k := 0; k < 10; k++ {
+		got := 0.0
+		for i, x := range nodes {
+			got += weights[i] * math.Pow(x, float64(2*k))
+		}
+		if msg, ok := checkValue(got, correct, 1e-9*correct); !ok {
+			t.Errorf("moment %d: %s", 2*k, msg)
+		}
+		correct *= float64(2*k+1) / 2
+	}
+}
+
+func TestGaussianBroaden(t *testing.T) {
+	const (
+		h     = 1e-10
+		sigma = .7
+	)
+
+	// Broadening a quadratic adds sigma^2 to it
+	sq := func(x float64) float64 { return x * x }
+	g := GaussianBroaden(sq, sigma)
+	for _, x := range []float64{-2, 0, 1.5} {
+		if msg, ok := checkValue(g(x), x*x+sigma*sigma, h); !ok {
+			t.Error(msg)
+		}
+	}
+
+	// Broadening a Gaussian of width s gives one of width sqrt(s^2 + sigma^2)
+	normal := func(s float64) Function {
+		return func(x float64) float64 {
+			return math.Exp(-x*x/(2*s*s)) / (s * math.Sqrt(2*math.Pi))
+		}
+	}
+	xs := []float64{-1, 0, .3, 2}
+	got := GaussianBroadenGrid(normal(1), sigma, xs)
+	want := normal(math.Hypot(1, sigma))
+	for i, x := range xs {
+		if msg, ok := checkValue(got[i], want(x), h); !ok {
+			t.Error(msg)
+		}
+	}
+}
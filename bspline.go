@@ -0,0 +1,198 @@
+package goint
+
+import (
+	"math"
+	"sort"
+)
+
+// A BSpline is a basis of B-spline functions of some degree on a knot
+// vector, optionally made rational by NURBS weights, with the integrals
+// against it needed by isogeometric analysis: the load vector of a
+// function and the mass matrix of the basis. Each is assembled span by
+// span with a Gauss-Legendre rule just large enough to be exact, as the
+// basis functions are polynomials on each knot span.
+type BSpline struct {
+	knots   []float64
+	degree  int
+	weights []float64
+}
+
+// NewBSpline returns the B-spline basis of degree p on knots, which are
+// copied, or nil unless p >= 0, the knots are finite and nondecreasing,
+// there are at least p + 2 of them and the domain, from knots[p] to
+// knots[len(knots)-p-1], is not empty.
+func NewBSpline(knots []float64, p int) *BSpline {
+	n := len(knots) - p - 1
+	if p < 0 || n < 1 {
+		return nil
+	}
+	for i, t := range knots {
+		if math.IsNaN(t) || math.IsInf(t, 0) || i > 0 && t < knots[i-1] {
+			return nil
+		}
+	}
+	if !(knots[n] > knots[p]) {
+		return nil
+	}
+
+	return &BSpline{knots: append([]float64(nil), knots...), degree: p}
+}
+
+// NewNURBS returns the rational basis of degree p on knots with the given
+// weights, one for each basis function, R_i = w_i N_i / sum of w_j N_j.
+// It is nil if NewBSpline would be or the weights are not all finite and
+// positive.
+func NewNURBS(knots []float64, p int, weights []float64) *BSpline {
+	s := NewBSpline(knots, p)
+	if s == nil || len(weights) != s.Len() {
+		return nil
+	}
+	for _, w := range weights {
+		if !(w > 0) || math.IsInf(w, 0) {
+			return nil
+		}
+	}
+
+	s.weights = append([]float64(nil), weights...)
+	return s
+}
+
+// Len returns the number of basis functions.
+func (s *BSpline) Len() int {
+	return len(s.knots) - s.degree - 1
+}
+
+// Domain returns the interval on which the basis is defined.
+func (s *BSpline) Domain() (a, b float64) {
+	return s.knots[s.degree], s.knots[s.Len()]
+}
+
+// Basis returns the values at x of the degree + 1 basis functions that
+// may be nonzero there, which are those numbered first onwards. Points
+// outside the domain are clamped to it.
+func (s *BSpline) Basis(x float64) (first int, values []float64) {
+	a, b := s.Domain()
+	x = math.Max(a, math.Min(x, b))
+	span := s.span(x)
+	values = make([]float64, s.degree+1)
+	s.basis(span, x, values)
+	return span - s.degree, values
+}
+
+// Load returns the integrals over the domain of f times each basis
+// function. They are exact, up to rounding, when f is a polynomial of
+// degree at most q on each knot span and the basis is not rational; with
+// NURBS weights the integrand is rational, and a rule of 2(degree + 1)
+// more points per span is used, which is accurate but not exact.
+func (s *BSpline) Load(f Function, q int) []float64 {
+	load := make([]float64, s.Len())
+	values := make([]float64, s.degree+1)
+	s.spans(s.points(s.degree+max(q, 0)), func(span int, x, w float64) {
+		s.basis(span, x, values)
+		w *= f(x)
+		for k, v := range values {
+			load[span-s.degree+k] += w * v
+		}
+	})
+	return load
+}
+
+// Mass returns the symmetric matrix of the integrals over the domain of
+// the products of pairs of basis functions, exact up to rounding for a
+// basis that is not rational and, as for Load, accurate for NURBS.
+func (s *BSpline) Mass() [][]float64 {
+	mass := newMatrix(s.Len(), s.Len())
+	values := make([]float64, s.degree+1)
+	s.spans(s.points(2*s.degree), func(span int, x, w float64) {
+		s.basis(span, x, values)
+		first := span - s.degree
+		for i, u := range values {
+			for j, v := range values[i:] {
+				mass[first+i][first+i+j] += w * u * v
+			}
+		}
+	})
+	for i := range mass {
+		for j := 0; j < i; j++ {
+			mass[i][j] = mass[j][i]
+		}
+	}
+	return mass
+}
+
+// Returns the number of Gauss-Legendre points integrating a polynomial of
+// the given degree exactly on a span, with 2(degree + 1) more for a
+// rational basis.
+func (s *BSpline) points(degree int) int {
+	n := degree/2 + 1
+	if s.weights != nil {
+		n += 2 * (s.degree + 1)
+	}
+	return n
+}
+
+// Calls visit with every node and weight of the n-point Gauss-Legendre
+// rule mapped onto each nonempty knot span of the domain, along with the
+// index of the span's left knot.
+func (s *BSpline) spans(n int, visit func(span int, x, w float64)) {
+	nodes, weights := gaussLegendre(n)
+	for span := s.degree; span < s.Len(); span++ {
+		lo, hi := s.knots[span], s.knots[span+1]
+		if !(hi > lo) {
+			continue
+		}
+		xs, ws := mapRule(nodes, weights, lo, hi)
+		for i, x := range xs {
+			visit(span, x, ws[i])
+		}
+	}
+}
+
+// Returns the index of the nonempty knot span of the domain containing x,
+// the last for x at or beyond the end of the domain.
+func (s *BSpline) span(x float64) int {
+	n := s.Len()
+	if x >= s.knots[n] {
+		x = s.knots[n]
+	}
+	i := sort.Search(n-s.degree, func(k int) bool { return s.knots[s.degree+k+1] > x }) + s.degree
+	if i >= n {
+		i = n - 1
+	}
+	for i > s.degree && !(s.knots[i+1] > s.knots[i]) {
+		i--
+	}
+	return i
+}
+
+// Sets values to the basis functions span - degree, ..., span at x by the
+// Cox-de Boor recurrence, applying the NURBS weights if there are any.
+func (s *BSpline) basis(span int, x float64, values []float64) {
+	p := s.degree
+	left := make([]float64, p+1)
+	right := make([]float64, p+1)
+	values[0] = 1
+	for j := 1; j <= p; j++ {
+		left[j] = x - s.knots[span+1-j]
+		right[j] = s.knots[span+j] - x
+		saved := 0.0
+		for r := 0; r < j; r++ {
+			t := values[r] / (right[r+1] + left[j-r])
+			values[r] = saved + right[r+1]*t
+			saved = left[j-r] * t
+		}
+		values[j] = saved
+	}
+
+	if s.weights == nil {
+		return
+	}
+	sum := 0.0
+	for k := range values {
+		values[k] *= s.weights[span-p+k]
+		sum += values[k]
+	}
+	for k := range values {
+		values[k] /= sum
+	}
+}
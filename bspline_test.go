@@ -0,0 +1,96 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBSpline(t *testing.T) {
+	knots := []float64{0, 0, 0, 1, 2, 2, 3, 3, 3}
+	s := NewBSpline(knots, 2)
+	if s.Len() != 6 {
+		t.Fatalf("got %d basis functions", s.Len())
+	}
+
+	// The integral of N_i is the width of its support over p + 1
+	ones := s.Load(func(x float64) float64 { return 1 }, 0)
+	for i, got := range ones {
+		if msg, ok := checkValue(got, (knots[i+3]-knots[i])/3, 1e-15); !ok {
+			t.Errorf("N_%d: %s", i, msg)
+		}
+	}
+
+	// Loads of a cubic are exact, and the mass matrix reproduces the
+	// loads of one through the partition of unity
+	cube := func(x float64) float64 { return x * x * x }
+	load := s.Load(cube, 3)
+	mass := s.Mass()
+	for i := range load {
+		basis := func(x float64) float64 {
+			first, values := s.Basis(x)
+			if i < first || i > first+2 {
+				return 0
+			}
+			return values[i-first]
+		}
+		r, _ := IntegrateGK(func(x float64) float64 { return cube(x) * basis(x) }, 0, 3, 1e-12, WithBreakpoints(1, 2))
+		if msg, ok := checkValue(load[i], r.Value, 1e-13); !ok {
+			t.Errorf("load %d: %s", i, msg)
+		}
+
+		row := 0.0
+		for j := range mass[i] {
+			row += mass[i][j]
+			if mass[i][j] != mass[j][i] {
+				t.Errorf("mass %d, %d: asymmetric", i, j)
+			}
+		}
+		if msg, ok := checkValue(row, ones[i], 1e-15); !ok {
+			t.Errorf("mass row %d: %s", i, msg)
+		}
+	}
+
+	// Points outside the domain are clamped to its ends
+	for _, x := range []float64{-1, 4} {
+		inside := math.Max(0, math.Min(x, 3))
+		first, values := s.Basis(x)
+		wantFirst, want := s.Basis(inside)
+		for k := range want {
+			if first != wantFirst || values[k] != want[k] {
+				t.Errorf("Basis(%v) = %d %v, want %d %v", x, first, values, wantFirst, want)
+				break
+			}
+		}
+	}
+
+	for _, bad := range []*BSpline{
+		NewBSpline([]float64{0, 1}, 1),
+		NewBSpline([]float64{0, 0, 1, 0}, 1),
+		NewBSpline([]float64{0, 0, 0, 0}, 1),
+		NewNURBS([]float64{0, 0, 1, 1}, 1, []float64{1, -1}),
+	} {
+		if bad != nil {
+			t.Error("accepted an invalid basis")
+		}
+	}
+}
+
+func TestNURBS(t *testing.T) {
+	// The quarter circle, whose basis still sums to one
+	s := NewNURBS([]float64{0, 0, 0, 1, 1, 1}, 2, []float64{1, math.Sqrt2 / 2, 1})
+	_, values := s.Basis(.3)
+	if msg, ok := checkValue(values[0]+values[1]+values[2], 1, 1e-15); !ok {
+		t.Error(msg)
+	}
+
+	load := s.Load(math.Exp, 0)
+	for i := range load {
+		r, _ := IntegrateGK(func(x float64) float64 {
+			_, values := s.Basis(x)
+			return math.Exp(x) * values[i]
+		}, 0, 1, 1e-13)
+		if msg, ok := checkValue(load[i], r.Value, 1e-9); !ok {
+			t.Errorf("load %d: %s", i, msg)
+		}
+	}
+}
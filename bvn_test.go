@@ -0,0 +1,99 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBivariateNormalCDF(t *testing.T) {
+	const (
+		h = 1e-12
+	)
+
+	// Orthant probabilities at the origin are known in closed form
+	for _, rho := range []float64{-.95, -.5, 0, .2, .6, .8, .99} {
+		want := .25 + math.Asin(rho)/(2*math.Pi)
+		if msg, ok := checkValue(BivariateNormalCDF(0, 0, rho), want, h); !ok {
+			t.Errorf("rho = %g: %s", rho, msg)
+		}
+		if msg, ok := checkValue(BivariateTCDF(0, 0, rho, 3), want, h); !ok {
+			t.Errorf("t, rho = %g: %s", rho, msg)
+		}
+	}
+
+	// Compare with conditioning on X and integrating
+	for _, rho := range []float64{-.8, .4, .95} {
+		hh, kk := .3, -.7
+		s := math.Sqrt(1 - rho*rho)
+		f := func(x float64) float64 {
+			return math.Exp(-x*x/2) / math.Sqrt(2*math.Pi) * normalCDF((kk-rho*x)/s)
+		}
+		want := Integrate(f, math.Inf(-1), hh, 1e-12)
+		if msg, ok := checkValue(BivariateNormalCDF(hh, kk, rho), want, 1e-9); !ok {
+			t.Errorf("rho = %g: %s", rho, msg)
+		}
+	}
+
+	for _, rho := range []float64{-1.5, 1 + 1e-9, math.NaN()} {
+		if p := BivariateNormalCDF(.3, -.7, rho); !math.IsNaN(p) {
+			t.Errorf("rho = %g gave %g", rho, p)
+		}
+		if p := BivariateTCDF(.3, -.7, rho, 3); !math.IsNaN(p) {
+			t.Errorf("t, rho = %g gave %g", rho, p)
+		}
+	}
+}
+
+func TestBivariateTCDF(t *testing.T) {
+	// T = Z / S, so the t probability is the expectation over S of a
+	// bivariate normal probability
+	for _, nu := range []int{1, 2, 4, 5} {
+		hh, kk, rho := .5, -.2, .3
+		got := BivariateTCDF(hh, kk, rho, nu)
+
+		rn := float64(nu)
+		lg, _ := math.Lgamma(rn / 2)
+		f := func(u float64) float64 {
+			if u >= 1 {
+				return 0
+			}
+			s := u / (1 - u)
+			d := chiDensity(s, rn, math.Log(2)+(rn/2)*math.Log(rn/2)-lg)
+			return d * BivariateNormalCDF(hh*s, kk*s, rho) / ((1 - u) * (1 - u))
+		}
+		want := Integrate(f, 0, 1, 1e-10)
+		if msg, ok := checkValue(got, want, 1e-7); !ok {
+			t.Errorf("nu = %d: %s", nu, msg)
+		}
+	}
+
+	if msg, ok := checkValue(StudentTCDF(1, 1), .75, 1e-15); !ok {
+		t.Error(msg)
+	}
+}
+
+func TestTrivariateNormalCDF(t *testing.T) {
+	// With independent components the probability factors
+	h3 := [3]float64{.1, -.4, 1.2}
+	want := normalCDF(.1) * normalCDF(-.4) * normalCDF(1.2)
+	if msg, ok := checkValue(TrivariateNormalCDF(h3, 0, 0, 0), want, 1e-12); !ok {
+		t.Error(msg)
+	}
+
+	// The orthant probability at zero is 1/8 + (asin r12 + asin r13 +
+	// asin r23) / (4 pi)
+	r12, r13, r23 := .5, .3, .4
+	want = .125 + (math.Asin(r12)+math.Asin(r13)+math.Asin(r23))/(4*math.Pi)
+	if msg, ok := checkValue(TrivariateNormalCDF([3]float64{}, r12, r13, r23), want, 1e-10); !ok {
+		t.Error(msg)
+	}
+
+	// The same holds for any elliptical distribution
+	if msg, ok := checkValue(TrivariateTCDF([3]float64{}, r12, r13, r23, 4, 1e-8), want, 1e-7); !ok {
+		t.Error(msg)
+	}
+
+	if !math.IsNaN(TrivariateNormalCDF(h3, .9, -.9, .9)) {
+		t.Error("expected NaN for an indefinite correlation matrix")
+	}
+}
@@ -0,0 +1,71 @@
+package goint
+
+import (
+	"math"
+)
+
+// IntegrateChebyshev integrates f over the finite interval [a, b] to within
+// tol by fitting it with a Chebyshev interpolant and integrating that
+// exactly, returning the interpolant for reuse along with the result. The
+// interpolant is sampled at the nested Chebyshev points for degrees 8,
+// 16, 32, ..., up to 65536, as in ClenshawCurtis, and grown until its
+// coefficients have decayed: the error estimate is the size of the last
+// coefficients, as Chebyshev.Decay reports it, scaled by the largest and
+// by the length of the interval, which for smooth f is reliable where
+// the difference of successive estimates is not. The returned
+// interpolant is chopped to the coefficients above the rounding level.
+// Growth stops early at a rounding plateau, as more points cannot then
+// help. Infinite or NaN limits or a NaN or negative tolerance give a nil
+// interpolant, a NaN estimate and ErrInvalidInput; if the tolerance is
+// not met, the last interpolant and its estimate are returned with
+// ErrNotConverged.
+func IntegrateChebyshev(f Function, a, b, tol float64) (*Chebyshev, Result, error) {
+	if math.IsNaN(a) || math.IsNaN(b) || math.IsInf(a, 0) || math.IsInf(b, 0) || !(tol >= 0) {
+		return nil, Result{Value: math.NaN()}, ErrInvalidInput
+	}
+	if a == b {
+		return &Chebyshev{a, b, []float64{0}}, Result{}, nil
+	}
+
+	n := 8
+	values := make([]float64, n+1)
+	for j := range values {
+		values[j] = f(chebPoint(a, b, j, n))
+	}
+
+	for {
+		coefs := chebCoefficients(values)
+		scale := 0.0
+		for _, c := range coefs {
+			scale = math.Max(scale, math.Abs(c))
+		}
+		d := spectralDecay(coefs)
+
+		r := Result{Error: d.Tail * scale * math.Abs(b-a), Evals: n + 1, Panels: 1}
+		if done := r.Error <= tol || d.Plateau; done || 2*n > maxClenshawCurtis || math.IsNaN(scale) {
+			r.Value = chebIntegral(coefs, a, b)
+
+			// Chop to the coefficients above the rounding level
+			m := len(coefs)
+			for m > 1 && math.Abs(coefs[m-1]) <= 10*epsilon*scale {
+				m--
+			}
+			c := &Chebyshev{a, b, coefs[:m:m]}
+			if !(r.Error <= tol) {
+				return c, r, ErrNotConverged
+			}
+			return c, r, nil
+		}
+
+		// The old points are the even ones of the new grid
+		finer := make([]float64, 2*n+1)
+		for j := range finer {
+			if j%2 == 0 {
+				finer[j] = values[j/2]
+			} else {
+				finer[j] = f(chebPoint(a, b, j, 2*n))
+			}
+		}
+		n, values = 2*n, finer
+	}
+}
@@ -0,0 +1,47 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestIntegrateChebyshev(t *testing.T) {
+	c, r, err := IntegrateChebyshev(math.Exp, -1, 2, 1e-12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	correct := math.Exp(2) - math.Exp(-1)
+	if msg, ok := checkValue(r.Value, correct, 1e-13); !ok {
+		t.Error(msg)
+	}
+	if math.Abs(r.Value-correct) > r.Error || r.Evals > 33 {
+		t.Errorf("error %g with estimate %g in %d evaluations", math.Abs(r.Value-correct), r.Error, r.Evals)
+	}
+
+	// The interpolant is reusable and chopped
+	if msg, ok := checkValue(c.Eval(.3), math.Exp(.3), 1e-14); !ok {
+		t.Error(msg)
+	}
+	if msg, ok := checkValue(c.Integral(), r.Value, 0); !ok {
+		t.Error(msg)
+	}
+	if n := len(c.Coefficients()); n > 25 {
+		t.Errorf("%d coefficients", n)
+	}
+
+	// A polynomial is fitted exactly by the first interpolant
+	c, r, _ = IntegrateChebyshev(func(x float64) float64 { return x*x*x - x }, 0, 2, 1e-14)
+	if msg, ok := checkValue(r.Value, 2, 1e-14); !ok || r.Evals != 9 || len(c.Coefficients()) != 4 {
+		t.Errorf("%s in %d evaluations with %d coefficients", msg, r.Evals, len(c.Coefficients()))
+	}
+
+	// A kink is not resolved within the budget
+	_, r, err = IntegrateChebyshev(math.Abs, -1, 2, 1e-14)
+	if err != ErrNotConverged || math.Abs(r.Value-2.5) > r.Error {
+		t.Errorf("got %g with estimate %g, %v", r.Value, r.Error, err)
+	}
+
+	if c, r, err := IntegrateChebyshev(math.Exp, 0, math.Inf(1), 1e-8); c != nil || !math.IsNaN(r.Value) || err != ErrInvalidInput {
+		t.Errorf("got %g, %v for an infinite limit", r.Value, err)
+	}
+}
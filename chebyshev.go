@@ -0,0 +1,123 @@
+package goint
+
+import (
+	"math"
+)
+
+// A Chebyshev is a polynomial on the finite interval [a, b] represented
+// by its coefficients in the Chebyshev basis,
+//
+//	p(x) = sum over k of c[k] T_k(t),  t = (2x - a - b) / (b - a).
+//
+// Its method value Eval is a Function.
+type Chebyshev struct {
+	a, b  float64
+	coefs []float64
+}
+
+// NewChebyshev returns the polynomial of degree n interpolating f at the
+// n + 1 Chebyshev extreme points of [a, b].
+func NewChebyshev(f Function, a, b float64, n int) *Chebyshev {
+	values := make([]float64, n+1)
+	for j := range values {
+		values[j] = f(chebPoint(a, b, j, n))
+	}
+
+	return &Chebyshev{a, b, chebCoefficients(values)}
+}
+
+// Returns the j-th of the n + 1 Chebyshev extreme points of [a, b],
+// counting down from b.
+func chebPoint(a, b float64, j, n int) float64 {
+	if n == 0 {
+		return (a + b) / 2
+	}
+	t := math.Cos(math.Pi * float64(j) / float64(n))
+	return (a+b)/2 + (b-a)/2*t
+}
+
+// Converts values at the Chebyshev extreme points cos(j pi / n) into
+// Chebyshev coefficients with a discrete cosine transform, computed by FFT
+// when n is a power of two and directly otherwise.
+func chebCoefficients(values []float64) []float64 {
+	n := len(values) - 1
+	if isPowerOfTwo(n) {
+		return chebCoefficientsFFT(values)
+	}
+
+	coefs := make([]float64, n+1)
+	if n == 0 {
+		coefs[0] = values[0]
+		return coefs
+	}
+
+	for k := 0; k <= n; k++ {
+		sum := 0.0
+		for j := 0; j <= n; j++ {
+			term := values[j] * math.Cos(math.Pi*float64(j*k)/float64(n))
+			if j == 0 || j == n {
+				term /= 2
+			}
+			sum += term
+		}
+		coefs[k] = 2 * sum / float64(n)
+	}
+	coefs[0] /= 2
+	coefs[n] /= 2
+
+	return coefs
+}
+
+// Domain returns the interval on which the polynomial is defined.
+func (c *Chebyshev) Domain() (float64, float64) {
+	return c.a, c.b
+}
+
+// Coefficients returns a copy of the Chebyshev coefficients.
+func (c *Chebyshev) Coefficients() []float64 {
+	return append([]float64(nil), c.coefs...)
+}
+
+// Eval evaluates the polynomial at x with Clenshaw's recurrence.
+func (c *Chebyshev) Eval(x float64) float64 {
+	t := (2*x - c.a - c.b) / (c.b - c.a)
+	b1, b2 := 0.0, 0.0
+	for k := len(c.coefs) - 1; k >= 1; k-- {
+		b1, b2 = 2*t*b1-b2+c.coefs[k], b1
+	}
+
+	return t*b1 - b2 + c.coefs[0]
+}
+
+// Derivative returns the derivative of the polynomial, computed exactly
+// from its coefficients. This is the spectrally accurate way to
+// differentiate a smooth function that has been approximated by
+// NewChebyshev.
+func (c *Chebyshev) Derivative() *Chebyshev {
+	n := len(c.coefs) - 1
+	if n == 0 {
+		return &Chebyshev{c.a, c.b, []float64{0}}
+	}
+
+	d := make([]float64, n+1)
+	for k := n; k >= 1; k-- {
+		d[k-1] = 2 * float64(k) * c.coefs[k]
+		if k+1 <= n-1 {
+			d[k-1] += d[k+1]
+		}
+	}
+	d[0] /= 2
+
+	scale := 2 / (c.b - c.a)
+	for k := range d {
+		d[k] *= scale
+	}
+
+	return &Chebyshev{c.a, c.b, d[:n]}
+}
+
+// Integral returns the integral of the polynomial over its domain,
+// computed exactly from its coefficients.
+func (c *Chebyshev) Integral() float64 {
+	return chebIntegral(c.coefs, c.a, c.b)
+}
@@ -0,0 +1,60 @@
+package goint
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// IntegrateCircle integrates the 2 pi-periodic function f over one period
+// with the trapezoid rule on n equally spaced nodes, which converges
+// geometrically in n for smooth periodic f. The result is NaN if n < 1.
+func IntegrateCircle(f func(theta float64) float64, n int) float64 {
+	if n < 1 {
+		return math.NaN()
+	}
+
+	sum := 0.0
+	for k := 0; k < n; k++ {
+		sum += f(2 * math.Pi * float64(k) / float64(n))
+	}
+	return 2 * math.Pi * sum / float64(n)
+}
+
+// CircularConvolution returns the circular convolution of the 2 pi-periodic
+// functions f and g,
+//
+//	(f * g)(theta) = integral over one period of f(phi) g(theta - phi) dphi,
+//
+// at the n nodes theta = 2 pi k / n. Both functions are sampled at the
+// nodes and the convolution is computed by FFT, with the same spectral
+// accuracy as IntegrateCircle. n must be a power of two; otherwise the
+// result is nil.
+func CircularConvolution(f, g func(theta float64) float64, n int) []float64 {
+	if !isPowerOfTwo(n) {
+		return nil
+	}
+
+	fs := make([]complex128, n)
+	gs := make([]complex128, n)
+	for k := range fs {
+		theta := 2 * math.Pi * float64(k) / float64(n)
+		fs[k] = complex(f(theta), 0)
+		gs[k] = complex(g(theta), 0)
+	}
+	fft(fs)
+	fft(gs)
+
+	// Multiply the spectra and invert by transforming the conjugate
+	for k := range fs {
+		fs[k] = cmplx.Conj(fs[k] * gs[k])
+	}
+	fft(fs)
+
+	ret := make([]float64, n)
+	scale := 2 * math.Pi / float64(n) / float64(n)
+	for k := range ret {
+		ret[k] = real(fs[k]) * scale
+	}
+
+	return ret
+}
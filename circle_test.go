@@ -0,0 +1,37 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestIntegrateCircle(t *testing.T) {
+	// The integral of exp(cos theta) over a period is 2 pi I0(1)
+	f := func(theta float64) float64 { return math.Exp(math.Cos(theta)) }
+	correct := 2 * math.Pi * 1.2660658777520084
+	if msg, ok := checkValue(IntegrateCircle(f, 16), correct, 1e-13); !ok {
+		t.Error(msg)
+	}
+	if !math.IsNaN(IntegrateCircle(f, 0)) {
+		t.Error("expected NaN for no nodes")
+	}
+}
+
+func TestCircularConvolution(t *testing.T) {
+	// cos * cos = pi cos, and cos * 1 = 0
+	conv := CircularConvolution(math.Cos, math.Cos, 16)
+	flat := CircularConvolution(math.Cos, func(float64) float64 { return 1 }, 16)
+	for k := range conv {
+		theta := 2 * math.Pi * float64(k) / 16
+		if msg, ok := checkValue(conv[k], math.Pi*math.Cos(theta), 1e-13); !ok {
+			t.Errorf("node %d: %s", k, msg)
+		}
+		if msg, ok := checkValue(flat[k], 0, 1e-13); !ok {
+			t.Errorf("node %d: %s", k, msg)
+		}
+	}
+
+	if CircularConvolution(math.Cos, math.Cos, 12) != nil {
+		t.Error("expected nil for a length that is not a power of two")
+	}
+}
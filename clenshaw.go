@@ -0,0 +1,84 @@
+package goint
+
+import (
+	"math"
+)
+
+// The most Chebyshev points ClenshawCurtis uses, less one.
+const maxClenshawCurtis = 1 << 16
+
+// ClenshawCurtis integrates f over the finite interval [a, b] with
+// Clenshaw-Curtis quadrature, returning the estimate and an error
+// estimate. f is sampled at the Chebyshev points cos(j pi / n), mapped to
+// [a, b], for n = 8, 16, 32, ...; the points are nested, so each doubling
+// evaluates f only at the new half of them. The Chebyshev coefficients of
+// each interpolant are found by FFT and integrated exactly, and doubling
+// stops once successive estimates agree to within tol. For smooth f this
+// needs far fewer evaluations than refinement by Boole's rule. Doubling
+// also stops once the coefficients have decayed to a rounding plateau, as
+// Chebyshev.Decay reports, since more points cannot then reduce the
+// error, which is estimated from the size of the last coefficients
+// instead, even if it exceeds tol. If the tolerance is not met
+// with 65537 points, the error estimate is infinite. If either bound is
+// infinite, both results are NaN.
+func ClenshawCurtis(f Function, a, b, tol float64) (float64, float64) {
+	if math.IsInf(a, 0) || math.IsInf(b, 0) {
+		return math.NaN(), math.NaN()
+	}
+	if a == b {
+		return 0, 0
+	}
+
+	n := 8
+	values := make([]float64, n+1)
+	for j := range values {
+		values[j] = f(chebPoint(a, b, j, n))
+	}
+	value := clenshawCurtisSum(values, a, b)
+
+	for 2*n <= maxClenshawCurtis {
+		// The old points are the even ones of the new grid
+		finer := make([]float64, 2*n+1)
+		for j := range finer {
+			if j%2 == 0 {
+				finer[j] = values[j/2]
+			} else {
+				finer[j] = f(chebPoint(a, b, j, 2*n))
+			}
+		}
+		n, values = 2*n, finer
+
+		coefs := chebCoefficients(values)
+		refined := chebIntegral(coefs, a, b)
+		diff := math.Abs(refined - value)
+		value = refined
+		if diff <= tol {
+			return value, diff
+		}
+		if d := spectralDecay(coefs); d.Plateau {
+			scale := 0.0
+			for _, c := range coefs {
+				scale = math.Max(scale, math.Abs(c))
+			}
+			return value, math.Min(diff, d.Tail*scale*math.Abs(b-a))
+		}
+	}
+
+	return value, math.Inf(1)
+}
+
+// Integrates the Chebyshev interpolant of values, given at the extreme
+// points of [a, b], exactly.
+func clenshawCurtisSum(values []float64, a, b float64) float64 {
+	return chebIntegral(chebCoefficients(values), a, b)
+}
+
+// Integrates the polynomial with Chebyshev coefficients coefs on [a, b]
+// exactly.
+func chebIntegral(coefs []float64, a, b float64) float64 {
+	sum := 0.0
+	for k := 0; k < len(coefs); k += 2 {
+		sum += coefs[k] * 2 / (1 - float64(k*k))
+	}
+	return sum * (b - a) / 2
+}
@@ -0,0 +1,85 @@
+package goint
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+)
+
+func TestFFT(t *testing.T) {
+	x := make([]complex128, 16)
+	for i := range x {
+		x[i] = complex(math.Sin(float64(i)), float64(i%3))
+	}
+	y := append([]complex128(nil), x...)
+	fft(y)
+
+	for k := range x {
+		sum := complex(0, 0)
+		for j := range x {
+			sum += x[j] * cmplx.Rect(1, -2*math.Pi*float64(j*k)/16)
+		}
+		if cmplx.Abs(sum-y[k]) > 1e-12 {
+			t.Errorf("coefficient %d: got %v, want %v", k, y[k], sum)
+		}
+	}
+}
+
+func TestChebCoefficientsFFT(t *testing.T) {
+	values := make([]float64, 33)
+	for j := range values {
+		values[j] = math.Exp(math.Cos(math.Pi * float64(j) / 32))
+	}
+
+	fast := chebCoefficientsFFT(values)
+	n := len(values) - 1
+	for k := range fast {
+		sum := 0.0
+		for j, v := range values {
+			term := v * math.Cos(math.Pi*float64(j*k)/float64(n))
+			if j == 0 || j == n {
+				term /= 2
+			}
+			sum += term
+		}
+		direct := 2 * sum / float64(n)
+		if k == 0 || k == n {
+			direct /= 2
+		}
+		if math.Abs(fast[k]-direct) > 1e-14 {
+			t.Errorf("coefficient %d: got %v, want %v", k, fast[k], direct)
+		}
+	}
+}
+
+func TestClenshawCurtis(t *testing.T) {
+	const tol = 1e-12
+
+	cases := []struct {
+		f       Function
+		a, b    float64
+		correct float64
+	}{
+		{math.Exp, 0, 1, math.E - 1},
+		{math.Exp, 1, 0, 1 - math.E},
+		{func(x float64) float64 { return 1 / (1 + 25*x*x) }, -1, 1, .4 * math.Atan(5)},
+		{func(x float64) float64 { return math.Cos(30 * x) }, 0, 2, math.Sin(60) / 30},
+	}
+
+	for i, c := range cases {
+		v, err := ClenshawCurtis(c.f, c.a, c.b, tol)
+		if msg, ok := checkValue(v, c.correct, 10*tol); !ok {
+			t.Errorf("case %d: %s", i, msg)
+		}
+		if err > tol {
+			t.Errorf("case %d: error estimate %g", i, err)
+		}
+	}
+
+	// Smooth integrands need few evaluations
+	evals := 0
+	ClenshawCurtis(func(x float64) float64 { evals++; return math.Exp(x) }, 0, 1, tol)
+	if evals > 33 {
+		t.Errorf("%d evaluations for exp", evals)
+	}
+}
@@ -0,0 +1,69 @@
+package goint
+
+import (
+	"math"
+)
+
+// Trapezoid integrates f over the finite interval [a, b] with the
+// composite trapezoid rule on n equal panels, using n + 1 evaluations.
+// The result is NaN if n < 1 or either bound is infinite.
+func Trapezoid(f Function, a, b float64, n int) float64 {
+	if n < 1 || math.IsInf(a, 0) || math.IsInf(b, 0) {
+		return math.NaN()
+	}
+
+	h := (b - a) / float64(n)
+	sum := (f(a) + f(b)) / 2
+	for i := 1; i < n; i++ {
+		sum += f(a + float64(i)*h)
+	}
+
+	return h * sum
+}
+
+// Simpson integrates f over the finite interval [a, b] with composite
+// Simpson's rule on n equal panels, each with its midpoint, using 2n + 1
+// evaluations. The result is NaN if n < 1 or either bound is infinite.
+func Simpson(f Function, a, b float64, n int) float64 {
+	if n < 1 || math.IsInf(a, 0) || math.IsInf(b, 0) {
+		return math.NaN()
+	}
+
+	h := (b - a) / float64(2*n)
+	sum := f(a) + f(b)
+	for i := 1; i < 2*n; i++ {
+		if i%2 == 1 {
+			sum += 4 * f(a+float64(i)*h)
+		} else {
+			sum += 2 * f(a+float64(i)*h)
+		}
+	}
+
+	return h * sum / 3
+}
+
+// Boole integrates f over the finite interval [a, b] with composite
+// Boole's rule, the panel formula used by Integrate, on n equal panels,
+// using 4n + 1 evaluations. The result is NaN if n < 1 or either bound is
+// infinite.
+func Boole(f Function, a, b float64, n int) float64 {
+	if n < 1 || math.IsInf(a, 0) || math.IsInf(b, 0) {
+		return math.NaN()
+	}
+
+	h := (b - a) / float64(4*n)
+	sum := 7 * (f(a) + f(b))
+	for i := 1; i < 4*n; i++ {
+		x := a + float64(i)*h
+		switch i % 4 {
+		case 0:
+			sum += 14 * f(x)
+		case 2:
+			sum += 12 * f(x)
+		default:
+			sum += 32 * f(x)
+		}
+	}
+
+	return 2 * h * sum / 45
+}
@@ -0,0 +1,48 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestComposite(t *testing.T) {
+	cube := func(x float64) float64 { return x * x * x }
+	quintic := func(x float64) float64 { return x * x * x * x * x }
+
+	// Each rule is exact up to its degree
+	if msg, ok := checkValue(Trapezoid(func(x float64) float64 { return 2*x + 1 }, 0, 3, 1), 12, 1e-14); !ok {
+		t.Error(msg)
+	}
+	if msg, ok := checkValue(Simpson(cube, 0, 2, 1), 4, 1e-14); !ok {
+		t.Error(msg)
+	}
+	if msg, ok := checkValue(Boole(quintic, 0, 2, 1), 64.0/6, 1e-13); !ok {
+		t.Error(msg)
+	}
+
+	// and agrees with the unexported panel formula
+	if msg, ok := checkValue(Boole(math.Exp, 0, 1, 1), boolesrule(math.Exp, 0, 1), 1e-15); !ok {
+		t.Error(msg)
+	}
+
+	// Halving the step reduces the error by 2^order
+	rules := []struct {
+		rule  func(Function, float64, float64, int) float64
+		order float64
+	}{
+		{Trapezoid, 2},
+		{Simpson, 4},
+		{Boole, 6},
+	}
+	for i, r := range rules {
+		e1 := math.Abs(r.rule(math.Exp, 0, 1, 4) - (math.E - 1))
+		e2 := math.Abs(r.rule(math.Exp, 0, 1, 8) - (math.E - 1))
+		if ratio := math.Log2(e1 / e2); math.Abs(ratio-r.order) > .1 {
+			t.Errorf("rule %d converged at order %g, want %g", i, ratio, r.order)
+		}
+	}
+
+	if !math.IsNaN(Simpson(math.Exp, 0, 1, 0)) {
+		t.Error("expected NaN for no panels")
+	}
+}
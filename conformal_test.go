@@ -0,0 +1,47 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestIntegrateConformal(t *testing.T) {
+	const tol = 1e-10
+
+	inf := math.Inf(1)
+	cases := []struct {
+		f       Function
+		a, b, d float64
+		correct float64
+	}{
+		{math.Exp, 0, 1, math.Pi / 2, math.E - 1},
+		{func(x float64) float64 { return 1 / (1 + 25*x*x) }, -1, 1, .3, .4 * math.Atan(5)},
+		{func(x float64) float64 { return 1 / math.Sqrt(x) }, 0, 1, math.Pi / 2, 2},
+		{func(x float64) float64 { return math.Log(x) }, 0, 1, math.Pi / 2, -1},
+		{func(x float64) float64 { return math.Exp(-x * x) }, -inf, inf, math.Pi / 4, math.Sqrt(math.Pi)},
+		{func(x float64) float64 { return 1 / (1 + x*x) }, 0, inf, math.Pi / 4, math.Pi / 2},
+		{func(x float64) float64 { return math.Exp(x) }, -inf, 0, math.Pi / 4, 1},
+		{math.Exp, 1, 0, math.Pi / 2, 1 - math.E},
+	}
+
+	for i, c := range cases {
+		got := IntegrateConformal(c.f, c.a, c.b, c.d, tol)
+		if msg, ok := checkValue(got, c.correct, 10*tol); !ok {
+			t.Errorf("case %d: %s", i, msg)
+		}
+	}
+
+	if !math.IsNaN(IntegrateConformal(math.Exp, 0, 1, 2, tol)) {
+		t.Error("expected NaN for an invalid strip width")
+	}
+
+	// A zero tolerance refines as far as allowed and stops
+	if msg, ok := checkValue(IntegrateConformal(math.Exp, 0, 1, math.Pi/2, 0), math.E-1, 1e-12); !ok {
+		t.Errorf("tol = 0: %s", msg)
+	}
+	for _, bad := range []float64{-1, math.NaN()} {
+		if !math.IsNaN(IntegrateConformal(math.Exp, 0, 1, math.Pi/2, bad)) {
+			t.Errorf("expected NaN for tol = %g", bad)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package goint
+
+// A ComplexFunction is an analytic function of a complex variable.
+type ComplexFunction func(z complex128) complex128
+
+// A Path is a parametrized contour z(t) in the complex plane, along with
+// its derivative dz/dt.
+type Path func(t float64) (z, dz complex128)
+
+// ContourIntegral integrates f along the contour path for t in [a, b], to
+// within err in each of the real and imaginary parts.
+func ContourIntegral(f ComplexFunction, path Path, a, b, err float64) complex128 {
+	g := func(t float64) complex128 {
+		z, dz := path(t)
+		return f(z) * dz
+	}
+
+	re := Integrate(func(t float64) float64 { return real(g(t)) }, a, b, err)
+	im := Integrate(func(t float64) float64 { return imag(g(t)) }, a, b, err)
+
+	return complex(re, im)
+}
+
+// LinePath returns the straight path from z0 to z1 for t in [0, 1].
+func LinePath(z0, z1 complex128) Path {
+	return func(t float64) (complex128, complex128) {
+		return z0 + complex(t, 0)*(z1-z0), z1 - z0
+	}
+}
@@ -0,0 +1,24 @@
+package goint
+
+// Overlap computes the overlap integral of f and g, the integral of
+// f(x) g(x) over [a, b], to within tol.
+func Overlap(f, g Function, a, b, tol float64) float64 {
+	return Integrate(func(x float64) float64 { return f(x) * g(x) }, a, b, tol)
+}
+
+// CrossCorrelation computes the integral of f(x) g(x + tau) over [a, b] for
+// each tau in lags, each to within tol. Evaluations of f and g are cached
+// and shared between lags: f is sampled at the same abscissae for every
+// lag, and g is only re-evaluated at shifted points it has not yet seen.
+func CrossCorrelation(f, g Function, a, b float64, lags []float64, tol float64) []float64 {
+	f = memoize(f)
+	g = memoize(g)
+
+	ret := make([]float64, len(lags))
+	for i, tau := range lags {
+		shifted := func(x float64) float64 { return f(x) * g(x+tau) }
+		ret[i] = Integrate(shifted, a, b, tol)
+	}
+
+	return ret
+}
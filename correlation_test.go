@@ -0,0 +1,55 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestOverlap(t *testing.T) {
+	const (
+		h = 1e-8
+	)
+
+	// sin and cos are orthogonal over a full period
+	if msg, ok := checkValue(Overlap(math.Sin, math.Cos, 0, 2*math.Pi, h), 0, h); !ok {
+		t.Error(msg)
+	}
+
+	// The overlap of sin with itself over a period is pi
+	if msg, ok := checkValue(Overlap(math.Sin, math.Sin, 0, 2*math.Pi, h), math.Pi, h); !ok {
+		t.Error(msg)
+	}
+}
+
+func TestCrossCorrelation(t *testing.T) {
+	const (
+		h = 1e-8
+	)
+
+	calls := 0
+	f := func(x float64) float64 {
+		calls++
+		return x
+	}
+	one := func(x float64) float64 { return 1 }
+
+	// The integral of x (x + tau) over [0, 1] is 1/3 + tau/2
+	lags := []float64{0, .25, .5, 1}
+	got := CrossCorrelation(f, func(x float64) float64 { return x }, 0, 1, lags, h)
+	for i, tau := range lags {
+		if msg, ok := checkValue(got[i], 1./3+tau/2, h); !ok {
+			t.Error(msg)
+		}
+	}
+
+	// Evaluations of f should be shared between lags
+	calls = 0
+	CrossCorrelation(f, one, 0, 1, lags[:1], h)
+	single := calls
+
+	calls = 0
+	CrossCorrelation(f, one, 0, 1, lags, h)
+	if calls != single {
+		t.Errorf("f was evaluated %d times for %d lags, want %d", calls, len(lags), single)
+	}
+}
@@ -0,0 +1,53 @@
+package goint
+
+import (
+	"math"
+)
+
+// CosineTransform computes the integral of f(x) cos(omega x) over the
+// finite interval [a, b] to within err. The interval is split at every
+// half-period of the cosine so that each call to Integrate sees at most a
+// single sign change of the oscillating factor. An infinite or NaN limit
+// gives NaN; IntegrateFourier handles infinite tails.
+func CosineTransform(f Function, a, b, omega, err float64) float64 {
+	g := func(x float64) float64 { return f(x) * math.Cos(omega*x) }
+	return integrateHalfPeriods(g, a, b, omega, err)
+}
+
+// SineTransform is the sine counterpart of CosineTransform.
+func SineTransform(f Function, a, b, omega, err float64) float64 {
+	g := func(x float64) float64 { return f(x) * math.Sin(omega*x) }
+	return integrateHalfPeriods(g, a, b, omega, err)
+}
+
+// Integrates g over [a, b] piecewise, breaking at multiples of pi/omega.
+// The tolerance is shared evenly between the pieces. Both limits must be
+// finite, as there would otherwise be infinitely many pieces.
+func integrateHalfPeriods(g Function, a, b, omega, err float64) float64 {
+	if math.IsInf(a, 0) || math.IsInf(b, 0) || math.IsNaN(a) || math.IsNaN(b) {
+		return math.NaN()
+	}
+	omega = math.Abs(omega)
+	if omega == 0 || a == b {
+		return Integrate(g, a, b, err)
+	}
+
+	half := math.Pi / omega
+	pieces := math.Ceil((b - a) / half)
+	if pieces <= 1 {
+		return Integrate(g, a, b, err)
+	}
+
+	ret := 0.0
+	pieceErr := err / pieces
+	L := a
+	for k := math.Floor(a/half) + 1; L < b; k++ {
+		R := math.Min(k*half, b)
+		if R > L {
+			ret += Integrate(g, L, R, pieceErr)
+		}
+		L = R
+	}
+
+	return ret
+}
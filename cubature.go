@@ -0,0 +1,288 @@
+package goint
+
+import (
+	"math"
+	"runtime"
+	"sort"
+)
+
+// The Genz-Malik generator distances, on the cube [-1, 1]^n.
+var (
+	gmLambda2 = math.Sqrt(9.0 / 70)
+	gmLambda3 = math.Sqrt(9.0 / 10)
+	gmLambda5 = math.Sqrt(9.0 / 19)
+)
+
+// A region is a hyperrectangle of an adaptive cubature, with its integral
+// estimate, error estimate, and the axis along which it should be split.
+type region struct {
+	center, half []float64
+	value, err   float64
+	axis         int
+}
+
+// IntegrateCubature integrates f over the hyperrectangle with corners
+// lower and upper to within tol, using the Genz-Malik degree 7 rule with
+// an embedded degree 5 rule for the error estimate. Each round bisects
+// the regions contributing most to the error, each along the axis where f
+// is least smooth, and the new regions are evaluated in parallel by a
+// work-stealing pool of goroutines, GOMAXPROCS of them unless set by
+// WithWorkers. f must therefore be safe for concurrent use. Regions are
+// selected, stored and summed in an order that does not depend on
+// scheduling, so results are identical from run to run and for any number
+// of workers. If the tolerance cannot be met, the best estimate is
+// returned along with ErrNotConverged, or ErrMemoryLimit if the memory
+// allowance ran out first. The corners must have the same, nonzero
+// dimension and finite coordinates, and tol must not be negative or NaN;
+// otherwise NaN is returned with ErrInvalidInput.
+func IntegrateCubature(f MultiFunction, lower, upper []float64, tol float64, opts ...Option) (Result, error) {
+	cfg := newConfig(opts)
+	return cubature(f, lower, upper, tol, cfg.workers, cfg)
+}
+
+// Implements IntegrateCubature with the given number of workers.
+func cubature(f MultiFunction, lower, upper []float64, tol float64, workers int, cfg *config) (Result, error) {
+	dim := len(lower)
+	if dim == 0 || len(upper) != dim {
+		return Result{Value: math.NaN()}, ErrInvalidInput
+	}
+	for i := range lower {
+		if math.IsNaN(lower[i]) || math.IsNaN(upper[i]) || math.IsInf(lower[i], 0) || math.IsInf(upper[i], 0) {
+			return Result{Value: math.NaN()}, ErrInvalidInput
+		}
+	}
+	if !(tol >= 0) {
+		return Result{Value: math.NaN()}, ErrInvalidInput
+	}
+
+	// Each region holds two slice headers, three words and two coordinate
+	// slices, and the selection of regions to split indexes it
+	limit := cfg.panelLimit(2*24+3*8+2*8*dim+8, maxPanels)
+	var mergedValue, mergedErr float64
+	outOfMemory := false
+
+	first := region{center: make([]float64, dim), half: make([]float64, dim)}
+	sign := 1.0
+	for i := range lower {
+		first.center[i] = (lower[i] + upper[i]) / 2
+		first.half[i] = math.Abs(upper[i]-lower[i]) / 2
+		if upper[i] < lower[i] {
+			sign = -sign
+		}
+	}
+
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	buffers := make([][]float64, workers)
+	for w := range buffers {
+		buffers[w] = make([]float64, dim)
+	}
+
+	points := genzMalikPoints(dim)
+	regions := []region{first}
+	genzMalik(f, &regions[0], buffers[0])
+	evals := points
+
+	errs := []float64{}
+	totalErr := func() float64 {
+		if !cfg.strict {
+			total := mergedErr
+			for _, r := range regions {
+				total += r.err
+			}
+			return total
+		}
+		errs = errs[:0]
+		for _, r := range regions {
+			errs = append(errs, r.err)
+		}
+		return mergedErr + pairwiseSum(errs)
+	}
+
+	for {
+		if total := totalErr(); total <= tol || math.IsNaN(total) {
+			break
+		}
+
+		if len(regions) >= limit {
+			if cfg.memoryPolicy != MemoryMerge || mergedErr > tol/2 || len(regions) < 2 {
+				outOfMemory = limit < maxPanels
+				break
+			}
+			regions = mergeRegions(regions, &mergedValue, &mergedErr)
+		}
+
+		// Split the largest errors, ties broken by position
+		order := make([]int, len(regions))
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(i, j int) bool {
+			return regions[order[i]].err > regions[order[j]].err
+		})
+		split := order[:1+len(order)/4]
+		if len(regions)+len(split) > limit {
+			split = split[:limit-len(regions)]
+		}
+		sort.Ints(split)
+
+		// Each parent keeps its slot for the lower half and appends the
+		// upper half, so the layout is fixed before any work starts
+		base := len(regions)
+		for range split {
+			regions = append(regions, region{})
+		}
+		for k, i := range split {
+			lo, hi := bisect(regions[i])
+			regions[i], regions[base+k] = lo, hi
+		}
+
+		parallelFor(2*len(split), workers, func(t, w int) {
+			if t%2 == 0 {
+				genzMalik(f, &regions[split[t/2]], buffers[w])
+			} else {
+				genzMalik(f, &regions[base+t/2], buffers[w])
+			}
+		})
+		evals += 2 * len(split) * points
+	}
+
+	r := Result{Value: mergedValue, Error: mergedErr, Evals: evals, Panels: len(regions)}
+	if cfg.strict {
+		values := make([]float64, len(regions))
+		for i, reg := range regions {
+			values[i] = reg.value
+		}
+		r.Value += pairwiseSum(values)
+		r.Error = totalErr()
+	} else {
+		for _, reg := range regions {
+			r.Value += reg.value
+			r.Error += reg.err
+		}
+	}
+	r.Value *= sign
+	if !(r.Error <= tol) {
+		if outOfMemory {
+			return r, ErrMemoryLimit
+		}
+		return r, ErrNotConverged
+	}
+
+	return r, nil
+}
+
+// Folds the half of the regions with the smallest errors into the merged
+// contribution, returning the rest in their original order.
+func mergeRegions(regions []region, value, err *float64) []region {
+	byErr := make([]int, len(regions))
+	for i := range byErr {
+		byErr[i] = i
+	}
+	sort.SliceStable(byErr, func(i, j int) bool { return regions[byErr[i]].err < regions[byErr[j]].err })
+
+	drop := make([]bool, len(regions))
+	for _, i := range byErr[:len(regions)/2] {
+		drop[i] = true
+		*value += regions[i].value
+		*err += regions[i].err
+	}
+
+	kept := regions[:0]
+	for i, r := range regions {
+		if !drop[i] {
+			kept = append(kept, r)
+		}
+	}
+	return kept
+}
+
+// Returns the two halves of r along its split axis, with fresh storage.
+func bisect(r region) (region, region) {
+	lo := region{
+		center: append([]float64(nil), r.center...),
+		half:   append([]float64(nil), r.half...),
+	}
+	lo.half[r.axis] /= 2
+	hi := region{
+		center: append([]float64(nil), lo.center...),
+		half:   lo.half,
+	}
+	lo.center[r.axis] -= lo.half[r.axis]
+	hi.center[r.axis] += lo.half[r.axis]
+
+	return lo, hi
+}
+
+// Returns the number of points in the Genz-Malik rule in dim dimensions.
+func genzMalikPoints(dim int) int {
+	return 1 + 4*dim + 2*dim*(dim-1) + 1<<uint(dim)
+}
+
+// Applies the Genz-Malik rule to r, filling in its value, error and split
+// axis. x is scratch space of length len(r.center).
+func genzMalik(f MultiFunction, r *region, x []float64) {
+	n := float64(len(r.center))
+
+	// Evaluates f at the center displaced by di half-widths along axis i
+	// and dj along axis j
+	at := func(i int, di float64, j int, dj float64) float64 {
+		copy(x, r.center)
+		x[i] += di * r.half[i]
+		x[j] += dj * r.half[j]
+		return f(x)
+	}
+
+	f1 := at(0, 0, 0, 0)
+	var s2, s3, s4, s5 float64
+	axis, worst := 0, -1.0
+	for i := range r.center {
+		p2 := at(i, gmLambda2, i, 0) + at(i, -gmLambda2, i, 0)
+		p3 := at(i, gmLambda3, i, 0) + at(i, -gmLambda3, i, 0)
+		s2 += p2
+		s3 += p3
+
+		// The fourth difference along each axis measures roughness
+		d := math.Abs(p2 - 2*f1 - gmLambda2*gmLambda2/(gmLambda3*gmLambda3)*(p3-2*f1))
+		if d > worst {
+			axis, worst = i, d
+		}
+
+		for j := i + 1; j < len(r.center); j++ {
+			for _, si := range []float64{-1, 1} {
+				for _, sj := range []float64{-1, 1} {
+					s4 += at(i, si*gmLambda3, j, sj*gmLambda3)
+				}
+			}
+		}
+	}
+
+	// Every vertex of the cube scaled by lambda5
+	dim := len(r.center)
+	for mask := 0; mask < 1<<uint(dim); mask++ {
+		copy(x, r.center)
+		for i := range x {
+			if mask&(1<<uint(i)) != 0 {
+				x[i] += gmLambda5 * r.half[i]
+			} else {
+				x[i] -= gmLambda5 * r.half[i]
+			}
+		}
+		s5 += f(x)
+	}
+
+	volume := 1.0
+	for _, h := range r.half {
+		volume *= 2 * h
+	}
+
+	deg7 := (12824-9120*n+400*n*n)/19683*f1 + 980.0/6561*s2 +
+		(1820-400*n)/19683*s3 + 200.0/19683*s4 + 6859.0/19683/math.Exp2(n)*s5
+	deg5 := (729-950*n+50*n*n)/729*f1 + 245.0/486*s2 +
+		(265-100*n)/1458*s3 + 25.0/729*s4
+
+	r.value = volume * deg7
+	r.err = volume * math.Abs(deg7-deg5)
+	r.axis = axis
+}
@@ -0,0 +1,130 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestIntegrateCubature(t *testing.T) {
+	const tol = 1e-8
+
+	// A peaked Gaussian in three dimensions
+	gauss := func(x []float64) float64 {
+		r2 := 0.0
+		for _, v := range x {
+			r2 += (v - .3) * (v - .3)
+		}
+		return math.Exp(-r2 / (2 * .05 * .05))
+	}
+	one := math.Sqrt(2*math.Pi) * .05 * (math.Erf(.7/(.05*math.Sqrt2)) + math.Erf(.3/(.05*math.Sqrt2))) / 2
+	correct := one * one * one
+
+	r, err := IntegrateCubature(gauss, []float64{0, 0, 0}, []float64{1, 1, 1}, tol)
+	if err != nil {
+		t.Error(err)
+	}
+	if msg, ok := checkValue(r.Value, correct, 10*tol); !ok {
+		t.Error(msg)
+	}
+	if r.Evals != (2*r.Panels-1)*genzMalikPoints(3) {
+		t.Errorf("inconsistent result %+v", r)
+	}
+
+	// The result does not depend on the number of workers
+	for _, workers := range []int{1, 3, 8} {
+		s, _ := cubature(gauss, []float64{0, 0, 0}, []float64{1, 1, 1}, tol, workers, newConfig(nil))
+		if s != r {
+			t.Errorf("%d workers gave %+v, want %+v", workers, s, r)
+		}
+	}
+
+	// Polynomials of degree 5 are exact, with no error, on a single region
+	poly := func(x []float64) float64 { return x[0]*x[0]*x[0]*x[1]*x[1] + x[1] }
+	r, _ = IntegrateCubature(poly, []float64{0, 0}, []float64{2, 1}, 1e-12)
+	if msg, ok := checkValue(r.Value, 4.0/3+1, 1e-12); !ok || r.Panels != 1 {
+		t.Error(msg, r.Panels)
+	}
+
+	// Reversed limits flip the sign
+	r, _ = IntegrateCubature(poly, []float64{2, 0}, []float64{0, 1}, 1e-12)
+	if msg, ok := checkValue(r.Value, -4.0/3-1, 1e-12); !ok {
+		t.Error(msg)
+	}
+
+	// Empty, mismatched and infinite corners are rejected
+	for i, c := range [][2][]float64{
+		{nil, nil},
+		{{0, 0}, {1}},
+		{{0, 0}, {1, math.Inf(1)}},
+		{{math.Inf(-1)}, {0}},
+	} {
+		r, err := IntegrateCubature(poly, c[0], c[1], 1e-6)
+		if err != ErrInvalidInput || !math.IsNaN(r.Value) {
+			t.Errorf("case %d: got %v, %v", i, r.Value, err)
+		}
+	}
+}
+
+func TestParallelFor(t *testing.T) {
+	n := 1000
+	hits := make([]int, n)
+	parallelFor(n, 7, func(i, w int) { hits[i]++ })
+	for i, h := range hits {
+		if h != 1 {
+			t.Fatalf("task %d ran %d times", i, h)
+		}
+	}
+}
+
+func TestIntegrateCubatureMemory(t *testing.T) {
+	f := func(x []float64) float64 { return math.Sqrt(x[0] + x[1]) }
+	lower, upper := []float64{0, 0}, []float64{1, 1}
+	correct := 4.0 / 15 * (4*math.Sqrt2 - 2)
+
+	budget := WithMaxMemoryBytes(50 * (2*24 + 3*8 + 2*8*2 + 8))
+	r, err := IntegrateCubature(f, lower, upper, 1e-9, budget)
+	if err != ErrMemoryLimit || r.Panels > 50 {
+		t.Errorf("expected ErrMemoryLimit within 50 regions, got %v with %+v", err, r)
+	}
+
+	r, err = IntegrateCubature(f, lower, upper, 1e-7, budget, WithMemoryPolicy(MemoryMerge))
+	if err != nil || r.Panels > 50 {
+		t.Errorf("expected convergence within 50 regions, got %v with %+v", err, r)
+	}
+	if msg, ok := checkValue(r.Value, correct, 1e-6); !ok {
+		t.Error(msg)
+	}
+}
+
+func TestDeterministic(t *testing.T) {
+	const tol = 1e-9
+
+	peak := func(x []float64) float64 {
+		return math.Exp(-50*((x[0]-.3)*(x[0]-.3)+(x[1]-.6)*(x[1]-.6))) + math.Sin(7*x[0]*x[1])
+	}
+	lower, upper := []float64{0, 0}, []float64{1, 1}
+
+	r, err := IntegrateCubature(peak, lower, upper, tol, Deterministic(), WithWorkers(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, workers := range []int{1, 2, 5, 16} {
+		s, _ := IntegrateCubature(peak, lower, upper, tol, Deterministic(), WithWorkers(workers))
+		if s != r {
+			t.Errorf("%d workers gave %+v, want %+v", workers, s, r)
+		}
+	}
+
+	// The pairwise reduction agrees with the plain one to rounding
+	plain, _ := IntegrateCubature(peak, lower, upper, tol)
+	if msg, ok := checkValue(plain.Value, r.Value, 1e-14); !ok {
+		t.Error(msg)
+	}
+
+	f := func(x float64) float64 { return 1 / (1e-3 + x*x) }
+	g1, _ := IntegrateGK(f, -1, 2, tol, Deterministic())
+	g2, _ := IntegrateGK(f, -1, 2, tol, Deterministic())
+	if g1 != g2 {
+		t.Errorf("repeated runs gave %+v and %+v", g1, g2)
+	}
+}
@@ -0,0 +1,87 @@
+package goint
+
+import (
+	"math"
+)
+
+// The most decades DecadeContributions adds beyond an infinite limit
+// before lumping the rest into one band.
+const maxTailDecades = 64
+
+// A Band is a subinterval [Lo, Hi] of an integral along with its
+// contribution to the integral.
+type Band struct {
+	Lo, Hi, Value float64
+}
+
+// BandContributions integrates f over the bands between consecutive
+// edges, which must be increasing and may begin at -Inf or end at +Inf,
+// reporting each band's contribution so that the dominant scales can be
+// seen. The tolerance tol is shared evenly between the bands. Bands on
+// the positive axis are integrated with IntegrateLog, and others with
+// Integrate.
+func BandContributions(f Function, edges []float64, tol float64) []Band {
+	if len(edges) < 2 {
+		return nil
+	}
+
+	band_tol := tol / float64(len(edges)-1)
+	bands := make([]Band, len(edges)-1)
+	for i := range bands {
+		lo, hi := edges[i], edges[i+1]
+		bands[i] = Band{Lo: lo, Hi: hi}
+		if lo >= 0 {
+			bands[i].Value = IntegrateLog(f, lo, hi, band_tol)
+		} else {
+			bands[i].Value = Integrate(f, lo, hi, band_tol)
+		}
+	}
+
+	return bands
+}
+
+// DecadeContributions integrates f over [a, b], where 0 <= a < b, one
+// decade at a time, with band edges at the powers of ten between a and b.
+// If a is zero or b infinite, decades are added outward until
+// x f(x) ln 10, which approximates a decade's contribution, falls below
+// tol, or until maxTailDecades have been added, and the remainder is
+// reported as a single band reaching 0 or Inf.
+func DecadeContributions(f Function, a, b, tol float64) []Band {
+	if !(a >= 0 && b > a) {
+		return nil
+	}
+
+	// Returns whether the decade ending at 10^k still matters
+	matters := func(k float64) bool {
+		x := math.Pow(10, k)
+		return math.Abs(x*f(x))*math.Ln10 >= tol
+	}
+
+	// The powers of ten from 10^lo to 10^hi become edges
+	lo, hi := math.Ceil(math.Log10(a)), math.Floor(math.Log10(b))
+	if a == 0 {
+		lo = 0
+		if !math.IsInf(b, 1) {
+			lo = hi
+		}
+		for i := 0; i < maxTailDecades && matters(lo); i++ {
+			lo--
+		}
+	}
+	if math.IsInf(b, 1) {
+		hi = math.Max(lo, 0)
+		for i := 0; i < maxTailDecades && matters(hi); i++ {
+			hi++
+		}
+	}
+
+	edges := []float64{a}
+	for k := lo; k <= hi; k++ {
+		if x := math.Pow(10, k); x > a && x < b {
+			edges = append(edges, x)
+		}
+	}
+	edges = append(edges, b)
+
+	return BandContributions(f, edges, tol)
+}
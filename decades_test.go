@@ -0,0 +1,72 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDecadeContributions(t *testing.T) {
+	const tol = 1e-9
+
+	// x^-2 on [1, Inf) gives 0.9 10^-k in the decade starting at 10^k
+	inv := func(x float64) float64 { return 1 / (x * x) }
+	bands := DecadeContributions(inv, 1, math.Inf(1), tol)
+	if len(bands) < 3 || bands[0].Lo != 1 || !math.IsInf(bands[len(bands)-1].Hi, 1) {
+		t.Fatalf("unexpected bands %v", bands)
+	}
+
+	total := 0.0
+	for i, b := range bands {
+		total += b.Value
+		if i < len(bands)-1 {
+			if msg, ok := checkValue(b.Value, 1/b.Lo-1/b.Hi, tol); !ok {
+				t.Errorf("band %d: %s", i, msg)
+			}
+			if b.Hi != 10*b.Lo {
+				t.Errorf("band %d is [%g, %g]", i, b.Lo, b.Hi)
+			}
+		}
+	}
+	if msg, ok := checkValue(total, 1, 2*tol); !ok {
+		t.Error(msg)
+	}
+
+	// A finite interval not aligned to decades
+	bands = DecadeContributions(func(x float64) float64 { return 1 / x }, .5, 2000, tol)
+	want := []float64{.5, 1, 10, 100, 1000, 2000}
+	if len(bands) != len(want)-1 {
+		t.Fatalf("unexpected bands %v", bands)
+	}
+	for i, b := range bands {
+		if b.Lo != want[i] || b.Hi != want[i+1] {
+			t.Errorf("band %d is [%g, %g]", i, b.Lo, b.Hi)
+		}
+		if msg, ok := checkValue(b.Value, math.Log(b.Hi/b.Lo), tol); !ok {
+			t.Errorf("band %d: %s", i, msg)
+		}
+	}
+
+	// From zero, the small decades are lumped together
+	bands = DecadeContributions(math.Sqrt, 0, 1, tol)
+	if bands[0].Lo != 0 || bands[len(bands)-1].Hi != 1 {
+		t.Fatalf("unexpected bands %v", bands)
+	}
+	total = 0
+	for _, b := range bands {
+		total += b.Value
+	}
+	if msg, ok := checkValue(total, 2.0/3, 2*tol); !ok {
+		t.Error(msg)
+	}
+}
+
+func TestBandContributions(t *testing.T) {
+	bands := BandContributions(func(x float64) float64 { return math.Exp(-math.Abs(x)) },
+		[]float64{math.Inf(-1), 0, 1, math.Inf(1)}, 1e-9)
+	want := []float64{1, 1 - math.Exp(-1), math.Exp(-1)}
+	for i, b := range bands {
+		if msg, ok := checkValue(b.Value, want[i], 1e-8); !ok {
+			t.Errorf("band %d: %s", i, msg)
+		}
+	}
+}
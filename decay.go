@@ -0,0 +1,74 @@
+package goint
+
+import (
+	"math"
+)
+
+// Coefficients below this fraction of the largest are at the rounding
+// level of the transform that produced them.
+const roundingPlateau = 1e3 * epsilon
+
+// A SpectralDecay summarizes how the Chebyshev coefficients of an
+// interpolant fall off with degree, which indicates both how smooth the
+// function is and how well the interpolant resolves it.
+type SpectralDecay struct {
+	// Rate is the geometric factor by which the coefficients shrink per
+	// degree before they level off: near zero for entire functions,
+	// approaching one for functions with nearby singularities, kinks or
+	// jumps.
+	Rate float64
+
+	// Tail is the size of the last four coefficients relative to the
+	// largest, an estimate of the relative error of the interpolant.
+	Tail float64
+
+	// Plateau reports that the tail has reached the rounding level and
+	// stopped decaying, so that more points cannot reduce the error:
+	// the interpolant is resolved to working precision. A small Tail
+	// without a plateau means that it is still converging, and a large
+	// one that it is not yet resolved.
+	Plateau bool
+}
+
+// Decay returns the decay of the coefficients of the polynomial.
+func (c *Chebyshev) Decay() SpectralDecay {
+	return spectralDecay(c.coefs)
+}
+
+// Measures the decay of the Chebyshev coefficients coefs on their
+// monotone envelope, the largest magnitude at each degree or above, so
+// that odd or even functions, with every other coefficient zero, decay
+// smoothly.
+func spectralDecay(coefs []float64) SpectralDecay {
+	n := len(coefs)
+	envelope := make([]float64, n)
+	largest := 0.0
+	for k := n - 1; k >= 0; k-- {
+		largest = math.Max(largest, math.Abs(coefs[k]))
+		envelope[k] = largest
+	}
+	if n == 0 || largest == 0 {
+		return SpectralDecay{Plateau: true}
+	}
+	for k := range envelope {
+		envelope[k] /= largest
+	}
+
+	tail := envelope[max(n-4, 0)]
+	d := SpectralDecay{Tail: tail}
+
+	// The rate is measured until the envelope reaches the tail's level
+	knee := 0
+	for knee < n-1 && envelope[knee] > 10*tail {
+		knee++
+	}
+	d.Rate = 1
+	if knee > 0 {
+		d.Rate = math.Pow(envelope[knee], 1/float64(knee))
+	}
+
+	// A plateau is a tail at the rounding level that the last half of the
+	// coefficients no longer reduces
+	d.Plateau = tail <= roundingPlateau && envelope[n/2] <= 10*tail
+	return d
+}
@@ -0,0 +1,59 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestChebyshevDecay(t *testing.T) {
+	// Entire functions decay fast and reach the rounding plateau
+	d := NewChebyshev(math.Exp, -1, 1, 32).Decay()
+	if !d.Plateau || d.Rate > .3 || d.Tail > roundingPlateau {
+		t.Errorf("exp: %+v", d)
+	}
+
+	// A pole at ±i/5 limits the rate to 1/(1/5 + sqrt(26)/5)
+	runge := func(x float64) float64 { return 1 / (1 + 25*x*x) }
+	d = NewChebyshev(runge, -1, 1, 64).Decay()
+	if d.Plateau || math.Abs(d.Rate-5/(1+math.Sqrt(26))) > .05 {
+		t.Errorf("Runge: %+v", d)
+	}
+	d = NewChebyshev(runge, -1, 1, 512).Decay()
+	if !d.Plateau {
+		t.Errorf("resolved Runge: %+v", d)
+	}
+
+	// A kink decays algebraically and is far from resolved
+	d = NewChebyshev(math.Abs, -1, 1, 32).Decay()
+	if d.Plateau || d.Rate < .5 || d.Tail < 1e-3 {
+		t.Errorf("abs: %+v", d)
+	}
+
+	// Polynomials are resolved exactly
+	d = NewChebyshev(func(x float64) float64 { return x * x }, -1, 1, 16).Decay()
+	if !d.Plateau {
+		t.Errorf("x^2: %+v", d)
+	}
+}
+
+func TestClenshawCurtisPlateau(t *testing.T) {
+	// An unattainable tolerance stops at the rounding plateau
+	evals := 0
+	f := func(x float64) float64 { evals++; return math.Exp(x) }
+	v, err := ClenshawCurtis(f, 0, 1, 0)
+	if msg, ok := checkValue(v, math.E-1, 1e-15); !ok {
+		t.Error(msg)
+	}
+	if math.IsInf(err, 0) || err > 1e-14 {
+		t.Errorf("error estimate %g", err)
+	}
+	if evals > 33 {
+		t.Errorf("%d evaluations", evals)
+	}
+
+	// Genuine non-convergence does not
+	v, err = ClenshawCurtis(func(x float64) float64 { return math.Sqrt(math.Abs(x)) }, -1, 1, 0)
+	if !math.IsInf(err, 1) {
+		t.Errorf("got %g with error estimate %g", v, err)
+	}
+}
@@ -0,0 +1,172 @@
+package goint
+
+import (
+	"math"
+)
+
+// The most points DerivativeNewtonCotes accepts.
+const maxDerivativeNewtonCotes = 6
+
+// A DerivativeRule is a Hermite, or Hermite-Birkhoff, quadrature rule on
+// [-1, 1] that uses the derivative of the integrand at each node as well
+// as its value, so that n nodes integrate polynomials of degree 2n - 1
+// exactly. It is applied to other intervals by an affine map. Where
+// derivatives are cheap, as with analytic integrands or automatic
+// differentiation, it raises the order of each panel without more
+// evaluations of f.
+type DerivativeRule struct {
+	nodes, weights, slopes []float64
+}
+
+// DerivativeNewtonCotes returns the derivative rule with n equally spaced
+// points, including both endpoints. n = 2 is the trapezoid rule with the
+// endpoint correction (b - a)^2 (f'(a) - f'(b)) / 12, exact for cubics,
+// whose derivative terms cancel between the interior panels of a
+// composite rule. It is nil unless 2 <= n <= 6.
+func DerivativeNewtonCotes(n int) *DerivativeRule {
+	if n < 2 || n > maxDerivativeNewtonCotes {
+		return nil
+	}
+
+	nodes := make([]float64, n)
+	for i := range nodes {
+		nodes[i] = -1 + 2*float64(i)/float64(n-1)
+	}
+
+	return derivativeRule(nodes)
+}
+
+// WithDerivatives returns the rule on the nodes of r that also uses the
+// derivative of the integrand at each node, nearly doubling its degree of
+// exactness, or nil if no such rule exists. For Gauss-Legendre rules,
+// which are already of degree 2n - 1, the derivative weights vanish up to
+// rounding.
+func (r *FixedRule) WithDerivatives() *DerivativeRule {
+	return derivativeRule(r.nodes)
+}
+
+// Returns the rule on [-1, 1] with the given nodes that integrates every
+// polynomial of degree below 2 len(nodes) exactly from values and
+// derivatives, posing the moment equations in the Chebyshev basis as
+// interpolatoryRule does.
+func derivativeRule(nodes []float64) *DerivativeRule {
+	n := len(nodes)
+	A := newMatrix(2*n, 2*n)
+	moments := make([]float64, 2*n)
+	for j, x := range nodes {
+		t0, t1 := 1.0, x
+		d0, d1 := 0.0, 1.0
+		for i := 0; i < 2*n; i++ {
+			A[i][j], A[i][n+j] = t0, d0
+			t0, t1 = t1, 2*x*t1-t0
+			d0, d1 = d1, 2*t0+2*x*d1-d0
+		}
+	}
+	for i := 0; i < 2*n; i += 2 {
+		moments[i] = 2 / (1 - float64(i*i))
+	}
+
+	w, err := solve(A, moments)
+	if err != nil {
+		return nil
+	}
+
+	return &DerivativeRule{
+		nodes:   append([]float64(nil), nodes...),
+		weights: w[:n],
+		slopes:  w[n:],
+	}
+}
+
+// Nodes returns a copy of the nodes of the rule on [-1, 1].
+func (r *DerivativeRule) Nodes() []float64 {
+	return append([]float64(nil), r.nodes...)
+}
+
+// Weights returns a copy of the weights of the function values at the
+// nodes on [-1, 1], matching Nodes. On [a, b] they scale by (b - a) / 2.
+func (r *DerivativeRule) Weights() []float64 {
+	return append([]float64(nil), r.weights...)
+}
+
+// DerivativeWeights returns a copy of the weights of the derivatives at
+// the nodes on [-1, 1], matching Nodes. On [a, b] they scale by
+// ((b - a) / 2)^2.
+func (r *DerivativeRule) DerivativeWeights() []float64 {
+	return append([]float64(nil), r.slopes...)
+}
+
+// Apply estimates the integral of f, whose derivative is df, over the
+// finite interval [a, b] with the rule. df is not called at nodes whose
+// derivative weight is zero.
+func (r *DerivativeRule) Apply(f, df Function, a, b float64) float64 {
+	center, half := (a+b)/2, (b-a)/2
+	sum, slope := 0.0, 0.0
+	for i, x := range r.nodes {
+		x = center + half*x
+		sum += r.weights[i] * f(x)
+		if r.slopes[i] != 0 {
+			slope += r.slopes[i] * df(x)
+		}
+	}
+	return half * (sum + half*slope)
+}
+
+// Composite estimates the integral of f, whose derivative is df, over the
+// finite interval [a, b] by applying the rule on n equal panels. The
+// result is NaN if n < 1.
+func (r *DerivativeRule) Composite(f, df Function, a, b float64, n int) float64 {
+	if n < 1 {
+		return math.NaN()
+	}
+
+	h := (b - a) / float64(n)
+	sum := 0.0
+	for i := 0; i < n; i++ {
+		sum += r.Apply(f, df, a+float64(i)*h, a+float64(i+1)*h)
+	}
+	return sum
+}
+
+// IntegrateWithDerivative integrates f, whose derivative is df, over the finite
+// interval [a, b] to within tol by adaptive bisection as IntegrateGK
+// does, estimating each panel with the derivative rule r applied to both of
+// its halves and taking the difference from r on the whole panel as the
+// error. Result.Evals counts the evaluations of f; df is called at most as
+// often. Infinite or NaN limits, a NaN or negative tolerance or a nil
+// rule give a NaN estimate and ErrInvalidInput; if the tolerance cannot
+// be met, the best estimate is returned with ErrNotConverged.
+func IntegrateWithDerivative(f, df Function, a, b, tol float64, r *DerivativeRule) (Result, error) {
+	if r == nil || math.IsNaN(a) || math.IsNaN(b) || math.IsInf(a, 0) || math.IsInf(b, 0) || !(tol >= 0) {
+		return Result{Value: math.NaN()}, ErrInvalidInput
+	}
+	if a == b {
+		return Result{}, nil
+	}
+	if a > b {
+		res, err := IntegrateWithDerivative(f, df, b, a, tol, r)
+		res.Value = -res.Value
+		return res, err
+	}
+
+	evals := 0
+	counted := func(x float64) float64 {
+		evals++
+		return f(x)
+	}
+	panel := func(g Function, a, b float64) (float64, float64) {
+		m := a + (b-a)/2
+		whole := r.Apply(g, df, a, b)
+		halves := r.Apply(g, df, a, m) + r.Apply(g, df, m, b)
+		return halves, math.Abs(halves - whole)
+	}
+	done := func(value, total, worst float64) bool { return total <= tol }
+	s := adapt(counted, []float64{a, b}, panel, maxPanels, nil, done)
+
+	res := Result{Evals: evals, Panels: len(s.lefts)}
+	res.Value, res.Error = s.sum()
+	if !(res.Error <= tol) {
+		return res, ErrNotConverged
+	}
+	return res, nil
+}
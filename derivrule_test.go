@@ -0,0 +1,87 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDerivativeNewtonCotes(t *testing.T) {
+	// The corrected trapezoid rule
+	r := DerivativeNewtonCotes(2)
+	if msg, ok := checkValue(r.slopes[0], 1.0/3, 1e-15); !ok {
+		t.Error(msg)
+	}
+	if msg, ok := checkValue(r.slopes[1], -1.0/3, 1e-15); !ok {
+		t.Error(msg)
+	}
+
+	for n := 2; n <= maxDerivativeNewtonCotes; n++ {
+		r := DerivativeNewtonCotes(n)
+		for d := 0; d < 2*n; d++ {
+			f := func(x float64) float64 { return math.Pow(x, float64(d)) }
+			df := func(x float64) float64 {
+				if d == 0 {
+					return 0
+				}
+				return float64(d) * math.Pow(x, float64(d-1))
+			}
+			correct := (math.Pow(2, float64(d+1)) - math.Pow(-1, float64(d+1))) / float64(d+1)
+			if msg, ok := checkValue(r.Apply(f, df, -1, 2), correct, 1e-12); !ok {
+				t.Errorf("%d points, degree %d: %s", n, d, msg)
+			}
+		}
+	}
+
+	if DerivativeNewtonCotes(1) != nil || DerivativeNewtonCotes(maxDerivativeNewtonCotes+1) != nil {
+		t.Error("expected nil rules")
+	}
+}
+
+func TestFixedRuleWithDerivatives(t *testing.T) {
+	// Derivatives raise Simpson's rule from degree 3 to 5
+	r := NewtonCotes(3).WithDerivatives()
+	f := func(x float64) float64 { return math.Pow(x, 5) }
+	df := func(x float64) float64 { return 5 * math.Pow(x, 4) }
+	if msg, ok := checkValue(r.Apply(f, df, 0, 1), 1.0/6, 1e-15); !ok {
+		t.Error(msg)
+	}
+
+	g := GaussLegendre(4).WithDerivatives()
+	for i, s := range g.slopes {
+		if math.Abs(s) > 1e-14 {
+			t.Errorf("Gauss node %d: derivative weight %g", i, s)
+		}
+	}
+
+	// The composite corrected trapezoid rule is far more accurate than
+	// the plain one
+	tr := DerivativeNewtonCotes(2).Composite(math.Exp, math.Exp, 0, 1, 8)
+	if msg, ok := checkValue(tr, math.E-1, 1e-6); !ok {
+		t.Error(msg)
+	}
+	if math.Abs(tr-(math.E-1)) > math.Abs(Trapezoid(math.Exp, 0, 1, 8)-(math.E-1))/100 {
+		t.Error("no improvement over the trapezoid rule")
+	}
+}
+
+func TestIntegrateWithDerivative(t *testing.T) {
+	f := func(x float64) float64 { return 1 / (1e-2 + x*x) }
+	df := func(x float64) float64 { return -2 * x / math.Pow(1e-2+x*x, 2) }
+	correct := 20 * math.Atan(10)
+
+	r, err := IntegrateWithDerivative(f, df, 1, -1, 1e-9, DerivativeNewtonCotes(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg, ok := checkValue(r.Value, -correct, 1e-8); !ok {
+		t.Error(msg)
+	}
+	plain, _ := IntegrateGK(f, -1, 1, 1e-9, WithRule(NewtonCotes(3)))
+	if r.Evals >= plain.Evals {
+		t.Errorf("%d evaluations, %d without derivatives", r.Evals, plain.Evals)
+	}
+
+	if r, err := IntegrateWithDerivative(f, df, 0, 1, 1e-9, nil); err != ErrInvalidInput || !math.IsNaN(r.Value) {
+		t.Errorf("got %g, %v for a nil rule", r.Value, err)
+	}
+}
@@ -0,0 +1,82 @@
+package goint
+
+import (
+	"math"
+)
+
+const (
+	// The number of step halvings used by Richardson extrapolation.
+	ridderSteps = 10
+
+	// The factor by which the step shrinks between extrapolation levels.
+	ridderShrink = 1.4
+)
+
+// DerivativeRichardson estimates f'(x) from central differences with
+// initial step h, refined by Ridders' Richardson extrapolation. The
+// estimate is returned along with an estimate of its error. h should be
+// large enough that f varies appreciably over it; extrapolation supplies
+// the accuracy.
+func DerivativeRichardson(f Function, x, h float64) (float64, float64) {
+	return ridders(func(h float64) float64 {
+		return (f(x+h) - f(x-h)) / (2 * h)
+	}, h)
+}
+
+// Derivative estimates f'(x) with DerivativeRichardson and a step scaled
+// to x.
+func Derivative(f Function, x float64) float64 {
+	d, _ := DerivativeRichardson(f, x, defaultStep(x))
+	return d
+}
+
+// SecondDerivative estimates the second derivative of f at x from
+// extrapolated second central differences, with a step scaled to x.
+func SecondDerivative(f Function, x float64) float64 {
+	fx := f(x)
+	d, _ := ridders(func(h float64) float64 {
+		return (f(x+h) - 2*fx + f(x-h)) / (h * h)
+	}, 2*defaultStep(x))
+	return d
+}
+
+// DerivativeFunc returns the Function x -> Derivative(f, x).
+func DerivativeFunc(f Function) Function {
+	return func(x float64) float64 { return Derivative(f, x) }
+}
+
+// Returns an initial finite-difference step appropriate at x.
+func defaultStep(x float64) float64 {
+	return .1 * math.Max(1, math.Abs(x))
+}
+
+// Extrapolates the even-order difference quotient D(h) to h = 0 with a
+// Neville tableau, returning the best entry and its error estimate.
+func ridders(D func(h float64) float64, h float64) (float64, float64) {
+	const con2 = ridderShrink * ridderShrink
+
+	var a [ridderSteps][ridderSteps]float64
+	a[0][0] = D(h)
+	ret, err := a[0][0], math.Inf(1)
+
+	for i := 1; i < ridderSteps; i++ {
+		h /= ridderShrink
+		a[0][i] = D(h)
+		fac := con2
+		for j := 1; j <= i; j++ {
+			a[j][i] = (a[j-1][i]*fac - a[j-1][i-1]) / (fac - 1)
+			fac *= con2
+			e := math.Max(math.Abs(a[j][i]-a[j-1][i]), math.Abs(a[j][i]-a[j-1][i-1]))
+			if e <= err {
+				ret, err = a[j][i], e
+			}
+		}
+
+		// Stop once higher orders get worse, as rounding takes over
+		if math.Abs(a[i][i]-a[i-1][i-1]) >= 2*err {
+			break
+		}
+	}
+
+	return ret, err
+}
@@ -0,0 +1,52 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDerivative(t *testing.T) {
+	for _, x := range []float64{-2, 0, .5, 10} {
+		if msg, ok := checkValue(Derivative(math.Sin, x), math.Cos(x), 1e-11); !ok {
+			t.Error(msg)
+		}
+		if msg, ok := checkValue(Derivative(math.Exp, x), math.Exp(x), 1e-10*math.Exp(x)); !ok {
+			t.Error(msg)
+		}
+		if msg, ok := checkValue(SecondDerivative(math.Sin, x), -math.Sin(x), 1e-7); !ok {
+			t.Error(msg)
+		}
+	}
+
+	d, err := DerivativeRichardson(math.Log, 2, .5)
+	if msg, ok := checkValue(d, .5, 1e-12); !ok {
+		t.Error(msg)
+	}
+	if err > 1e-10 {
+		t.Errorf("error estimate %g is too large", err)
+	}
+}
+
+func TestChebyshev(t *testing.T) {
+	c := NewChebyshev(math.Exp, -1, 2, 24)
+	dc := c.Derivative()
+	ddc := dc.Derivative()
+
+	for _, x := range []float64{-1, 0, .7, 2} {
+		if msg, ok := checkValue(c.Eval(x), math.Exp(x), 1e-13); !ok {
+			t.Error(msg)
+		}
+		if msg, ok := checkValue(dc.Eval(x), math.Exp(x), 1e-11); !ok {
+			t.Error(msg)
+		}
+		if msg, ok := checkValue(ddc.Eval(x), math.Exp(x), 1e-9); !ok {
+			t.Error(msg)
+		}
+	}
+
+	// Polynomials are reproduced exactly
+	p := NewChebyshev(func(x float64) float64 { return x*x*x - x }, 0, 1, 3)
+	if msg, ok := checkValue(p.Derivative().Eval(.5), 3*.25-1, 1e-14); !ok {
+		t.Error(msg)
+	}
+}
@@ -0,0 +1,143 @@
+package goint
+
+import (
+	"math"
+)
+
+// A DoubleDouble is an unevaluated sum of two float64 values, the second
+// no larger than half a unit in the last place of the first, giving about
+// 32 significant digits. Its arithmetic uses only error-free float64
+// transformations, so it is far cheaper than math/big while accumulating
+// sums and products with negligible rounding error. Products are rounded
+// explicitly before they are added, so that the compiler cannot fuse
+// them, and the results are the same on every platform.
+type DoubleDouble struct {
+	Hi, Lo float64
+}
+
+// Returns s = a + b rounded and the rounding error, exactly.
+func twoSum(a, b float64) (float64, float64) {
+	s := a + b
+	bb := s - a
+	return s, (a - (s - bb)) + (b - bb)
+}
+
+// Returns p = a * b rounded and the rounding error, exactly.
+func twoProd(a, b float64) (float64, float64) {
+	p := a * b
+	return p, math.FMA(a, b, -p)
+}
+
+// Renormalizes hi + lo so that lo is within half an ulp of hi.
+func quickTwoSum(hi, lo float64) DoubleDouble {
+	s := hi + lo
+	return DoubleDouble{s, lo - (s - hi)}
+}
+
+// Add returns d + e.
+func (d DoubleDouble) Add(e DoubleDouble) DoubleDouble {
+	s, err := twoSum(d.Hi, e.Hi)
+	t, terr := twoSum(d.Lo, e.Lo)
+	err += t
+	r := quickTwoSum(s, err)
+	err = terr + r.Lo
+	return quickTwoSum(r.Hi, err)
+}
+
+// AddFloat returns d + x.
+func (d DoubleDouble) AddFloat(x float64) DoubleDouble {
+	s, err := twoSum(d.Hi, x)
+	return quickTwoSum(s, err+d.Lo)
+}
+
+// Mul returns d * e.
+func (d DoubleDouble) Mul(e DoubleDouble) DoubleDouble {
+	p, err := twoProd(d.Hi, e.Hi)
+	err += float64(d.Hi*e.Lo) + float64(d.Lo*e.Hi)
+	return quickTwoSum(p, err)
+}
+
+// MulFloat returns d * x.
+func (d DoubleDouble) MulFloat(x float64) DoubleDouble {
+	p, err := twoProd(d.Hi, x)
+	return quickTwoSum(p, err+float64(d.Lo*x))
+}
+
+// Float64 returns d rounded to the nearest float64.
+func (d DoubleDouble) Float64() float64 {
+	return d.Hi + d.Lo
+}
+
+// Sums xs in double-double arithmetic.
+func ddSum(xs []float64) DoubleDouble {
+	var sum DoubleDouble
+	for _, x := range xs {
+		sum = sum.AddFloat(x)
+	}
+	return sum
+}
+
+// Returns the summed integral and error estimates over all panels,
+// accumulated in double-double arithmetic.
+func (s *panelSet) ddSum() (value, err float64) {
+	return ddSum(s.values).AddFloat(s.mergedValue).Float64(), ddSum(s.errors).AddFloat(s.mergedErr).Float64()
+}
+
+// Evaluates f at center + half * node, computing the node in double-double
+// arithmetic so that it is correctly rounded in all but rare cases.
+func ddNode(f Function, center, half DoubleDouble, node float64) float64 {
+	return f(half.MulFloat(node).Add(center).Float64())
+}
+
+// Returns the center and half-width of [a, b] in double-double arithmetic.
+func ddPanel(a, b float64) (center, half DoubleDouble) {
+	sum, serr := twoSum(b, a)
+	diff, derr := twoSum(b, -a)
+	center = quickTwoSum(sum, serr).MulFloat(.5)
+	half = quickTwoSum(diff, derr).MulFloat(.5)
+	return center, half
+}
+
+// Estimates the integral over [a, b] as panel does, with the nodes and
+// the weighted sums computed in double-double arithmetic.
+func (r *kronrodRule) ddPanel(f Function, a, b float64) (float64, float64) {
+	center, half := ddPanel(a, b)
+	last := len(r.nodes) - 1
+
+	fc := ddNode(f, center, half, 0)
+	kronrod := DoubleDouble{Hi: fc}.MulFloat(r.weights[last])
+	gauss := DoubleDouble{Hi: fc}.MulFloat(r.gauss[last])
+	abs := math.Abs(fc) * r.weights[last]
+
+	values := make([][2]float64, last)
+	for i := 0; i < last; i++ {
+		f1 := ddNode(f, center, half, -r.nodes[i])
+		f2 := ddNode(f, center, half, r.nodes[i])
+		values[i] = [2]float64{f1, f2}
+
+		pair := DoubleDouble{}.AddFloat(f1).AddFloat(f2)
+		kronrod = kronrod.Add(pair.MulFloat(r.weights[i]))
+		gauss = gauss.Add(pair.MulFloat(r.gauss[i]))
+		abs += r.weights[i] * (math.Abs(f1) + math.Abs(f2))
+	}
+
+	// The error estimate needs no extra precision
+	mean := kronrod.Float64() / 2
+	asc := r.weights[last] * math.Abs(fc-mean)
+	for i, v := range values {
+		asc += r.weights[i] * (math.Abs(v[0]-mean) + math.Abs(v[1]-mean))
+	}
+
+	h := half.Float64()
+	err := math.Abs(kronrod.Add(DoubleDouble{-gauss.Hi, -gauss.Lo}).Float64() * h)
+	asc *= math.Abs(h)
+	abs *= math.Abs(h)
+	if asc != 0 && err != 0 {
+		err = asc * math.Min(1, math.Pow(200*err/asc, 1.5))
+	}
+	if abs > smallestNormal/(50*epsilon) {
+		err = math.Max(50*epsilon*abs, err)
+	}
+
+	return kronrod.Mul(half).Float64(), err
+}
@@ -0,0 +1,69 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDoubleDouble(t *testing.T) {
+	// 1 + 2^-80 - 1 is lost in float64 but kept in double-double
+	d := DoubleDouble{Hi: 1}.AddFloat(0x1p-80).AddFloat(-1)
+	if d.Float64() != 0x1p-80 {
+		t.Errorf("1 + 2^-80 - 1 = %v", d.Float64())
+	}
+
+	// (1 + 2^-40)^2 = 1 + 2^-39 + 2^-80 exactly
+	x := DoubleDouble{Hi: 1 + 0x1p-40}
+	sq := x.Mul(x)
+	if sq.Hi != 1+0x1p-39 || sq.Lo != 0x1p-80 {
+		t.Errorf("(1 + 2^-40)^2 = %v + %v", sq.Hi, sq.Lo)
+	}
+	if y := x.MulFloat(1 + 0x1p-40); y != sq {
+		t.Errorf("MulFloat gave %+v, want %+v", y, sq)
+	}
+
+	// Summing a badly conditioned sequence
+	xs := []float64{1e16, 1, -1e16, 1, 1e-8}
+	if got := ddSum(xs).Float64(); got != 2+1e-8 {
+		t.Errorf("sum %v", got)
+	}
+	if got := ddSum(xs).Add(DoubleDouble{Hi: -2}).Float64(); got != 1e-8 {
+		t.Errorf("difference %v", got)
+	}
+}
+
+func TestWithDoubleDouble(t *testing.T) {
+	const tol = 1e-12
+
+	f := func(x float64) float64 { return 1e8*math.Cos(x) + math.Exp(x) }
+	correct := 1e8*math.Sin(10) + math.Exp(10) - 1
+	r, err := IntegrateGK(f, 0, 10, tol*1e8, WithDoubleDouble())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg, ok := checkValue(r.Value, correct, 1e-5); !ok {
+		t.Error(msg)
+	}
+
+	// The Kronrod estimate itself is unchanged to rounding
+	plain, _ := IntegrateGK(f, 0, 10, tol*1e8)
+	if r.Evals != plain.Evals {
+		t.Errorf("%d evaluations, want %d", r.Evals, plain.Evals)
+	}
+	if msg, ok := checkValue(r.Value, plain.Value, 1e-6); !ok {
+		t.Error(msg)
+	}
+
+	// Large cancelling contributions lose digits in float64 sums, but not
+	// in double-double ones, measured against the same panels re-summed in
+	// extended precision
+	g := func(x float64) float64 { return 1e8 * math.Sin(50*x) }
+	plain, _ = IntegrateGK(g, 0, 2*math.Pi, 1e-4, WithPrecisionCheck())
+	r, err = IntegrateGK(g, 0, 2*math.Pi, 1e-4, WithPrecisionCheck(), WithDoubleDouble())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plain.Rounding < 1e-8 || r.Rounding > 1e-12 {
+		t.Errorf("rounding %v in float64, %v in double-double", plain.Rounding, r.Rounding)
+	}
+}
@@ -0,0 +1,185 @@
+package goint
+
+import (
+	"math"
+)
+
+// The most Simpson panels IntegrateEnvelope places in each half-period.
+const maxEnvelopePanels = 1 << 10
+
+// The number of Gauss-Legendre points used to integrate the carrier
+// against the Simpson basis on each panel.
+const envelopeMomentPoints = 20
+
+// A Carrier is an oscillating factor of an integrand whose zeros are
+// known: Eval changes sign at Zero + k HalfPeriod for every integer k,
+// and Eval(x + HalfPeriod) = -Eval(x).
+type Carrier struct {
+	Eval       Function
+	Zero       float64
+	HalfPeriod float64
+}
+
+// SineCarrier returns the carrier sin(omega x).
+func SineCarrier(omega float64) Carrier {
+	return Carrier{
+		Eval:       func(x float64) float64 { return math.Sin(omega * x) },
+		HalfPeriod: math.Pi / math.Abs(omega),
+	}
+}
+
+// CosineCarrier returns the carrier cos(omega x).
+func CosineCarrier(omega float64) Carrier {
+	half := math.Pi / math.Abs(omega)
+	return Carrier{
+		Eval:       func(x float64) float64 { return math.Cos(omega * x) },
+		Zero:       half / 2,
+		HalfPeriod: half,
+	}
+}
+
+// IntegrateEnvelope integrates envelope(x) c.Eval(x) over the finite
+// interval [a, b] to within tol, for an envelope that is smooth on the
+// scale of the carrier's half-period, such as a slowly decaying
+// amplitude. The interval is cut at the carrier's zeros, and on each
+// whole half-period between them the envelope is integrated by a
+// composite Simpson product rule, its quadratic interpolant on each panel
+// integrated exactly against the carrier. The weights are the same on
+// every half-period up to sign, so they are computed once, and the number
+// of panels per half-period is doubled, reusing earlier evaluations,
+// until the estimate changes by at most half of tol. Each partial
+// half-period at the ends is integrated by IntegrateGK. Unlike the
+// adaptive rule, which must resolve every oscillation of the product,
+// the cost depends only on how smooth the envelope is. Result.Evals
+// counts evaluations of the envelope. Infinite or NaN limits, a NaN or
+// negative tolerance, or a carrier without a positive half-period give a
+// NaN estimate and ErrInvalidInput; if the tolerance is not met, the best
+// estimate is returned with ErrNotConverged.
+func IntegrateEnvelope(envelope Function, c Carrier, a, b, tol float64) (Result, error) {
+	if math.IsNaN(a) || math.IsNaN(b) || math.IsInf(a, 0) || math.IsInf(b, 0) || !(tol >= 0) ||
+		c.Eval == nil || !(c.HalfPeriod > 0) || math.IsInf(c.HalfPeriod, 0) || math.IsNaN(c.Zero) {
+		return Result{Value: math.NaN()}, ErrInvalidInput
+	}
+	if a == b {
+		return Result{}, nil
+	}
+	if a > b {
+		r, err := IntegrateEnvelope(envelope, c, b, a, tol)
+		r.Value = -r.Value
+		return r, err
+	}
+
+	product := func(x float64) float64 { return envelope(x) * c.Eval(x) }
+	h := c.HalfPeriod
+	first := math.Ceil((a - c.Zero) / h)
+	last := math.Floor((b - c.Zero) / h)
+	if last <= first {
+		return IntegrateGK(product, a, b, tol)
+	}
+
+	// The partial half-periods at the ends
+	var r Result
+	var status error
+	lo, hi := c.Zero+first*h, c.Zero+last*h
+	for _, end := range [][2]float64{{a, lo}, {hi, b}} {
+		if end[0] < end[1] {
+			piece, err := IntegrateGK(product, end[0], end[1], tol/4)
+			r.Value += piece.Value
+			r.Error += piece.Error
+			r.Evals += piece.Evals
+			if err != nil {
+				status = err
+			}
+		}
+	}
+
+	// values[k] holds the envelope at the 2m + 1 Simpson nodes of the k-th
+	// whole half-period
+	count := int(last - first)
+	values := make([][]float64, count)
+	m := 1
+	for k := range values {
+		left := lo + float64(k)*h
+		values[k] = []float64{envelope(left), envelope(left + h/2), envelope(left + h)}
+	}
+	r.Evals += 3 * count
+
+	middle := envelopeSum(values, envelopeWeights(c, lo, h, m))
+	diff := math.Inf(1)
+	for 2*m <= maxEnvelopePanels && !(diff <= tol/2) {
+		m *= 2
+		step := h / float64(2*m)
+		for k := range values {
+			left := lo + float64(k)*h
+			finer := make([]float64, 2*m+1)
+			for j := range finer {
+				if j%2 == 0 {
+					finer[j] = values[k][j/2]
+				} else {
+					finer[j] = envelope(left + float64(j)*step)
+				}
+			}
+			values[k] = finer
+		}
+		r.Evals += m * count
+
+		refined := envelopeSum(values, envelopeWeights(c, lo, h, m))
+		diff = math.Abs(refined - middle)
+		middle = refined
+		if math.IsNaN(middle) {
+			break
+		}
+	}
+
+	r.Value += middle
+	r.Error += diff
+	if !(diff <= tol/2) {
+		return r, ErrNotConverged
+	}
+	return r, status
+}
+
+// Returns the sum over the half-periods, alternating in sign, of the
+// weights applied to the envelope values of each.
+func envelopeSum(values [][]float64, weights []float64) float64 {
+	sum := 0.0
+	for k, v := range values {
+		s := 0.0
+		for j, w := range weights {
+			s += w * v[j]
+		}
+		if k%2 == 1 {
+			s = -s
+		}
+		sum += s
+	}
+	return sum
+}
+
+// Returns the weights of the composite Simpson product rule with m panels
+// over the half-period [lo, lo + h] of the carrier c, the integrals of
+// each panel's quadratic Lagrange basis against the carrier. The carrier
+// is smooth between its zeros, so a fixed Gauss-Legendre rule integrates
+// it to rounding.
+func envelopeWeights(c Carrier, lo, h float64, m int) []float64 {
+	weights := make([]float64, 2*m+1)
+	step := h / float64(2*m)
+	rule := GaussLegendre(envelopeMomentPoints)
+	for i := 0; i < m; i++ {
+		mid := lo + float64(2*i+1)*step
+
+		// The moments of the carrier in s = (x - mid) / step
+		var mu [3]float64
+		for p := range mu {
+			moment := func(x float64) float64 {
+				return math.Pow((x-mid)/step, float64(p)) * c.Eval(x)
+			}
+			mu[p] = rule.Apply(moment, mid-step, mid+step)
+		}
+
+		weights[2*i] += (mu[2] - mu[1]) / 2
+		weights[2*i+1] += mu[0] - mu[2]
+		weights[2*i+2] += (mu[2] + mu[1]) / 2
+	}
+	return weights
+}
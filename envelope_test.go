@@ -0,0 +1,51 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestIntegrateEnvelope(t *testing.T) {
+	const tol = 1e-9
+	decay := func(x float64) float64 { return math.Exp(-x / 50) }
+
+	// The integral of e^(-ax) sin(wx) and e^(-ax) cos(wx)
+	antiSin := func(x, a, w float64) float64 {
+		return math.Exp(-a*x) * (-a*math.Sin(w*x) - w*math.Cos(w*x)) / (a*a + w*w)
+	}
+	antiCos := func(x, a, w float64) float64 {
+		return math.Exp(-a*x) * (w*math.Sin(w*x) - a*math.Cos(w*x)) / (a*a + w*w)
+	}
+
+	cases := []struct {
+		c       Carrier
+		a, b    float64
+		correct float64
+	}{
+		{SineCarrier(20), 0, 100, antiSin(100, .02, 20) - antiSin(0, .02, 20)},
+		{CosineCarrier(20), .3, 99.9, antiCos(99.9, .02, 20) - antiCos(.3, .02, 20)},
+		{SineCarrier(-20), 99.9, .3, -(antiSin(99.9, .02, -20) - antiSin(.3, .02, -20))},
+		{CosineCarrier(20), 0, .1, antiCos(.1, .02, 20) - antiCos(0, .02, 20)},
+	}
+
+	for i, c := range cases {
+		r, err := IntegrateEnvelope(decay, c.c, c.a, c.b, tol)
+		if err != nil {
+			t.Errorf("case %d: %v", i, err)
+		}
+		if msg, ok := checkValue(r.Value, c.correct, 2*tol); !ok {
+			t.Errorf("case %d: %s", i, msg)
+		}
+	}
+
+	r, _ := IntegrateEnvelope(decay, SineCarrier(200), 0, 100, tol)
+	product := func(x float64) float64 { return decay(x) * math.Sin(200*x) }
+	plain, _ := IntegrateGK(product, 0, 100, tol)
+	if 3*r.Evals >= plain.Evals {
+		t.Errorf("%d evaluations, %d by IntegrateGK", r.Evals, plain.Evals)
+	}
+
+	if r, err := IntegrateEnvelope(decay, Carrier{Eval: math.Sin}, 0, 1, tol); err != ErrInvalidInput || !math.IsNaN(r.Value) {
+		t.Errorf("got %g, %v without a half-period", r.Value, err)
+	}
+}
@@ -0,0 +1,114 @@
+package goint
+
+import (
+	"math"
+)
+
+// WithExtrapolation makes the adaptive driver accelerate its convergence
+// with Wynn's epsilon algorithm, as QUADPACK's dqags does, for integrands
+// with integrable endpoint or interior singularities and, through the
+// substitution of an infinite interval, slowly decaying tails. There,
+// bisection towards the singular point reduces the error only by a
+// constant factor per level, and would otherwise take hundreds of levels
+// or stall. Each time refinement reaches a new smallest panel width, the
+// total is recorded, and the sequence of totals is extrapolated to its
+// limit. The extrapolated value is accepted once the change in it over
+// the last two levels, added to the error of the panels wider than the
+// two finest levels, is within the tolerance; ordinary convergence is
+// accepted as before. MemoryMerge is ignored: refinement stops at the
+// memory allowance.
+func WithExtrapolation() Option {
+	return func(c *config) { c.extrapolate = true }
+}
+
+// Adapts as adapt does, without merging, additionally extrapolating the
+// totals at each new level of refinement. If the extrapolation meets tol
+// first, its value and error estimate are returned with ok set.
+func adaptExtrapolated(f Function, points []float64, rule panelRule, limit int,
+	done func(value, total, worst float64) bool, tol float64) (s *panelSet, value, err float64, ok bool) {
+	s, sum, total := newPanelSet(f, points, rule)
+	finest := math.Inf(1)
+	for i := range s.lefts {
+		finest = math.Min(finest, s.rights[i]-s.lefts[i])
+	}
+
+	var sums, limits []float64
+	for len(s.order) > 0 {
+		if math.IsNaN(total) {
+			if sum, total = s.sum(); math.IsNaN(total) {
+				break
+			}
+		}
+		if done(sum, total, s.errors[s.order[0]]) {
+			if sum, total = s.sum(); done(sum, total, s.errors[s.order[0]]) {
+				break
+			}
+		}
+		if len(s.order) >= limit {
+			break
+		}
+
+		worst := s.order[0]
+		width := (s.rights[worst] - s.lefts[worst]) / 2
+		dvalue, derr, bisected := s.bisect(f, rule)
+		if !bisected {
+			break
+		}
+		sum += dvalue
+		total += derr
+		if !(width < finest) {
+			continue
+		}
+
+		// A new level: extrapolate the totals once the coarser panels
+		// are resolved
+		finest = width
+		sum, total = s.sum()
+		sums = append(sums, sum)
+		limits = append(limits, wynnEpsilon(sums))
+		n := len(limits)
+		if n < 3 {
+			continue
+		}
+		large := 0.0
+		for i := range s.lefts {
+			if s.rights[i]-s.lefts[i] > 2*finest {
+				large += s.errors[i]
+			}
+		}
+		err = math.Abs(limits[n-1]-limits[n-2]) + math.Abs(limits[n-2]-limits[n-3]) + large
+		if err <= tol {
+			return s, limits[n-1], err, true
+		}
+	}
+
+	return s, 0, 0, false
+}
+
+// Returns Wynn's epsilon extrapolation of the limit of the sequence s,
+// the entry of the highest even column of the epsilon table that uses
+// every term. Where two entries of a column coincide the table cannot be
+// continued, and the last even column reached is used.
+func wynnEpsilon(s []float64) float64 {
+	// prev and cur are columns k-1 and k of the table, each indexed so
+	// that its last entry uses the last term
+	prev := make([]float64, len(s)+1)
+	cur := append([]float64(nil), s...)
+	best := s[len(s)-1]
+	for k := 0; len(cur) > 1; k++ {
+		next := make([]float64, len(cur)-1)
+		for i := range next {
+			d := cur[i+1] - cur[i]
+			if d == 0 {
+				return best
+			}
+			next[i] = prev[i+1] + 1/d
+		}
+		prev, cur = cur, next
+		if k%2 == 1 {
+			best = cur[len(cur)-1]
+		}
+	}
+
+	return best
+}
@@ -0,0 +1,47 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestWithExtrapolation(t *testing.T) {
+	const tol = 1e-10
+	cases := []struct {
+		f       Function
+		a, b    float64
+		correct float64
+	}{
+		{func(x float64) float64 { return 1 / math.Sqrt(x) }, 0, 1, 2},
+		{func(x float64) float64 { return math.Pow(x, -.9) }, 0, 1, 10},
+		{func(x float64) float64 { return math.Log(x) / math.Sqrt(x) }, 0, 1, -4},
+		{func(x float64) float64 { return math.Pow(x, -1.1) }, 1, math.Inf(1), 10},
+	}
+
+	for i, c := range cases {
+		plain, _ := IntegrateGK(c.f, c.a, c.b, tol)
+		r, err := IntegrateGK(c.f, c.a, c.b, tol, WithExtrapolation())
+		if err != nil {
+			t.Errorf("case %d: %v", i, err)
+		}
+		if msg, ok := checkValue(r.Value, c.correct, 100*tol); !ok {
+			t.Errorf("case %d: %s", i, msg)
+		}
+		if r.Evals > plain.Evals {
+			t.Errorf("case %d: %d evaluations, %d without extrapolation", i, r.Evals, plain.Evals)
+		}
+	}
+}
+
+func TestWynnEpsilon(t *testing.T) {
+	// The partial sums of the alternating series for log 2
+	s := make([]float64, 16)
+	sum := 0.0
+	for i := range s {
+		sum += math.Pow(-1, float64(i)) / float64(i+1)
+		s[i] = sum
+	}
+	if msg, ok := checkValue(wynnEpsilon(s), math.Ln2, 1e-10); !ok {
+		t.Error(msg)
+	}
+}
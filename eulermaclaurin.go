@@ -0,0 +1,51 @@
+package goint
+
+import (
+	"math"
+)
+
+// The coefficients B_2k / (2k)! of the Euler-Maclaurin formula, for k = 1,
+// 2, ....
+var eulerMaclaurinCoefficients = [...]float64{
+	1.0 / 12,
+	-1.0 / 720,
+	1.0 / 30240,
+	-1.0 / 1209600,
+	1.0 / 47900160,
+	-691.0 / 1307674368000,
+	1.0 / 74724249600,
+	-3617.0 / 10670622842880000,
+	43867.0 / 5109094217170944000,
+	-174611.0 / 802857662698291200000,
+}
+
+// EulerMaclaurin integrates f over the finite interval [a, b] with the
+// composite trapezoid rule on n equal panels, corrected at the endpoints
+// by the Euler-Maclaurin formula using the derivatives of f:
+// derivs[k] is the (k+1)-th derivative. Only the odd-order derivatives
+// are evaluated, each once at each endpoint, so that with derivs up to
+// order 2m - 1 the error of the trapezoid rule, O(h^2), falls to
+// O(h^(2m+2)) for n + 1 + 2m evaluations, and polynomials of degree up
+// to 2m + 1 are integrated exactly. Derivatives beyond order 19 are
+// ignored, and a nil odd-order entry ends the corrections; even-order
+// entries are never used and may be nil. The result is NaN if n < 1 or
+// either bound is infinite.
+func EulerMaclaurin(f Function, derivs []Function, a, b float64, n int) float64 {
+	ret := Trapezoid(f, a, b, n)
+	if math.IsNaN(ret) {
+		return ret
+	}
+
+	h := (b - a) / float64(n)
+	hk := h * h
+	for k, c := range eulerMaclaurinCoefficients {
+		if 2*k >= len(derivs) || derivs[2*k] == nil {
+			break
+		}
+		d := derivs[2*k]
+		ret -= c * hk * (d(b) - d(a))
+		hk *= h * h
+	}
+
+	return ret
+}
@@ -0,0 +1,46 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEulerMaclaurin(t *testing.T) {
+	// exp is its own derivative
+	derivs := []Function{math.Exp, math.Exp, math.Exp, math.Exp, math.Exp, math.Exp, math.Exp}
+	correct := math.E - 1
+	if msg, ok := checkValue(EulerMaclaurin(math.Exp, derivs, 0, 1, 4), correct, 1e-12); !ok {
+		t.Error(msg)
+	}
+
+	// Without derivatives the rule is the trapezoid rule
+	if got, want := EulerMaclaurin(math.Exp, nil, 0, 1, 4), Trapezoid(math.Exp, 0, 1, 4); got != want {
+		t.Errorf("got %g, expected %g", got, want)
+	}
+
+	// Each pair of derivatives improves the error by a factor of about
+	// (h / 2π)^2
+	prev := math.Abs(Trapezoid(math.Exp, 0, 1, 4) - correct)
+	for m := 1; m <= 3; m++ {
+		got := math.Abs(EulerMaclaurin(math.Exp, derivs[:2*m-1], 0, 1, 4) - correct)
+		if got > prev/100 {
+			t.Errorf("%d corrections: error %g, previously %g", m, got, prev)
+		}
+		prev = got
+	}
+
+	// x^5 is integrated exactly with its first and third derivatives
+	f := func(x float64) float64 { return math.Pow(x, 5) }
+	df := []Function{
+		func(x float64) float64 { return 5 * math.Pow(x, 4) },
+		nil,
+		func(x float64) float64 { return 60 * x * x },
+	}
+	if msg, ok := checkValue(EulerMaclaurin(f, df, -1, 2, 3), 63.0/6, 1e-13); !ok {
+		t.Error(msg)
+	}
+
+	if !math.IsNaN(EulerMaclaurin(math.Exp, derivs, 0, math.Inf(1), 4)) {
+		t.Error("expected NaN on an infinite interval")
+	}
+}
@@ -0,0 +1,57 @@
+package goint
+
+import (
+	"math"
+)
+
+// A CompositeRule pairs a composite quadrature rule, which integrates f
+// over the finite interval [a, b] on n equal panels, with the order of its
+// error: halving the step divides the error by about 2^Order. Extrapolate
+// assumes, as holds for symmetric rules such as these by the
+// Euler-Maclaurin formula, that the error is a series in even powers of
+// the step beginning with the Order-th.
+type CompositeRule struct {
+	Apply func(f Function, a, b float64, n int) float64
+	Order int
+}
+
+// The composite rules of Trapezoid, Simpson and Boole.
+var (
+	TrapezoidRule = CompositeRule{Trapezoid, 2}
+	SimpsonRule   = CompositeRule{Simpson, 4}
+	BooleRule     = CompositeRule{Boole, 6}
+)
+
+// Extrapolate returns the composite rule that applies rule on n, 2n, ...,
+// 2^levels n panels and combines the results by Richardson extrapolation,
+// eliminating levels further terms of the error series, so that its order
+// is rule.Order + 2 levels. Extrapolating TrapezoidRule gives the columns
+// of Romberg's table. It costs about twice the evaluations of the finest
+// application, and the result can itself be extrapolated further. Where
+// the integrand is not smooth enough for the error series to hold, the
+// extrapolation gains nothing and may lose accuracy.
+func Extrapolate(rule CompositeRule, levels int) CompositeRule {
+	apply := func(f Function, a, b float64, n int) float64 {
+		if n < 1 || levels < 0 {
+			return math.NaN()
+		}
+
+		row := make([]float64, levels+1)
+		for k := range row {
+			row[k] = rule.Apply(f, a, b, n<<uint(k))
+		}
+
+		// Each pass removes the leading term of the remaining series from
+		// adjacent estimates, of order p, p + 2, ...
+		for j := 0; j < levels; j++ {
+			factor := math.Ldexp(1, rule.Order+2*j) - 1
+			for k := levels; k > j; k-- {
+				row[k] += (row[k] - row[k-1]) / factor
+			}
+		}
+
+		return row[levels]
+	}
+
+	return CompositeRule{apply, rule.Order + 2*levels}
+}
@@ -0,0 +1,35 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestExtrapolate(t *testing.T) {
+	// Extrapolating the trapezoid rule once gives Simpson's rule
+	simpson := Extrapolate(TrapezoidRule, 1)
+	if msg, ok := checkValue(simpson.Apply(math.Exp, 0, 1, 4), Simpson(math.Exp, 0, 1, 4), 1e-14); !ok {
+		t.Error(msg)
+	}
+
+	// A wide interval keeps the errors of the high orders above rounding
+	wide := math.Exp(8) - 1
+	rules := []CompositeRule{
+		simpson,
+		Extrapolate(TrapezoidRule, 3),
+		Extrapolate(SimpsonRule, 2),
+		Extrapolate(Extrapolate(BooleRule, 1), 1),
+	}
+	for i, r := range rules {
+		// Halving the step reduces the error by 2^Order
+		e1 := math.Abs(r.Apply(math.Exp, 0, 8, 2) - wide)
+		e2 := math.Abs(r.Apply(math.Exp, 0, 8, 4) - wide)
+		if ratio := math.Log2(e1 / e2); math.Abs(ratio-float64(r.Order)) > .5 {
+			t.Errorf("rule %d: error ratio 2^%v, expected 2^%d", i, ratio, r.Order)
+		}
+	}
+
+	if !math.IsNaN(Extrapolate(SimpsonRule, 2).Apply(math.Exp, 0, 1, 0)) {
+		t.Error("expected NaN for no panels")
+	}
+}
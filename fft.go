@@ -0,0 +1,66 @@
+package goint
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// Replaces x, whose length must be a power of two, with its discrete
+// Fourier transform sum_j x[j] exp(-2 pi i j k / n), using the iterative
+// radix-2 Cooley-Tukey algorithm.
+func fft(x []complex128) {
+	n := len(x)
+
+	// Bit-reversal permutation
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j ^= bit
+		if i < j {
+			x[i], x[j] = x[j], x[i]
+		}
+	}
+
+	for size := 2; size <= n; size <<= 1 {
+		step := cmplx.Rect(1, -2*math.Pi/float64(size))
+		for start := 0; start < n; start += size {
+			w := complex(1, 0)
+			for k := 0; k < size/2; k++ {
+				u, v := x[start+k], w*x[start+k+size/2]
+				x[start+k], x[start+k+size/2] = u+v, u-v
+				w *= step
+			}
+		}
+	}
+}
+
+// Returns whether n is a positive power of two.
+func isPowerOfTwo(n int) bool {
+	return n > 0 && n&(n-1) == 0
+}
+
+// Computes the Chebyshev coefficients of the values at the points
+// cos(j pi / n), j = 0..n, where n is a power of two, with an FFT of the
+// even extension of the values. The result matches chebCoefficients.
+func chebCoefficientsFFT(values []float64) []float64 {
+	n := len(values) - 1
+	y := make([]complex128, 2*n)
+	for j, v := range values {
+		y[j] = complex(v, 0)
+		if j > 0 && j < n {
+			y[2*n-j] = complex(v, 0)
+		}
+	}
+	fft(y)
+
+	coefs := make([]float64, n+1)
+	for k := range coefs {
+		coefs[k] = real(y[k]) / float64(n)
+	}
+	coefs[0] /= 2
+	coefs[n] /= 2
+
+	return coefs
+}
@@ -0,0 +1,135 @@
+package goint
+
+import (
+	"math"
+)
+
+// An Oscillation selects the oscillating factor of IntegrateOscillatory.
+type Oscillation int
+
+const (
+	// Cosine selects cos(omega x).
+	Cosine Oscillation = iota
+
+	// Sine selects sin(omega x).
+	Sine
+)
+
+const (
+	// The fewest and most subintervals of the composite Filon rule.
+	filonMinPanels = 16
+	filonMaxPanels = 1 << 22
+
+	// Below this value of omega h, Filon's coefficients are computed from
+	// their Taylor series to avoid cancellation.
+	filonSeries = 1.0 / 6
+)
+
+// IntegrateOscillatory integrates f(x) cos(omega x), or f(x) sin(omega x)
+// when kind is Sine, over the finite interval [a, b] to within tol by
+// Filon's method: f is interpolated by a quadratic on each pair of
+// subintervals and the product with the oscillating factor is integrated
+// exactly. The error therefore depends on how well f, rather than the
+// integrand, is resolved, and does not grow with omega, so highly
+// oscillatory integrals need no more points than f itself. The number of
+// subintervals is doubled, reusing every evaluation, until successive
+// estimates agree to within tol. Infinite or NaN bounds, or a NaN or
+// negative tolerance, give a NaN estimate and ErrInvalidInput; if the
+// tolerance cannot be met, the best estimate is returned with
+// ErrNotConverged.
+func IntegrateOscillatory(f Function, a, b, omega float64, kind Oscillation, tol float64) (Result, error) {
+	if math.IsNaN(a) || math.IsNaN(b) || math.IsInf(a, 0) || math.IsInf(b, 0) ||
+		math.IsNaN(omega) || !(tol >= 0) || (kind != Cosine && kind != Sine) {
+		return Result{Value: math.NaN()}, ErrInvalidInput
+	}
+	if a == b {
+		return Result{}, nil
+	}
+
+	n := filonMinPanels
+	values := make([]float64, n+1)
+	for i := range values {
+		values[i] = f(a + (b-a)*float64(i)/float64(n))
+	}
+	evals := n + 1
+	value := filon(values, a, b, omega, kind)
+	diff := math.Inf(1)
+
+	for n < filonMaxPanels && !(diff < tol) {
+		// The old points become the even points of the refined grid
+		n *= 2
+		refined := make([]float64, n+1)
+		for i := range refined {
+			if i%2 == 0 {
+				refined[i] = values[i/2]
+			} else {
+				refined[i] = f(a + (b-a)*float64(i)/float64(n))
+				evals++
+			}
+		}
+		values = refined
+
+		next := filon(values, a, b, omega, kind)
+		value, diff = next, math.Abs(next-value)
+	}
+
+	r := Result{Value: value, Error: diff, Evals: evals, Panels: n}
+	if !(diff < tol) {
+		return r, ErrNotConverged
+	}
+	return r, nil
+}
+
+// Applies the composite Filon rule to f, given at len(values) equally
+// spaced points from a to b, an odd number of them.
+func filon(values []float64, a, b, omega float64, kind Oscillation) float64 {
+	n := len(values) - 1
+	h := (b - a) / float64(n)
+	alpha, beta, gamma := filonCoefficients(omega * h)
+
+	trig := math.Cos
+	if kind == Sine {
+		trig = math.Sin
+	}
+
+	even, odd := 0.0, 0.0
+	for i, v := range values {
+		t := v * trig(omega*(a+float64(i)*h))
+		if i%2 == 0 {
+			even += t
+		} else {
+			odd += t
+		}
+	}
+	fa, fb := values[0], values[n]
+	even -= (fa*trig(omega*a) + fb*trig(omega*b)) / 2
+
+	// The boundary term integrates the oscillating factor against f
+	var boundary float64
+	if kind == Cosine {
+		boundary = fb*math.Sin(omega*b) - fa*math.Sin(omega*a)
+	} else {
+		boundary = fa*math.Cos(omega*a) - fb*math.Cos(omega*b)
+	}
+
+	return h * (alpha*boundary + beta*even + gamma*odd)
+}
+
+// Returns Filon's coefficients for theta = omega h.
+func filonCoefficients(theta float64) (alpha, beta, gamma float64) {
+	if math.Abs(theta) < filonSeries {
+		t2 := theta * theta
+		t3 := t2 * theta
+		alpha = t3 * (2.0/45 - t2*(2.0/315-t2*2.0/4725))
+		beta = 2.0/3 + t2*(2.0/15-t2*(4.0/105-t2*2.0/567))
+		gamma = 4.0/3 - t2*(2.0/15-t2*(1.0/210-t2/11340))
+		return alpha, beta, gamma
+	}
+
+	s, c := math.Sincos(theta)
+	t3 := theta * theta * theta
+	alpha = (theta*theta + theta*s*c - 2*s*s) / t3
+	beta = 2 * (theta*(1+c*c) - 2*s*c) / t3
+	gamma = 4 * (s - theta*c) / t3
+	return alpha, beta, gamma
+}
@@ -0,0 +1,53 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestIntegrateOscillatory(t *testing.T) {
+	const tol = 1e-10
+
+	// The integrals of e^x cos(wx) and e^x sin(wx) over [0, 1]
+	exact := func(w float64) (float64, float64) {
+		e := math.E
+		d := 1 + w*w
+		c := (e*(math.Cos(w)+w*math.Sin(w)) - 1) / d
+		s := (e*(math.Sin(w)-w*math.Cos(w)) + w) / d
+		return c, s
+	}
+
+	for _, w := range []float64{0, .1, 3, 100, 1e4, -50} {
+		c, s := exact(w)
+		rc, err := IntegrateOscillatory(math.Exp, 0, 1, w, Cosine, tol)
+		if err != nil {
+			t.Errorf("omega = %v: %v", w, err)
+		}
+		if msg, ok := checkValue(rc.Value, c, 10*tol); !ok {
+			t.Errorf("cos, omega = %v: %s", w, msg)
+		}
+		rs, err := IntegrateOscillatory(math.Exp, 0, 1, w, Sine, tol)
+		if err != nil {
+			t.Errorf("omega = %v: %v", w, err)
+		}
+		if msg, ok := checkValue(rs.Value, s, 10*tol); !ok {
+			t.Errorf("sin, omega = %v: %s", w, msg)
+		}
+
+		// The cost does not grow with the frequency
+		if rc.Evals > 2000 {
+			t.Errorf("omega = %v: %d evaluations", w, rc.Evals)
+		}
+	}
+
+	// Reversed limits
+	r, _ := IntegrateOscillatory(math.Exp, 1, 0, 3, Cosine, tol)
+	c, _ := exact(3)
+	if msg, ok := checkValue(r.Value, -c, 10*tol); !ok {
+		t.Error(msg)
+	}
+
+	if _, err := IntegrateOscillatory(math.Exp, 0, math.Inf(1), 3, Cosine, tol); err != ErrInvalidInput {
+		t.Errorf("infinite bound gave %v", err)
+	}
+}
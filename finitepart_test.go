@@ -0,0 +1,88 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFinitePart(t *testing.T) {
+	tol := 1e-8
+
+	// f = 1 gives the pure finite part
+	one := func(x float64) float64 { return 1 }
+	if msg, ok := checkValue(FinitePart(one, -1, 1, 0, tol), -2, tol); !ok {
+		t.Error(msg)
+	}
+
+	// f.p. of x^2/(x-c)^2 = int (1 + 2c/(x-c) + c^2/(x-c)^2)
+	c := .3
+	square := func(x float64) float64 { return x * x }
+	correct := 1 + 2*c*math.Log((1-c)/c) + c*c*(-1/(1-c)-1/c)
+	if msg, ok := checkValue(FinitePart(square, 0, 1, c, tol), correct, 1e-7); !ok {
+		t.Error(msg)
+	}
+
+	// f.p. of exp(x)/x^2 on [-1, 1], from the series of exp
+	correct = finitePartExp()
+	if msg, ok := checkValue(FinitePart(math.Exp, -1, 1, 0, tol), correct, 1e-7); !ok {
+		t.Error(msg)
+	}
+
+	if !math.IsNaN(FinitePart(one, 0, 1, 1, tol)) {
+		t.Error("expected NaN for a pole at an endpoint")
+	}
+}
+
+// Sums the finite part of exp(x)/x^2 over [-1, 1] term by term: the x^0
+// term gives -2, x^1 gives zero, and x^k/k! for k >= 2 integrates to
+// (1 - (-1)^(k-1)) / ((k-1) k!).
+func finitePartExp() float64 {
+	ret := -2.0
+	fact := 1.0
+	for k := 2; k < 30; k++ {
+		fact *= float64(k)
+		if k%2 == 0 {
+			ret += 2 / (float64(k-1) * fact)
+		}
+	}
+	return ret
+}
+
+func TestPrincipalValue(t *testing.T) {
+	// p.v. of exp(x)/x on [-1, 1] is 2 Shi(1)
+	correct := 2.114501750751457
+	if msg, ok := checkValue(PrincipalValue(math.Exp, -1, 1, 0, 1e-9), correct, 1e-8); !ok {
+		t.Error(msg)
+	}
+
+	// An unreachable tolerance exhausts the halvings, after which the
+	// rest of the interval must meet the last window exactly
+	if msg, ok := checkValue(PrincipalValue(math.Exp, -1, 1, 0, 0), correct, 1e-10); !ok {
+		t.Error(msg)
+	}
+}
+
+func TestPrincipalValueCases(t *testing.T) {
+	const tol = 1e-10
+	cases := []struct {
+		f       Function
+		a, b, c float64
+		correct float64
+	}{
+		// p.v. int 1/(x-c) is log((b-c)/(c-a))
+		{func(float64) float64 { return 1 }, 0, 3, 1, math.Log(2)},
+		// x^2 = (x-c)(x+c) + c^2
+		{func(x float64) float64 { return x * x }, -1, 3, .5, 4 + 2 + .25*math.Log(2.5/1.5)},
+		// The singular derivative at 0 forces the window to be halved
+		{func(x float64) float64 { return math.Sqrt(x) }, 0, 4, 1, 4 - math.Log(3)},
+	}
+	for i, c := range cases {
+		if msg, ok := checkValue(PrincipalValue(c.f, c.a, c.b, c.c, tol), c.correct, 10*tol); !ok {
+			t.Errorf("case %d: %s", i, msg)
+		}
+	}
+
+	if !math.IsNaN(PrincipalValue(math.Exp, 0, 1, 2, tol)) {
+		t.Error("expected NaN for a pole outside the interval")
+	}
+}
@@ -0,0 +1,47 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFixedIntegrate(t *testing.T) {
+	for _, n := range []int{1, 3, 10} {
+		r, err := FixedIntegrate(math.Exp, 0, 1, n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.Evals != 15*n || r.Panels != n {
+			t.Errorf("%d panels: %d evaluations in %d panels", n, r.Evals, r.Panels)
+		}
+		if msg, ok := checkValue(r.Value, math.E-1, 1e-14); !ok {
+			t.Errorf("%d panels: %s", n, msg)
+		}
+	}
+
+	// The error estimate bounds the error and shrinks with n
+	f := func(x float64) float64 { return math.Sqrt(x) }
+	prev := math.Inf(1)
+	for _, n := range []int{4, 16, 64} {
+		r, _ := FixedIntegrate(f, 1, 0, n, WithKronrodOrder(21))
+		if r.Evals != 21*n {
+			t.Errorf("%d panels: %d evaluations", n, r.Evals)
+		}
+		if got := math.Abs(r.Value + 2.0/3); got > r.Error || r.Error >= prev {
+			t.Errorf("%d panels: error %g, estimate %g", n, got, r.Error)
+		}
+		prev = r.Error
+	}
+
+	r, err := FixedIntegrate(func(x float64) float64 { return math.Exp(-x * x) }, math.Inf(-1), math.Inf(1), 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg, ok := checkValue(r.Value, math.SqrtPi, 1e-6); !ok {
+		t.Error(msg)
+	}
+
+	if r, err := FixedIntegrate(math.Exp, 0, 1, 0); err != ErrInvalidInput || !math.IsNaN(r.Value) {
+		t.Errorf("got %g, %v for no panels", r.Value, err)
+	}
+}
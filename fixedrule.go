@@ -0,0 +1,202 @@
+package goint
+
+import (
+	"math"
+)
+
+// The most points NewtonCotes accepts; beyond this the closed formulas
+// have weights of mixed sign and amplify rounding error.
+const maxNewtonCotes = 11
+
+// The most points OpenNewtonCotes accepts.
+const maxOpenNewtonCotes = 7
+
+// A FixedRule is an interpolatory quadrature rule on [-1, 1], applied to
+// other intervals by an affine map.
+type FixedRule struct {
+	nodes, weights []float64
+}
+
+// NewtonCotes returns the closed Newton-Cotes rule with n equally spaced
+// points, including both endpoints: n = 2 is the trapezoid rule, 3
+// Simpson's rule and 5 Boole's rule. It is nil unless 2 <= n <= 11. The
+// rule can drive the adaptive integrator through WithRule, trading
+// per-panel order against subdivision depth.
+func NewtonCotes(n int) *FixedRule {
+	if n < 2 || n > maxNewtonCotes {
+		return nil
+	}
+
+	nodes := make([]float64, n)
+	for i := range nodes {
+		nodes[i] = -1 + 2*float64(i)/float64(n-1)
+	}
+
+	return interpolatoryRule(nodes)
+}
+
+// OpenNewtonCotes returns the open Newton-Cotes rule with n equally spaced
+// interior points, which divide the interval into n + 1 equal parts: n = 1
+// is the midpoint rule and n = 3 Milne's rule. It is nil unless
+// 1 <= n <= 7. Open rules never evaluate the integrand at the ends of a
+// panel, so with WithRule they handle integrands that are infinite or
+// undefined at an endpoint, such as 1/sqrt(x) at zero.
+func OpenNewtonCotes(n int) *FixedRule {
+	if n < 1 || n > maxOpenNewtonCotes {
+		return nil
+	}
+
+	nodes := make([]float64, n)
+	for i := range nodes {
+		nodes[i] = -1 + 2*float64(i+1)/float64(n+1)
+	}
+
+	return interpolatoryRule(nodes)
+}
+
+// Milne returns Milne's rule, the open Newton-Cotes rule with 3 interior
+// points, 2/3 (2 f(-1/2) - f(0) + 2 f(1/2)) on [-1, 1], exact for cubics.
+func Milne() *FixedRule {
+	return OpenNewtonCotes(3)
+}
+
+// Weddle returns Weddle's rule on 7 equally spaced points,
+// (f0 + 5 f1 + f2 + 6 f3 + f4 + 5 f5 + f6) / 10 on [-1, 1], which perturbs
+// the 7-point Newton-Cotes rule by a sixth difference to get simple
+// positive weights, keeping exactness to degree 5.
+func Weddle() *FixedRule {
+	nodes := make([]float64, 7)
+	weights := make([]float64, 7)
+	for i, w := range []float64{1, 5, 1, 6, 1, 5, 1} {
+		nodes[i] = -1 + float64(i)/3
+		weights[i] = w / 10
+	}
+	return &FixedRule{nodes: nodes, weights: weights}
+}
+
+// WithOrder makes IntegrateGK estimate each panel with the closed
+// Newton-Cotes rule with the fewest points that integrates polynomials of
+// degree k exactly, from the trapezoid rule for k = 1 and Simpson's rule
+// for 2 and 3 up to 11 points for k = 10 and 11, applied as WithRule
+// applies it. Higher degrees reduce the subdivision needed for smooth
+// integrands. Zero restores the default Gauss-Kronrod pair, and any other
+// k outside 1 to 11 gives ErrInvalidOption. WithRule takes precedence.
+func WithOrder(k int) Option {
+	return func(c *config) { c.order = k }
+}
+
+// Returns the rule selected by WithOrder(k), or nil if there is none.
+func orderRule(k int) *FixedRule {
+	if k < 1 || k > maxNewtonCotes {
+		return nil
+	}
+	if k%2 == 0 {
+		return NewtonCotes(k + 1)
+	}
+	return NewtonCotes(max(k, 2))
+}
+
+// GaussLegendre returns the n-point Gauss-Legendre rule, which is exact for
+// polynomials of degree 2n - 1, or nil if n < 1. Nodes and weights are
+// computed by Newton's method on the Legendre polynomial. Used with
+// WithRule it drives the adaptive integrator as a composite rule.
+func GaussLegendre(n int) *FixedRule {
+	if n < 1 {
+		return nil
+	}
+
+	nodes, weights := gaussLegendre(n)
+	return &FixedRule{nodes: nodes, weights: weights}
+}
+
+// Returns the rule on [-1, 1] with the given nodes that integrates every
+// polynomial of degree below len(nodes) exactly. The moment equations are
+// posed in the Chebyshev basis, which keeps them well conditioned.
+func interpolatoryRule(nodes []float64) *FixedRule {
+	n := len(nodes)
+	A := newMatrix(n, n)
+	moments := make([]float64, n)
+	for j, x := range nodes {
+		t0, t1 := 1.0, x
+		for i := 0; i < n; i++ {
+			A[i][j] = t0
+			t0, t1 = t1, 2*x*t1-t0
+		}
+	}
+	for i := 0; i < n; i += 2 {
+		moments[i] = 2 / (1 - float64(i*i))
+	}
+
+	weights, err := solve(A, moments)
+	if err != nil {
+		return nil
+	}
+
+	return &FixedRule{nodes: append([]float64(nil), nodes...), weights: weights}
+}
+
+// NewFixedRule returns the rule on [-1, 1] with the given nodes and
+// weights, which are copied, or nil unless they are nonempty, of equal
+// length and finite, with the nodes in [-1, 1].
+func NewFixedRule(nodes, weights []float64) *FixedRule {
+	if len(nodes) == 0 || len(nodes) != len(weights) {
+		return nil
+	}
+	for i, x := range nodes {
+		if !(x >= -1 && x <= 1) || math.IsNaN(weights[i]) || math.IsInf(weights[i], 0) {
+			return nil
+		}
+	}
+
+	return &FixedRule{
+		nodes:   append([]float64(nil), nodes...),
+		weights: append([]float64(nil), weights...),
+	}
+}
+
+// Nodes returns a copy of the nodes of the rule on [-1, 1].
+func (r *FixedRule) Nodes() []float64 {
+	return append([]float64(nil), r.nodes...)
+}
+
+// Weights returns a copy of the weights of the rule on [-1, 1],
+// matching Nodes. On [a, b] they scale by (b - a) / 2.
+func (r *FixedRule) Weights() []float64 {
+	return append([]float64(nil), r.weights...)
+}
+
+// Apply estimates the integral of f over the finite interval [a, b] with
+// the rule.
+func (r *FixedRule) Apply(f Function, a, b float64) float64 {
+	center, half := (a+b)/2, (b-a)/2
+	sum := 0.0
+	for i, x := range r.nodes {
+		sum += r.weights[i] * f(center+half*x)
+	}
+	return half * sum
+}
+
+// Composite estimates the integral of f over the finite interval [a, b]
+// by applying the rule on n equal panels. The result is NaN if n < 1.
+func (r *FixedRule) Composite(f Function, a, b float64, n int) float64 {
+	if n < 1 {
+		return math.NaN()
+	}
+
+	h := (b - a) / float64(n)
+	sum := 0.0
+	for i := 0; i < n; i++ {
+		sum += r.Apply(f, a+float64(i)*h, a+float64(i+1)*h)
+	}
+	return sum
+}
+
+// Estimate implements Rule, applying the rule to both halves of [a, b]
+// and taking the difference from the rule on the whole interval as the
+// error.
+func (r *FixedRule) Estimate(f Function, a, b float64) (value, err float64) {
+	m := a + (b-a)/2
+	whole := r.Apply(f, a, b)
+	halves := r.Apply(f, a, m) + r.Apply(f, m, b)
+	return halves, math.Abs(halves - whole)
+}
@@ -0,0 +1,232 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNewtonCotes(t *testing.T) {
+	// Boole's rule on [-1, 1]
+	boole := []float64{7.0 / 45, 32.0 / 45, 12.0 / 45, 32.0 / 45, 7.0 / 45}
+	r := NewtonCotes(5)
+	for i, w := range boole {
+		if msg, ok := checkValue(r.weights[i], w, 1e-14); !ok {
+			t.Errorf("weight %d: %s", i, msg)
+		}
+	}
+
+	// An n-point rule is exact to degree n - 1, or n when n is odd
+	for n := 2; n <= maxNewtonCotes; n++ {
+		degree := n - 1
+		if n%2 == 1 {
+			degree = n
+		}
+		p := func(x float64) float64 { return math.Pow(x, float64(degree)) + x }
+		correct := (math.Pow(2, float64(degree+1)) - 1) / float64(degree+1)
+		if msg, ok := checkValue(NewtonCotes(n).Apply(p, 1, 2), correct+1.5, 1e-11); !ok {
+			t.Errorf("%d points: %s", n, msg)
+		}
+	}
+
+	if NewtonCotes(1) != nil || NewtonCotes(12) != nil {
+		t.Error("expected nil outside 2..11 points")
+	}
+}
+
+func TestWithRule(t *testing.T) {
+	f := func(x float64) float64 { return 1 / (1e-2 + x*x) }
+	correct := 20 * math.Atan(10)
+
+	for _, n := range []int{6, 7, 8, 10} {
+		r, err := IntegrateGK(f, -1, 1, 1e-9, WithRule(NewtonCotes(n)))
+		if err != nil {
+			t.Errorf("%d points: %v", n, err)
+		}
+		if msg, ok := checkValue(r.Value, correct, 1e-8); !ok {
+			t.Errorf("%d points: %s", n, msg)
+		}
+	}
+}
+
+func TestOpenNewtonCotes(t *testing.T) {
+	// Milne's rule weights 2, -1, 2 over thirds of [-1, 1]
+	milne := []float64{4.0 / 3, -2.0 / 3, 4.0 / 3}
+	r := OpenNewtonCotes(3)
+	for i, w := range milne {
+		if msg, ok := checkValue(r.weights[i], w, 1e-14); !ok {
+			t.Errorf("weight %d: %s", i, msg)
+		}
+	}
+
+	// An endpoint singularity is never evaluated
+	f := func(x float64) float64 {
+		if x == 0 {
+			t.Fatal("evaluated at the endpoint")
+		}
+		return 1 / math.Sqrt(x)
+	}
+	for n := 1; n <= maxOpenNewtonCotes; n++ {
+		res, err := IntegrateGK(f, 0, 1, 1e-6, WithRule(OpenNewtonCotes(n)))
+		if err != nil {
+			t.Errorf("%d points: %v", n, err)
+		}
+		if msg, ok := checkValue(res.Value, 2, 1e-5); !ok {
+			t.Errorf("%d points: %s", n, msg)
+		}
+	}
+
+	if OpenNewtonCotes(0) != nil || OpenNewtonCotes(8) != nil {
+		t.Error("expected nil outside 1..7 points")
+	}
+}
+
+func TestGaussLegendreRule(t *testing.T) {
+	for _, n := range []int{1, 2, 5, 20, 64} {
+		r := GaussLegendre(n)
+		k := float64(2*n - 2)
+		p := func(x float64) float64 { return math.Pow(x, k) + math.Pow(x, k+1) }
+		if msg, ok := checkValue(r.Apply(p, -1, 1), 2/(k+1), 1e-13); !ok {
+			t.Errorf("%d points: %s", n, msg)
+		}
+	}
+
+	if msg, ok := checkValue(GaussLegendre(4).Composite(math.Exp, 0, 1, 8), math.E-1, 1e-15); !ok {
+		t.Error(msg)
+	}
+
+	r, err := IntegrateGK(func(x float64) float64 { return math.Sqrt(x) }, 0, 1, 1e-10, WithRule(GaussLegendre(10)))
+	if err != nil {
+		t.Error(err)
+	}
+	if msg, ok := checkValue(r.Value, 2.0/3, 1e-9); !ok {
+		t.Error(msg)
+	}
+
+	if GaussLegendre(0) != nil {
+		t.Error("expected nil for no points")
+	}
+}
+
+func TestRuleNodes(t *testing.T) {
+	// Rebuilding a rule from its nodes and weights reproduces it
+	gl := GaussLegendre(7)
+	rebuilt := NewFixedRule(gl.Nodes(), gl.Weights())
+	if got, want := rebuilt.Apply(math.Exp, 0, 1), gl.Apply(math.Exp, 0, 1); got != want {
+		t.Errorf("got %g, expected %g", got, want)
+	}
+	nodes := gl.Nodes()
+	nodes[0] = 7
+	if gl.Nodes()[0] == 7 {
+		t.Error("Nodes returned the rule's own slice")
+	}
+
+	if NewFixedRule([]float64{0, 2}, []float64{1, 1}) != nil || NewFixedRule([]float64{0}, nil) != nil {
+		t.Error("expected nil rules")
+	}
+
+	lag := GaussLaguerre(5)
+	w := NewWeightedRule(lag.Nodes(), lag.Weights(), nil)
+	if got, want := w.Apply(math.Cos), lag.Apply(math.Cos); got != want {
+		t.Errorf("got %g, expected %g", got, want)
+	}
+	if NewWeightedRule([]float64{math.NaN()}, []float64{1}, nil) != nil {
+		t.Error("expected nil rule")
+	}
+
+	d := DerivativeNewtonCotes(2)
+	if got := d.DerivativeWeights(); len(got) != 2 || got[0] != d.slopes[0] {
+		t.Errorf("derivative weights %v", got)
+	}
+	if got := d.Weights(); len(got) != 2 || got[0] != 1 {
+		t.Errorf("weights %v", got)
+	}
+
+	// The 15-point pair sums to the panel estimate
+	x, k, g := GaussKronrodNodes(15)
+	if len(x) != 15 || len(k) != 15 || len(g) != 15 {
+		t.Fatalf("%d nodes", len(x))
+	}
+	kSum, gSum := 0.0, 0.0
+	for i := range x {
+		if i > 0 && x[i] <= x[i-1] {
+			t.Errorf("nodes out of order at %d", i)
+		}
+		kSum += k[i] * math.Exp(x[i])
+		gSum += g[i] * math.Exp(x[i])
+	}
+	want, _ := gk15.panel(math.Exp, -1, 1)
+	if msg, ok := checkValue(kSum, want, 1e-15); !ok {
+		t.Error(msg)
+	}
+	if msg, ok := checkValue(gSum, math.E-1/math.E, 1e-14); !ok {
+		t.Error(msg)
+	}
+	if x, _, _ := GaussKronrodNodes(17); x != nil {
+		t.Error("expected nil nodes")
+	}
+}
+
+func TestMilneWeddle(t *testing.T) {
+	for _, c := range []struct {
+		name   string
+		r      *FixedRule
+		degree int
+	}{
+		{"Milne", Milne(), 3},
+		{"Weddle", Weddle(), 5},
+	} {
+		for d := 0; d <= c.degree+1; d++ {
+			got := c.r.Apply(func(x float64) float64 { return math.Pow(x, float64(d)) }, 0, 1)
+			_, ok := checkValue(got, 1/float64(d+1), 1e-15)
+			if ok != (d <= c.degree) {
+				t.Errorf("%s: degree %d gave %g", c.name, d, got)
+			}
+		}
+	}
+
+	// Weddle's rule is Newton-Cotes less a sixth difference
+	nc := NewtonCotes(7).Apply(math.Exp, 0, 1)
+	h := 1. / 6
+	diff := 0.0
+	for i, c := range []float64{1, -6, 15, -20, 15, -6, 1} {
+		diff += c * math.Exp(float64(i)*h)
+	}
+	if msg, ok := checkValue(Weddle().Apply(math.Exp, 0, 1), nc+h*diff/140, 1e-15); !ok {
+		t.Error(msg)
+	}
+
+	// Milne's rule never evaluates at the ends
+	r, err := IntegrateGK(func(x float64) float64 { return 1 / math.Sqrt(x) }, 0, 1, 1e-4, WithRule(Milne()))
+	if msg, ok := checkValue(r.Value, 2, 1e-4); err != nil || !ok {
+		t.Errorf("%s, %v", msg, err)
+	}
+}
+
+func TestWithOrder(t *testing.T) {
+	prev := math.MaxInt
+	for _, k := range []int{1, 3, 5, 7} {
+		r, err := IntegrateGK(math.Exp, 0, 1, 1e-10, WithOrder(k))
+		if err != nil {
+			t.Fatalf("order %d: %v", k, err)
+		}
+		if msg, ok := checkValue(r.Value, math.E-1, 1e-10); !ok {
+			t.Errorf("order %d: %s", k, msg)
+		}
+		if r.Panels >= prev {
+			t.Errorf("order %d: %d panels after %d", k, r.Panels, prev)
+		}
+		prev = r.Panels
+	}
+
+	// Order 4 is Boole's rule, exact for quintics in one panel
+	quintic := func(x float64) float64 { return x * x * x * x * x }
+	if r, _ := IntegrateGK(quintic, 0, 1, 1e-14, WithOrder(4)); r.Panels != 1 || r.Evals != 15 {
+		t.Errorf("%d panels, %d evaluations", r.Panels, r.Evals)
+	}
+
+	for _, k := range []int{-1, 12} {
+		if _, err := IntegrateGK(math.Exp, 0, 1, 1e-10, WithOrder(k)); err != ErrInvalidOption {
+			t.Errorf("order %d: got %v", k, err)
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package goint
+
+import (
+	"encoding/binary"
+)
+
+// A minimal FlatBuffers encoder and reader, enough for the metadata of
+// Arrow IPC streams. Objects are laid out front to back: each table is
+// preceded by its vtable and followed by the objects it refers to, so that
+// every offset points forward as the format requires, and every scalar
+// is aligned to its size from the start of the buffer.
+
+// An fbObject is a table, vector or string that can be referred to.
+type fbObject interface {
+	// Appends the object to b, returning the position offsets to it
+	// should point at.
+	write(b *[]byte) int
+}
+
+// An fbField is a field of a table: absent if size is zero, otherwise a
+// little-endian scalar of size bytes, or an offset to ref if it is set.
+type fbField struct {
+	size int
+	bits uint64
+	ref  fbObject
+}
+
+// Returns a scalar field of size bytes holding bits.
+func fbScalar(size int, bits uint64) fbField {
+	return fbField{size: size, bits: bits}
+}
+
+// Returns a field referring to obj.
+func fbRef(obj fbObject) fbField {
+	return fbField{size: 4, ref: obj}
+}
+
+// An fbTable is a table whose fields are indexed by their ids.
+type fbTable []fbField
+
+func (t fbTable) write(b *[]byte) int {
+	// Lay out the fields after the offset to the vtable
+	offsets := make([]int, len(t))
+	size := 4
+	for i, f := range t {
+		if f.size > 0 {
+			size = (size + f.size - 1) / f.size * f.size
+			offsets[i] = size
+			size += f.size
+		}
+	}
+
+	fbPad(b, 2)
+	vtable := len(*b)
+	*b = binary.LittleEndian.AppendUint16(*b, uint16(4+2*len(t)))
+	*b = binary.LittleEndian.AppendUint16(*b, uint16(size))
+	for _, off := range offsets {
+		*b = binary.LittleEndian.AppendUint16(*b, uint16(off))
+	}
+
+	fbPad(b, 8)
+	pos := len(*b)
+	*b = append(*b, make([]byte, size)...)
+	binary.LittleEndian.PutUint32((*b)[pos:], uint32(pos-vtable))
+	for i, f := range t {
+		if f.size > 0 && f.ref == nil {
+			var buf [8]byte
+			binary.LittleEndian.PutUint64(buf[:], f.bits)
+			copy((*b)[pos+offsets[i]:], buf[:f.size])
+		}
+	}
+	for i, f := range t {
+		if f.ref != nil {
+			fbPatch(*b, pos+offsets[i], f.ref.write(b))
+		}
+	}
+	return pos
+}
+
+// An fbTables is a vector of tables.
+type fbTables []fbTable
+
+func (v fbTables) write(b *[]byte) int {
+	fbPad(b, 4)
+	pos := len(*b)
+	*b = binary.LittleEndian.AppendUint32(*b, uint32(len(v)))
+	*b = append(*b, make([]byte, 4*len(v))...)
+	for i, t := range v {
+		fbPatch(*b, pos+4+4*i, t.write(b))
+	}
+	return pos
+}
+
+// An fbStructs is a vector of n structs of 8-byte fields, encoded in data.
+type fbStructs struct {
+	n    int
+	data []byte
+}
+
+func (v fbStructs) write(b *[]byte) int {
+	for len(*b)%8 != 4 {
+		*b = append(*b, 0)
+	}
+	pos := len(*b)
+	*b = binary.LittleEndian.AppendUint32(*b, uint32(v.n))
+	*b = append(*b, v.data...)
+	return pos
+}
+
+// An fbString is a string, stored with a terminating zero byte.
+type fbString string
+
+func (s fbString) write(b *[]byte) int {
+	fbPad(b, 4)
+	pos := len(*b)
+	*b = binary.LittleEndian.AppendUint32(*b, uint32(len(s)))
+	*b = append(*b, s...)
+	*b = append(*b, 0)
+	return pos
+}
+
+// Returns the flatbuffer with root table t.
+func fbBuild(t fbTable) []byte {
+	b := make([]byte, 4, 256)
+	fbPatch(b, 0, t.write(&b))
+	return b
+}
+
+// Pads b with zeros to a multiple of align bytes.
+func fbPad(b *[]byte, align int) {
+	for len(*b)%align != 0 {
+		*b = append(*b, 0)
+	}
+}
+
+// Sets the offset at position at of b to point at target.
+func fbPatch(b []byte, at, target int) {
+	binary.LittleEndian.PutUint32(b[at:], uint32(target-at))
+}
+
+// An fbView reads the table at position pos of a flatbuffer. Reads out of
+// range report absent fields rather than panicking, so that malformed
+// input can be rejected.
+type fbView struct {
+	buf []byte
+	pos int
+}
+
+// Returns the root table of buf.
+func fbRoot(buf []byte) (fbView, bool) {
+	pos, ok := fbUint(buf, 0, 4)
+	return fbView{buf, int(pos)}, ok && int(pos) < len(buf)
+}
+
+// Reads the little-endian unsigned integer of size bytes at position at
+// of buf.
+func fbUint(buf []byte, at, size int) (uint64, bool) {
+	if at < 0 || at > len(buf)-size {
+		return 0, false
+	}
+	var v uint64
+	for i := size - 1; i >= 0; i-- {
+		v = v<<8 | uint64(buf[at+i])
+	}
+	return v, true
+}
+
+// Returns the position of field id, if it is present.
+func (t fbView) field(id int) (int, bool) {
+	soffset, ok := fbUint(t.buf, t.pos, 4)
+	if !ok {
+		return 0, false
+	}
+	vtable := t.pos - int(int32(soffset))
+	size, ok := fbUint(t.buf, vtable, 2)
+	if !ok || 4+2*id+2 > int(size) {
+		return 0, false
+	}
+	off, ok := fbUint(t.buf, vtable+4+2*id, 2)
+	return t.pos + int(off), ok && off != 0
+}
+
+// Returns the scalar field id of size bytes, or zero if it is absent.
+func (t fbView) scalar(id, size int) uint64 {
+	at, ok := t.field(id)
+	if !ok {
+		return 0
+	}
+	v, _ := fbUint(t.buf, at, size)
+	return v
+}
+
+// Returns the position the offset in field id points at.
+func (t fbView) ref(id int) (int, bool) {
+	at, ok := t.field(id)
+	if !ok {
+		return 0, false
+	}
+	off, ok := fbUint(t.buf, at, 4)
+	return at + int(off), ok && at+int(off) < len(t.buf)
+}
+
+// Returns the table field id.
+func (t fbView) table(id int) (fbView, bool) {
+	at, ok := t.ref(id)
+	return fbView{t.buf, at}, ok
+}
+
+// Returns the vector of tables in field id.
+func (t fbView) tables(id int) ([]fbView, bool) {
+	at, ok := t.ref(id)
+	if !ok {
+		return nil, false
+	}
+	n, ok := fbUint(t.buf, at, 4)
+	if !ok || n > uint64(len(t.buf)-at)/4 {
+		return nil, false
+	}
+
+	tables := make([]fbView, n)
+	for i := range tables {
+		elem := at + 4 + 4*i
+		off, _ := fbUint(t.buf, elem, 4)
+		tables[i] = fbView{t.buf, elem + int(off)}
+	}
+	return tables, true
+}
+
+// Returns the contents of the string or vector of elemSize-byte elements
+// in field id.
+func (t fbView) vector(id, elemSize int) ([]byte, bool) {
+	at, ok := t.ref(id)
+	if !ok {
+		return nil, false
+	}
+	n, ok := fbUint(t.buf, at, 4)
+	if !ok || n > uint64(len(t.buf)-at-4)/uint64(elemSize) {
+		return nil, false
+	}
+	return t.buf[at+4 : at+4+int(n)*elemSize], true
+}
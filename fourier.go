@@ -0,0 +1,77 @@
+package goint
+
+import (
+	"math"
+)
+
+const (
+	// The most half-period cycles IntegrateFourier sums.
+	fourierMaxCycles = 100
+
+	// The ratio between the tolerances of successive cycles, from
+	// QUADPACK's dqawf.
+	fourierRatio = .9
+)
+
+// IntegrateFourier integrates f(x) cos(omega x), or f(x) sin(omega x) when
+// kind is Sine, over [a, +Inf) to within tol in the manner of QUADPACK's
+// dqawf. The range is cut at the zeros of the oscillating factor, each
+// half-period cycle is integrated by IntegrateGK, the k-th to within
+// tol (1 - p) p^k with p = 0.9 so that the tolerances sum to tol, and the
+// partial sums, which alternate in sign for decaying f, are extrapolated
+// to their limit with Wynn's epsilon algorithm. The error estimate adds
+// the change in the extrapolated limit over the last two cycles to the
+// errors of the cycles. The integral must converge, if only
+// conditionally: f need not be integrable, but must decay to zero. An
+// infinite or NaN a, a zero or NaN omega, or a NaN or negative tolerance
+// gives a NaN estimate and ErrInvalidInput; if the tolerance is not met
+// within 100 cycles, the best estimate is returned with ErrNotConverged.
+func IntegrateFourier(f Function, a, omega float64, kind Oscillation, tol float64) (Result, error) {
+	if math.IsNaN(a) || math.IsInf(a, 0) || math.IsNaN(omega) || omega == 0 ||
+		!(tol >= 0) || (kind != Cosine && kind != Sine) {
+		return Result{Value: math.NaN()}, ErrInvalidInput
+	}
+
+	trig := math.Cos
+	phase := .5
+	if kind == Sine {
+		trig, phase = math.Sin, 0
+	}
+	g := func(x float64) float64 { return f(x) * trig(omega*x) }
+
+	// The zeros of the oscillating factor are (k + phase) half
+	half := math.Pi / math.Abs(omega)
+	k := math.Floor(a/half-phase) + 1
+
+	var r Result
+	sums := make([]float64, 0, fourierMaxCycles)
+	limits := make([]float64, 0, fourierMaxCycles)
+	sum, cycleTol, cycleErr := 0.0, tol*(1-fourierRatio), 0.0
+	for left := a; len(sums) < fourierMaxCycles; k++ {
+		right := (k + phase) * half
+		piece, _ := IntegrateGK(g, left, right, cycleTol)
+		left, cycleTol = right, cycleTol*fourierRatio
+
+		sum += piece.Value
+		cycleErr += piece.Error
+		r.Evals += piece.Evals
+		r.Panels += piece.Panels
+		sums = append(sums, sum)
+		limits = append(limits, wynnEpsilon(sums))
+
+		n := len(limits)
+		if n < 3 {
+			continue
+		}
+		r.Value = limits[n-1]
+		r.Error = math.Abs(limits[n-1]-limits[n-2]) + math.Abs(limits[n-2]-limits[n-3]) + cycleErr
+		if r.Error <= tol {
+			return r, nil
+		}
+		if math.IsNaN(r.Value) {
+			break
+		}
+	}
+
+	return r, ErrNotConverged
+}
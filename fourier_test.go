@@ -0,0 +1,36 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestIntegrateFourier(t *testing.T) {
+	const tol = 1e-8
+	cases := []struct {
+		f        Function
+		a, omega float64
+		kind     Oscillation
+		correct  float64
+	}{
+		{func(x float64) float64 { return 1 / (1 + x*x) }, 0, 1, Cosine, math.Pi / (2 * math.E)},
+		{func(x float64) float64 { return 1 / x }, 1, 1, Sine, math.Pi/2 - 0.946083070367183015},
+		{func(x float64) float64 { return 1 / math.Sqrt(x) }, 0, 1, Sine, math.Sqrt(math.Pi / 2)},
+		{func(x float64) float64 { return 1 / math.Sqrt(x) }, 0, 4, Cosine, math.Sqrt(math.Pi / 8)},
+		{func(x float64) float64 { return math.Exp(-x) }, 0, -3, Sine, -.3},
+	}
+
+	for i, c := range cases {
+		r, err := IntegrateFourier(c.f, c.a, c.omega, c.kind, tol)
+		if err != nil {
+			t.Errorf("case %d: %v", i, err)
+		}
+		if msg, ok := checkValue(r.Value, c.correct, 10*tol); !ok {
+			t.Errorf("case %d: %s", i, msg)
+		}
+	}
+
+	if _, err := IntegrateFourier(math.Exp, 0, 0, Cosine, tol); err != ErrInvalidInput {
+		t.Errorf("zero frequency gave %v", err)
+	}
+}
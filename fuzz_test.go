@@ -0,0 +1,163 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+// Returns one of a family of synthetic integrands selected by kind and
+// shaped by p: smooth, peaked, kinked, discontinuous, singular, oscillatory
+// and NaN-producing functions, which between them exercise every branch
+// of the adaptive engines.
+func synthetic(kind uint8, p float64) Function {
+	switch kind % 8 {
+	case 0:
+		return func(x float64) float64 { return math.Exp(p * x) }
+	case 1:
+		return func(x float64) float64 { return 1 / (1e-6 + (x-p)*(x-p)) }
+	case 2:
+		return func(x float64) float64 { return math.Abs(x - p) }
+	case 3:
+		return func(x float64) float64 {
+			if x < p {
+				return 0
+			}
+			return 1
+		}
+	case 4:
+		return func(x float64) float64 { return 1 / math.Sqrt(math.Abs(x-p)) }
+	case 5:
+		return func(x float64) float64 { return math.Sin(p * x) }
+	case 6:
+		return func(x float64) float64 { return math.NaN() }
+	}
+	return func(x float64) float64 { return math.Exp(-x*x) * p }
+}
+
+// Seeds covering the pathological inputs: NaN and infinite bounds, equal
+// bounds, zero, negative and NaN tolerances, denormal spans and reversed
+// infinities.
+func addSeeds(f *testing.F) {
+	nan, inf := math.NaN(), math.Inf(1)
+	seeds := []struct {
+		kind      uint8
+		p         float64
+		a, b, tol float64
+	}{
+		{0, 1, 0, 1, 1e-8},
+		{0, 1, nan, 1, 1e-8},
+		{0, 1, 0, nan, 1e-8},
+		{0, 1, 1, 1, 0},
+		{0, 1, 0, 1, 0},
+		{2, .3, 0, 1, 0},
+		{0, 1, 0, 1, -1},
+		{0, 1, 0, 1, nan},
+		{7, 1, 0, 5e-324, 1e-12},
+		{7, 1, 1, 1 + 2e-16, 1e-300},
+		{7, 1, inf, -inf, 1e-8},
+		{7, 1, -inf, -3, 1e-8},
+		{6, 0, 0, 1, 1e-8},
+		{6, 0, -inf, inf, 1e-8},
+		{3, .5, 0, 1, 1e-300},
+		{4, .25, 0, 1, 1e-10},
+		{5, 1e6, 0, 1, 1e-10},
+		{1, 1e300, -1e300, 1e300, 1e-6},
+	}
+	for _, s := range seeds {
+		f.Add(s.kind, s.p, s.a, s.b, s.tol)
+	}
+}
+
+func FuzzIntegrate(f *testing.F) {
+	addSeeds(f)
+	f.Fuzz(func(t *testing.T, kind uint8, p, a, b, tol float64) {
+		Integrate(synthetic(kind, p), a, b, tol)
+	})
+}
+
+func FuzzIntegrateGK(f *testing.F) {
+	addSeeds(f)
+	f.Fuzz(func(t *testing.T, kind uint8, p, a, b, tol float64) {
+		r, err := IntegrateGK(synthetic(kind, p), a, b, tol)
+		if err == nil && !(r.Error <= tol) {
+			t.Errorf("accepted error %v above tolerance %v", r.Error, tol)
+		}
+		if r.Panels > maxPanels {
+			t.Errorf("%d panels", r.Panels)
+		}
+	})
+}
+
+func FuzzAdaptiveSimpson(f *testing.F) {
+	addSeeds(f)
+	f.Fuzz(func(t *testing.T, kind uint8, p, a, b, tol float64) {
+		AdaptiveSimpson(synthetic(kind, p), a, b, tol)
+	})
+}
+
+func FuzzAdaptiveLobatto(f *testing.F) {
+	addSeeds(f)
+	f.Fuzz(func(t *testing.T, kind uint8, p, a, b, tol float64) {
+		AdaptiveLobatto(synthetic(kind, p), a, b, tol)
+	})
+}
+
+func FuzzRomberg(f *testing.F) {
+	addSeeds(f)
+	f.Fuzz(func(t *testing.T, kind uint8, p, a, b, tol float64) {
+		Romberg(synthetic(kind, p), a, b, tol)
+	})
+}
+
+func TestPathologicalInputs(t *testing.T) {
+	nan, inf := math.NaN(), math.Inf(1)
+
+	for _, bounds := range [][2]float64{{nan, 1}, {0, nan}} {
+		a, b := bounds[0], bounds[1]
+		if v := Integrate(math.Exp, a, b, 1e-8); !math.IsNaN(v) {
+			t.Errorf("Integrate over [%v, %v] gave %v", a, b, v)
+		}
+		if v := AdaptiveSimpson(math.Exp, a, b, 1e-8); !math.IsNaN(v) {
+			t.Errorf("AdaptiveSimpson over [%v, %v] gave %v", a, b, v)
+		}
+		if v := AdaptiveLobatto(math.Exp, a, b, 1e-8); !math.IsNaN(v) {
+			t.Errorf("AdaptiveLobatto over [%v, %v] gave %v", a, b, v)
+		}
+		if r, err := IntegrateGK(math.Exp, a, b, 1e-8); err != ErrInvalidInput || !math.IsNaN(r.Value) {
+			t.Errorf("IntegrateGK over [%v, %v] gave %+v, %v", a, b, r, err)
+		}
+	}
+	for _, tol := range []float64{nan, -1} {
+		if _, err := IntegrateGK(math.Exp, 0, 1, tol); err != ErrInvalidInput {
+			t.Errorf("IntegrateGK with tolerance %v gave %v", tol, err)
+		}
+		if _, err := IntegrateCubature(func(x []float64) float64 { return 1 }, []float64{0}, []float64{1}, tol); err != ErrInvalidInput {
+			t.Errorf("IntegrateCubature with tolerance %v gave %v", tol, err)
+		}
+	}
+
+	// A zero tolerance refines as far as possible and still returns
+	if msg, ok := checkValue(Integrate(math.Exp, 0, 1, 0), math.E-1, 1e-12); !ok {
+		t.Error(msg)
+	}
+	if msg, ok := checkValue(AdaptiveSimpson(math.Exp, 0, 1, 0), math.E-1, 1e-12); !ok {
+		t.Error(msg)
+	}
+	if msg, ok := checkValue(AdaptiveLobatto(math.Exp, 0, 1, 0), math.E-1, 1e-12); !ok {
+		t.Error(msg)
+	}
+
+	// Reversed infinite limits, and a tail that used to loop forever
+	if msg, ok := checkValue(Integrate(math.Exp, 0, -inf, 1e-8), -1, 1e-6); !ok {
+		t.Error(msg)
+	}
+	if msg, ok := checkValue(Integrate(math.Exp, -inf, -3, 1e-8), math.Exp(-3), 1e-6); !ok {
+		t.Error(msg)
+	}
+
+	// A NaN integrand stops refinement at once
+	r, err := IntegrateGK(synthetic(6, 0), 0, 1, 1e-8)
+	if err != ErrNotConverged || !math.IsNaN(r.Value) || r.Panels != 1 {
+		t.Errorf("NaN integrand gave %+v, %v", r, err)
+	}
+}
@@ -0,0 +1,182 @@
+package goint
+
+import (
+	"math"
+	"sort"
+)
+
+// Computes the nodes and weights of the n-point Gaussian quadrature rule
+// for the measure whose monic orthogonal polynomials satisfy the three-term
+// recurrence with coefficients alpha[0:n] and beta[1:n], using the
+// Golub-Welsch algorithm. The total mass of the measure is mu0. beta[0] is
+// ignored. Nodes are returned in increasing order.
+func golubWelsch(alpha, beta []float64, mu0 float64) ([]float64, []float64) {
+	n := len(alpha)
+	d := make([]float64, n)
+	e := make([]float64, n)
+	copy(d, alpha)
+	for i := 1; i < n; i++ {
+		e[i-1] = math.Sqrt(beta[i])
+	}
+
+	nodes, z := tridiagEigen(d, e)
+
+	weights := make([]float64, n)
+	for i := range weights {
+		weights[i] = mu0 * z[i] * z[i]
+	}
+
+	sortRule(nodes, weights)
+	return nodes, weights
+}
+
+// Computes the eigenvalues of the symmetric tridiagonal matrix with diagonal
+// d and subdiagonal e[0:n-1] using the implicit QL algorithm, along with the
+// first component of each normalized eigenvector. Both d and e are
+// overwritten.
+func tridiagEigen(d, e []float64) ([]float64, []float64) {
+	n := len(d)
+	z := make([]float64, n)
+	if n == 0 {
+		return d, z
+	}
+	z[0] = 1
+	e[n-1] = 0
+
+	for l := 0; l < n; l++ {
+		for iter := 0; iter < 60; iter++ {
+			m := l
+			for ; m < n-1; m++ {
+				dd := math.Abs(d[m]) + math.Abs(d[m+1])
+				if math.Abs(e[m]) <= 1e-17*dd {
+					break
+				}
+			}
+			if m == l {
+				break
+			}
+
+			g := (d[l+1] - d[l]) / (2 * e[l])
+			r := math.Hypot(g, 1)
+			g = d[m] - d[l] + e[l]/(g+math.Copysign(r, g))
+			s, c, p := 1.0, 1.0, 0.0
+
+			i := m - 1
+			for ; i >= l; i-- {
+				f := s * e[i]
+				b := c * e[i]
+				r = math.Hypot(f, g)
+				e[i+1] = r
+				if r == 0 {
+					d[i+1] -= p
+					e[m] = 0
+					break
+				}
+				s = f / r
+				c = g / r
+				g = d[i+1] - p
+				r = (d[i]-g)*s + 2*c*b
+				p = s * r
+				d[i+1] = g + p
+				g = c*r - b
+
+				f = z[i+1]
+				z[i+1] = s*z[i] + c*f
+				z[i] = c*z[i] - s*f
+			}
+			if r == 0 && i >= l {
+				continue
+			}
+			d[l] -= p
+			e[l] = g
+			e[m] = 0
+		}
+	}
+
+	return d, z
+}
+
+// Sorts a rule's nodes into increasing order, permuting its weights to match.
+func sortRule(nodes, weights []float64) {
+	sort.Sort(ruleSorter{nodes, weights})
+}
+
+type ruleSorter struct {
+	nodes, weights []float64
+}
+
+func (r ruleSorter) Len() int           { return len(r.nodes) }
+func (r ruleSorter) Less(i, j int) bool { return r.nodes[i] < r.nodes[j] }
+func (r ruleSorter) Swap(i, j int) {
+	r.nodes[i], r.nodes[j] = r.nodes[j], r.nodes[i]
+	r.weights[i], r.weights[j] = r.weights[j], r.weights[i]
+}
+
+// Returns the n-point Gauss-Hermite rule for the weight exp(-x^2).
+func gaussHermite(n int) ([]float64, []float64) {
+	alpha := make([]float64, n)
+	beta := make([]float64, n)
+	for k := 1; k < n; k++ {
+		beta[k] = float64(k) / 2
+	}
+
+	return golubWelsch(alpha, beta, math.SqrtPi)
+}
+
+// Returns the n-point Gauss-Legendre rule on [-1, 1], computing each node
+// by Newton's method on the Legendre polynomial P_n.
+func gaussLegendre(n int) ([]float64, []float64) {
+	nodes := make([]float64, n)
+	weights := make([]float64, n)
+
+	for i := 0; i < (n+1)/2; i++ {
+		x := math.Cos(math.Pi * (float64(i) + .75) / (float64(n) + .5))
+		var dp float64
+		for iter := 0; iter < 100; iter++ {
+			var p float64
+			p, dp = legendre(n, x)
+			dx := p / dp
+			x -= dx
+			if math.Abs(dx) <= 1e-16 {
+				break
+			}
+		}
+		_, dp = legendre(n, x)
+
+		nodes[i] = -x
+		nodes[n-1-i] = x
+		weights[i] = 2 / ((1 - x*x) * dp * dp)
+		weights[n-1-i] = weights[i]
+	}
+
+	return nodes, weights
+}
+
+// Evaluates the Legendre polynomial P_n and its derivative at x.
+func legendre(n int, x float64) (float64, float64) {
+	p0, p1 := 1.0, x
+	if n == 0 {
+		return 1, 0
+	}
+	for k := 2; k <= n; k++ {
+		p0, p1 = p1, ((2*float64(k)-1)*x*p1-(float64(k)-1)*p0)/float64(k)
+	}
+
+	return p1, float64(n) * (x*p1 - p0) / (x*x - 1)
+}
+
+// Maps a rule on [-1, 1] onto the finite interval [a, b], returning new
+// slices.
+func mapRule(nodes, weights []float64, a, b float64) ([]float64, []float64) {
+	c := (a + b) / 2
+	h := (b - a) / 2
+
+	xs := make([]float64, len(nodes))
+	ws := make([]float64, len(weights))
+	for i := range nodes {
+		xs[i] = c + h*nodes[i]
+		ws[i] = h * weights[i]
+	}
+
+	return xs, ws
+}
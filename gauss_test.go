@@ -0,0 +1,27 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGaussLegendre(t *testing.T) {
+	for _, n := range []int{1, 2, 5, 12, 40} {
+		nodes, weights := gaussLegendre(n)
+
+		// The rule integrates x^k exactly over [-1, 1] for k < 2n
+		for k := 0; k < 2*n; k++ {
+			got := 0.0
+			for i, x := range nodes {
+				got += weights[i] * math.Pow(x, float64(k))
+			}
+			correct := 0.0
+			if k%2 == 0 {
+				correct = 2 / float64(k+1)
+			}
+			if msg, ok := checkValue(got, correct, 1e-13); !ok {
+				t.Errorf("n = %d, moment %d: %s", n, k, msg)
+			}
+		}
+	}
+}
@@ -0,0 +1,277 @@
+package goint
+
+import (
+	"errors"
+	"math"
+)
+
+const (
+	// The spacing of floating point numbers near one.
+	epsilon = 0x1p-52
+
+	// The smallest normal floating point number.
+	smallestNormal = 0x1p-1022
+)
+
+// ErrNotConverged is returned alongside the best available estimate when
+// an adaptive integrator stops before its error estimate meets the
+// requested tolerance.
+var ErrNotConverged = errors.New("goint: tolerance not reached")
+
+// ErrInvalidInput is returned with a NaN estimate when a bound is NaN or
+// the tolerance is NaN or negative.
+var ErrInvalidInput = errors.New("goint: invalid bounds or tolerance")
+
+// A Result reports the outcome of an adaptive integration.
+type Result struct {
+	// Value is the estimate of the integral.
+	Value float64
+
+	// Error is the estimated absolute error of Value.
+	Error float64
+
+	// Evals is the number of times the integrand was evaluated.
+	Evals int
+
+	// Panels is the number of panels in the final partition.
+	Panels int
+
+	// Precise is Value recomputed on the final partition in extended
+	// precision, and Rounding the difference between the two, when
+	// WithPrecisionCheck is given; both are zero otherwise.
+	Precise, Rounding float64
+
+	// Lower and Upper are Riemann sums over the final partition that
+	// bound the integral when WithMonotone is given; both are zero
+	// otherwise.
+	Lower, Upper float64
+}
+
+// The 15-point Kronrod abscissae on [-1, 1], from QUADPACK's dqk15; the
+// odd-indexed ones are the abscissae of the embedded 7-point Gauss rule.
+var gk15Nodes = [8]float64{
+	0.991455371120812639206854697526329,
+	0.949107912342758524526189684047851,
+	0.864864423359769072789712788640926,
+	0.741531185599394439863864773280788,
+	0.586087235467691130294144845693013,
+	0.405845151377397166906606412076961,
+	0.207784955007898467600689403773245,
+	0.000000000000000000000000000000000,
+}
+
+// The Kronrod weights matching gk15Nodes.
+var gk15Weights = [8]float64{
+	0.022935322010529224963732008058970,
+	0.063092092629978553290700663189204,
+	0.104790010322250183839876322541518,
+	0.140653259715525918745189590510238,
+	0.169004726639267902826583426598550,
+	0.190350578064785409913256402421014,
+	0.204432940075298892414161999234649,
+	0.209482141084727828012999174891714,
+}
+
+// The weights of the 7-point Gauss rule, matching gk15Nodes[1], [3], [5]
+// and [7].
+var g7Weights = [4]float64{
+	0.129484966168869693270611432679082,
+	0.279705391489276667901467771423780,
+	0.381830050505118944950369775488975,
+	0.417959183673469387755102040816327,
+}
+
+// IntegrateGK integrates f over [a, b] to within tol using the 15-point
+// Gauss-Kronrod pair, or another rule selected by WithKronrodOrder or
+// WithRule, on an adaptive partition, repeatedly bisecting the
+// panel with the largest error estimate as QUADPACK's QAG does. Effort is
+// concentrated where f has localized features instead of being spread
+// over the whole interval. Either limit may be infinite, in which case
+// the interval is first mapped onto a finite one by x = a + t/(1-t) or
+// its reflections, or by the map chosen with WithInfiniteMap. If the
+// tolerance cannot be met, the best estimate is returned along with
+// ErrNotConverged, or ErrMemoryLimit if the memory allowance ran out
+// first.
+func IntegrateGK(f Function, a, b, tol float64, opts ...Option) (Result, error) {
+	return integrateGK(f, a, b, tol, newConfig(opts), nil)
+}
+
+// Implements IntegrateGK, additionally stopping early once stop, if not
+// nil, accepts the current estimate and its error.
+func integrateGK(f Function, a, b, tol float64, cfg *config, stop func(value, err float64) bool) (Result, error) {
+	if math.IsNaN(a) || math.IsNaN(b) || !(tol >= 0) {
+		return Result{Value: math.NaN()}, ErrInvalidInput
+	}
+	if a == b {
+		return Result{}, nil
+	}
+	if a > b {
+		var flipped func(value, err float64) bool
+		if stop != nil {
+			flipped = func(value, err float64) bool { return stop(-value, err) }
+		}
+		r, err := integrateGK(f, b, a, tol, cfg, flipped)
+		r.Value, r.Precise = -r.Value, -r.Precise
+		r.Lower, r.Upper = -r.Upper, -r.Lower
+		return r, err
+	}
+
+	panel, err := cfg.panel(a, b)
+	if err != nil {
+		return Result{Value: math.NaN()}, err
+	}
+	if cfg.batch != nil {
+		panel = cfg.batch.rule(panel)
+	}
+	limit := cfg.panelLimit(panelBytes, maxPanels)
+	var full func(s *panelSet) bool
+	if cfg.memoryPolicy == MemoryMerge {
+		full = func(s *panelSet) bool { return s.mergedErr <= tol/2 && s.merge() }
+	}
+
+	evals := 0
+	counted := func(x float64) float64 {
+		evals++
+		return f(x)
+	}
+
+	m := cfg.meter()
+	if m != nil {
+		counted = m.record(counted)
+	}
+
+	var cached *cache
+	if cfg.cache != nil {
+		cached = &cache{s: cfg.cache}
+		if cfg.batch != nil {
+			cached.skip = func() bool { return cfg.batch.recording }
+		}
+		counted = cached.record(counted)
+	}
+
+	var samples *sampleSet
+	if cfg.monotone {
+		samples = &sampleSet{}
+		counted = samples.record(counted)
+	}
+
+	var records *evaluationLog
+	if cfg.log != nil {
+		records = newEvaluationLog(cfg.log)
+		if cfg.batch != nil {
+			records.skip = func() bool { return cfg.batch.recording }
+		}
+		counted = records.record(counted)
+	}
+
+	g, lo, hi := cfg.compactify(counted, a, b)
+	points := cfg.initialPoints(a, b, lo, hi)
+	if records != nil {
+		records.points = points
+		panel = records.rule(panel)
+	}
+	done := func(value, total, worst float64) bool {
+		return total <= tol || (stop != nil && stop(value, total)) || (m != nil && m.stop(len(points)-1))
+	}
+	var panels *panelSet
+	var extrapolated bool
+	var r Result
+	if cfg.extrapolate {
+		panels, r.Value, r.Error, extrapolated = adaptExtrapolated(g, points, panel, limit, done, tol)
+	} else {
+		panels = adapt(g, points, panel, limit, full, done)
+	}
+
+	r.Evals, r.Panels = evals, len(panels.lefts)
+	switch {
+	case extrapolated:
+	case cfg.doubleDouble:
+		r.Value, r.Error = panels.ddSum()
+	case cfg.strict:
+		r.Value, r.Error = panels.pairwiseSum()
+	default:
+		r.Value, r.Error = panels.sum()
+	}
+	if cfg.precisionCheck {
+		r.Precise = cfg.precise(g, panels)
+		r.Rounding = math.Abs(r.Value - r.Precise)
+		r.Evals = evals
+	}
+	var monotone bool
+	if cfg.monotone {
+		r.Lower, r.Upper, monotone = samples.bounds(counted, a, b)
+		r.Evals = evals
+	}
+	if !(r.Error <= tol) && !(stop != nil && stop(r.Value, r.Error)) {
+		if m != nil && (m.stopped || m.refused) {
+			return r, ErrBudgetExhausted
+		}
+		if limit < maxPanels && len(panels.lefts) >= limit {
+			return r, ErrMemoryLimit
+		}
+		return r, ErrNotConverged
+	}
+	if cfg.monotone && !monotone {
+		return r, ErrNotMonotone
+	}
+	if records != nil && records.err != nil {
+		return r, records.err
+	}
+	if cached != nil && cached.err != nil {
+		return r, cached.err
+	}
+
+	return r, nil
+}
+
+// Returns the panel rule selected by the configuration for integrating
+// over [a, b], which applies after the interval is compactified.
+func (c *config) panel(a, b float64) (panelRule, error) {
+	if c.kronrodOrder != 0 && !kronrodOrders[c.kronrodOrder] {
+		return nil, ErrInvalidOption
+	}
+	if c.infiniteMap < RationalMap || c.infiniteMap > LogarithmicMap {
+		return nil, ErrInvalidOption
+	}
+	if c.monotone && (math.IsInf(a, 0) || math.IsInf(b, 0)) {
+		return nil, ErrInvalidOption
+	}
+	if c.portable && (c.kronrodOrder != 0 || c.rule != nil || c.autoOrder || c.doubleDouble || c.order != 0) {
+		return nil, ErrInvalidOption
+	}
+	if c.order != 0 && orderRule(c.order) == nil {
+		return nil, ErrInvalidOption
+	}
+
+	kronrod := gk15
+	if c.kronrodOrder != 0 {
+		kronrod = kronrodOrder(c.kronrodOrder)
+	}
+	panel := kronrod.panel
+	switch {
+	case c.rule != nil:
+		panel = c.rule.Estimate
+	case c.order != 0:
+		panel = orderRule(c.order).Estimate
+	case c.autoOrder:
+		panel = autoPanel
+	case c.doubleDouble:
+		panel = kronrod.ddPanel
+	case c.portable:
+		panel = kronrod.portablePanel
+	}
+
+	if metric := c.errorMetric; metric != nil {
+		x, inner := c.uncompact(a, b), panel
+		panel = func(f Function, lo, hi float64) (float64, float64) {
+			value, err := inner(f, lo, hi)
+			xlo, xhi := x(lo), x(hi)
+			if xlo > xhi {
+				xlo, xhi = xhi, xlo
+			}
+			return value, metric(xlo, xhi, value, err)
+		}
+	}
+
+	return panel, nil
+}
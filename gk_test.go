@@ -0,0 +1,227 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestIntegrateGK(t *testing.T) {
+	const tol = 1e-10
+
+	inf := math.Inf(1)
+	cases := []struct {
+		f       Function
+		a, b    float64
+		correct float64
+	}{
+		{math.Exp, 0, 1, math.E - 1},
+		{math.Exp, 1, 0, 1 - math.E},
+		{func(x float64) float64 { return 1 / (1e-4 + x*x) }, -1, 1, 200 * math.Atan(100)},
+		{func(x float64) float64 { return math.Log(x) }, 0, 1, -1},
+		{func(x float64) float64 { return math.Exp(-x * x) }, -inf, inf, math.Sqrt(math.Pi)},
+		{func(x float64) float64 { return 1 / (1 + x*x) }, 0, inf, math.Pi / 2},
+		{math.Exp, -inf, 0, 1},
+	}
+
+	for i, c := range cases {
+		r, err := IntegrateGK(c.f, c.a, c.b, tol)
+		if err != nil {
+			t.Errorf("case %d: %v", i, err)
+		}
+		if msg, ok := checkValue(r.Value, c.correct, 10*tol); !ok {
+			t.Errorf("case %d: %s", i, msg)
+		}
+		if r.Error > tol || r.Evals != 15*(2*r.Panels-1) {
+			t.Errorf("case %d: inconsistent result %+v", i, r)
+		}
+	}
+
+	// A sharp peak needs far fewer evaluations than global refinement
+	evals := 0
+	peak := func(x float64) float64 {
+		evals++
+		return 1 / (1e-4 + x*x)
+	}
+	Integrate(peak, -1, 1, 1e-6)
+	r, _ := IntegrateGK(peak, -1, 1, 1e-6)
+	if r.Evals*4 > evals-r.Evals {
+		t.Errorf("%d evaluations against %d for Integrate", r.Evals, evals-r.Evals)
+	}
+
+	if _, err := IntegrateGK(math.Exp, 0, 1, 0); err != ErrNotConverged {
+		t.Errorf("expected ErrNotConverged, got %v", err)
+	}
+}
+
+func TestIntegrateGKMemory(t *testing.T) {
+	logx := func(x float64) float64 { return math.Log(x) }
+	budget := WithMaxMemoryBytes(8 * panelBytes)
+
+	r, err := IntegrateGK(logx, 0, 1, 1e-12, budget)
+	if err != ErrMemoryLimit || r.Panels > 8 {
+		t.Errorf("expected ErrMemoryLimit within 8 panels, got %v with %+v", err, r)
+	}
+
+	r, err = IntegrateGK(logx, 0, 1, 1e-9, budget, WithMemoryPolicy(MemoryMerge))
+	if err != nil || r.Panels > 8 {
+		t.Errorf("expected convergence within 8 panels, got %v with %+v", err, r)
+	}
+	if msg, ok := checkValue(r.Value, -1, 1e-8); !ok {
+		t.Error(msg)
+	}
+}
+
+func TestKronrodLegendre(t *testing.T) {
+	// The computed 15-point rule reproduces QUADPACK's constants
+	r := kronrodLegendre(7)
+	for i := range gk15.nodes {
+		if math.Abs(r.nodes[i]-gk15.nodes[i]) > 1e-14 ||
+			math.Abs(r.weights[i]-gk15.weights[i]) > 1e-14 ||
+			math.Abs(r.gauss[i]-gk15.gauss[i]) > 1e-14 {
+			t.Errorf("node %d: got (%v, %v, %v), want (%v, %v, %v)", i,
+				r.nodes[i], r.weights[i], r.gauss[i],
+				gk15.nodes[i], gk15.weights[i], gk15.gauss[i])
+		}
+	}
+
+	// Each pair is exact for polynomials of degree 3n + 1, and the
+	// embedded Gauss rule for degree 2n - 1
+	for order := range kronrodOrders {
+		n := (order - 1) / 2
+		r := kronrodOrder(order)
+		// Odd powers vanish by symmetry, so check the highest even one
+		k := float64((3*n + 1) &^ 1)
+		v, _ := r.panel(func(x float64) float64 { return math.Pow(x, k) }, -1, 1)
+		if msg, ok := checkValue(v, 2/(k+1), 1e-13); !ok {
+			t.Errorf("order %d: %s", order, msg)
+		}
+
+		g := 0.0
+		for i, x := range r.nodes {
+			p := math.Pow(x, float64(2*n-2))
+			if i == len(r.nodes)-1 {
+				g += r.gauss[i] * p
+			} else {
+				g += 2 * r.gauss[i] * p
+			}
+		}
+		if msg, ok := checkValue(g, 2/float64(2*n-1), 1e-13); !ok {
+			t.Errorf("order %d Gauss: %s", order, msg)
+		}
+	}
+}
+
+func TestWithKronrodOrder(t *testing.T) {
+	f := func(x float64) float64 { return math.Cos(50 * x) }
+	correct := 2 * math.Sin(50) / 50
+
+	low, _ := IntegrateGK(f, -1, 1, 1e-12)
+	high, err := IntegrateGK(f, -1, 1, 1e-12, WithKronrodOrder(61))
+	if err != nil {
+		t.Error(err)
+	}
+	if msg, ok := checkValue(high.Value, correct, 1e-11); !ok {
+		t.Error(msg)
+	}
+	if high.Panels >= low.Panels {
+		t.Errorf("order 61 used %d panels against %d for order 15", high.Panels, low.Panels)
+	}
+
+	if _, err := IntegrateGK(f, -1, 1, 1e-12, WithKronrodOrder(17)); err != ErrInvalidOption {
+		t.Errorf("expected ErrInvalidOption, got %v", err)
+	}
+}
+
+func TestWithErrorFunctional(t *testing.T) {
+	const tol = 1e-8
+
+	// The identity functional changes nothing
+	f := func(x float64) float64 { return math.Exp(-x * x) }
+	identity := func(a, b, value, err float64) float64 { return err }
+	plain, _ := IntegrateGK(f, math.Inf(-1), math.Inf(1), tol)
+	same, err := IntegrateGK(f, math.Inf(-1), math.Inf(1), tol, WithErrorFunctional(identity))
+	if err != nil || same != plain {
+		t.Errorf("identity functional: %v, %v; want %v", same, err, plain)
+	}
+
+	// Ignoring errors away from [0, 1] leaves the spike at 5 unresolved
+	spike := func(x float64) float64 { return math.Exp(x) + 1/(1e-6+(x-5)*(x-5)) }
+	mask := func(a, b, value, err float64) float64 {
+		if b <= 0 || a >= 1 {
+			return 0
+		}
+		return err
+	}
+	full, err := IntegrateGK(spike, 0, 10, tol)
+	if err != nil {
+		t.Fatal(err)
+	}
+	masked, err := IntegrateGK(spike, 0, 10, tol, WithErrorFunctional(mask))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if masked.Evals >= full.Evals {
+		t.Errorf("masked used %d evaluations, unmasked %d", masked.Evals, full.Evals)
+	}
+	if masked.Error > tol {
+		t.Errorf("masked error %v exceeds %v", masked.Error, tol)
+	}
+
+	// Panels are reported in the original coordinates
+	lowest := math.Inf(1)
+	record := func(a, b, value, err float64) float64 {
+		lowest = math.Min(lowest, a)
+		return err
+	}
+	IntegrateGK(f, math.Inf(-1), 0, tol, WithErrorFunctional(record))
+	if !math.IsInf(lowest, -1) {
+		t.Errorf("lowest panel bound %v, want -Inf", lowest)
+	}
+}
+
+func TestWithBreakpoints(t *testing.T) {
+	const tol = 1e-10
+
+	// A jump at 1/3 and a kink at 1/sqrt(2)
+	f := func(x float64) float64 {
+		v := math.Abs(x - math.Sqrt2/2)
+		if x > 1.0/3 {
+			v += 1
+		}
+		return v
+	}
+	correct := (math.Pow(math.Sqrt2/2, 2)+math.Pow(2-math.Sqrt2/2, 2))/2 + 2 - 1.0/3
+
+	plain, _ := IntegrateGK(f, 0, 2, tol)
+	split, err := IntegrateGK(f, 0, 2, tol, WithBreakpoints(1.0/3, math.Sqrt2/2, 7))
+	if err != nil {
+		t.Error(err)
+	}
+	if msg, ok := checkValue(split.Value, correct, tol); !ok {
+		t.Error(msg)
+	}
+	if split.Evals >= plain.Evals/4 {
+		t.Errorf("%d evaluations with breakpoints, %d without", split.Evals, plain.Evals)
+	}
+
+	// A jump on an infinite interval, and on a reversed one
+	g := func(x float64) float64 {
+		if x < 1 {
+			return 0
+		}
+		return math.Exp(-x)
+	}
+	for _, m := range []InfiniteMap{RationalMap, DoubleExponentialMap, TangentMap} {
+		r, err := IntegrateGK(g, math.Inf(-1), math.Inf(1), tol, WithBreakpoints(1), WithInfiniteMap(m))
+		if err != nil {
+			t.Errorf("map %d: %v", m, err)
+		}
+		if msg, ok := checkValue(r.Value, math.Exp(-1), tol); !ok {
+			t.Errorf("map %d: %s", m, msg)
+		}
+	}
+	r, _ := IntegrateGK(g, math.Inf(1), 0, tol, WithBreakpoints(1))
+	if msg, ok := checkValue(r.Value, -math.Exp(-1), tol); !ok {
+		t.Error(msg)
+	}
+}
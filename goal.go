@@ -0,0 +1,148 @@
+package goint
+
+import (
+	"math"
+)
+
+// IntegrateGoal estimates q(I), where I[i] is the integral of fs[i] over
+// [a, b], to within tol. Rather than meeting a tolerance on each integral,
+// effort is spent where it most reduces the error of q: the error of q is
+// estimated to first order as the sum over i of |dq/dI[i]| times the error
+// of I[i], and each step bisects the worst panel of the integral with the
+// largest such term. For a ratio I[0]/I[1], for instance, the smaller
+// integral is refined far more than the larger one. The sensitivities are
+// estimated by central differences, so q should be smooth near I.
+//
+// The returned Result describes q, counting the evaluations and panels of
+// all the integrals, and is followed by a Result for each integral. The
+// options are those of IntegrateGK; the memory allowance is shared by all
+// the partitions, and MemoryMerge is treated as MemoryFail.
+func IntegrateGoal(fs []Function, a, b float64, q func(integrals []float64) float64,
+	tol float64, opts ...Option) (Result, []Result, error) {
+	n := len(fs)
+	if a > b {
+		neg := func(integrals []float64) float64 {
+			flipped := make([]float64, len(integrals))
+			for i, v := range integrals {
+				flipped[i] = -v
+			}
+			return q(flipped)
+		}
+		r, rs, err := IntegrateGoal(fs, b, a, neg, tol, opts...)
+		for i := range rs {
+			rs[i].Value = -rs[i].Value
+		}
+		return r, rs, err
+	}
+
+	cfg := newConfig(opts)
+	rule, err := cfg.panel(a, b)
+	if err != nil {
+		return Result{Value: math.NaN()}, nil, err
+	}
+	limit := cfg.panelLimit(panelBytes, maxPanels)
+
+	results := make([]Result, n)
+	gs := make([]Function, n)
+	sets := make([]*panelSet, n)
+	values := make([]float64, n)
+	errs := make([]float64, n)
+	panels := 0
+	for i, f := range fs {
+		i, f := i, f
+		counted := func(x float64) float64 {
+			results[i].Evals++
+			return f(x)
+		}
+
+		sets[i] = &panelSet{}
+		if a == b {
+			continue
+		}
+
+		var lo, hi float64
+		gs[i], lo, hi = cfg.compactify(counted, a, b)
+		values[i], errs[i] = rule(gs[i], lo, hi)
+		sets[i].order = append(sets[i].order, sets[i].add(lo, hi, values[i], errs[i]))
+		panels++
+	}
+
+	terms := make([]float64, n)
+	estimate := func() (float64, float64) {
+		total := 0.0
+		for i := range values {
+			terms[i] = math.Abs(sensitivity(q, values, i, errs[i])) * errs[i]
+			total += terms[i]
+		}
+		return q(values), total
+	}
+
+	value, total := estimate()
+	converged := false
+	for {
+		if total <= tol {
+			// Rounding accumulates in the running totals; confirm them
+			for i, s := range sets {
+				values[i], errs[i] = s.sum()
+			}
+			if value, total = estimate(); total <= tol {
+				converged = true
+				break
+			}
+		}
+		if panels >= limit {
+			break
+		}
+
+		j := -1
+		for i := range terms {
+			if len(sets[i].order) > 0 && (j < 0 || terms[i] > terms[j]) {
+				j = i
+			}
+		}
+		if j < 0 {
+			break
+		}
+		dvalue, derr, ok := sets[j].bisect(gs[j], rule)
+		if !ok {
+			break
+		}
+		values[j] += dvalue
+		errs[j] += derr
+		panels++
+		value, total = estimate()
+	}
+
+	r := Result{Value: value, Error: total, Panels: panels}
+	for i, s := range sets {
+		results[i].Value, results[i].Error = values[i], errs[i]
+		results[i].Panels = len(s.lefts)
+		r.Evals += results[i].Evals
+	}
+
+	switch {
+	case converged:
+		return r, results, nil
+	case limit < maxPanels && panels >= limit:
+		return r, results, ErrMemoryLimit
+	}
+	return r, results, ErrNotConverged
+}
+
+// Estimates the derivative of q with respect to integrals[i] by a central
+// difference with a step comparable to err, restoring integrals[i].
+func sensitivity(q func([]float64) float64, integrals []float64, i int, err float64) float64 {
+	x := integrals[i]
+	h := math.Max(1e-4*math.Abs(x), err)
+	if h == 0 {
+		return 0
+	}
+
+	integrals[i] = x + h
+	up := q(integrals)
+	integrals[i] = x - h
+	down := q(integrals)
+	integrals[i] = x
+
+	return (up - down) / (2 * h)
+}
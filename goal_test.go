@@ -0,0 +1,53 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestIntegrateGoal(t *testing.T) {
+	const tol = 1e-9
+
+	// The mean of x under the density proportional to exp(-x^2 / 2) on
+	// [0, ∞) is sqrt(2/π)
+	w := func(x float64) float64 { return math.Exp(-x * x / 2) }
+	xw := func(x float64) float64 { return x * w(x) }
+	ratio := func(I []float64) float64 { return I[0] / I[1] }
+
+	r, rs, err := IntegrateGoal([]Function{xw, w}, 0, math.Inf(1), ratio, tol)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg, ok := checkValue(r.Value, math.Sqrt(2/math.Pi), tol); !ok {
+		t.Error(msg)
+	}
+	if r.Error > tol {
+		t.Errorf("error estimate %v exceeds %v", r.Error, tol)
+	}
+	if msg, ok := checkValue(rs[1].Value, math.Sqrt(math.Pi/2), 1e-6); !ok {
+		t.Error(msg)
+	}
+	if r.Evals != rs[0].Evals+rs[1].Evals || r.Panels != rs[0].Panels+rs[1].Panels {
+		t.Errorf("totals %+v do not match %+v", r, rs)
+	}
+
+	// A term that q ignores is never refined
+	spiky := func(x float64) float64 { return 1 / (1e-8 + x*x) }
+	first := func(I []float64) float64 { return I[0] }
+	_, rs, err = IntegrateGoal([]Function{math.Exp, spiky}, -1, 1, first, tol)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rs[1].Panels != 1 {
+		t.Errorf("ignored integral has %d panels", rs[1].Panels)
+	}
+
+	// Reversed limits negate every integral
+	r, rs, err = IntegrateGoal([]Function{math.Exp}, 1, 0, first, tol)
+	if err != nil || rs[0].Value != r.Value {
+		t.Fatalf("%+v, %+v, %v", r, rs, err)
+	}
+	if msg, ok := checkValue(r.Value, 1-math.E, tol); !ok {
+		t.Error(msg)
+	}
+}
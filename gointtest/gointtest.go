@@ -0,0 +1,85 @@
+// Package gointtest provides Function wrappers for testing code that
+// depends on the integrators in goint: counting evaluations, failing
+// after a budget, replaying scripted values, and adding reproducible
+// noise. Every wrapper is safe for concurrent use.
+package gointtest
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+
+	"goint"
+)
+
+// A CountingFunction wraps a Function and counts its evaluations. Its
+// method value Eval is a goint.Function.
+type CountingFunction struct {
+	f     goint.Function
+	calls int64
+}
+
+// NewCountingFunction returns a CountingFunction wrapping f.
+func NewCountingFunction(f goint.Function) *CountingFunction {
+	return &CountingFunction{f: f}
+}
+
+// Eval evaluates the wrapped function at x and counts the call.
+func (c *CountingFunction) Eval(x float64) float64 {
+	atomic.AddInt64(&c.calls, 1)
+	return c.f(x)
+}
+
+// Calls returns the number of evaluations since creation or the last
+// Reset.
+func (c *CountingFunction) Calls() int {
+	return int(atomic.LoadInt64(&c.calls))
+}
+
+// Reset sets the count of evaluations to zero.
+func (c *CountingFunction) Reset() {
+	atomic.StoreInt64(&c.calls, 0)
+}
+
+// FailAfter returns a Function that evaluates f for its first n calls and
+// returns NaN from then on, simulating an integrand whose evaluation
+// breaks down partway through an integration.
+func FailAfter(f goint.Function, n int) goint.Function {
+	var calls int64
+	return func(x float64) float64 {
+		if atomic.AddInt64(&calls, 1) > int64(n) {
+			return math.NaN()
+		}
+		return f(x)
+	}
+}
+
+// Scripted returns a Function that ignores its argument and returns the
+// given values in turn, and NaN once they are exhausted.
+func Scripted(values ...float64) goint.Function {
+	values = append([]float64(nil), values...)
+	var calls int64
+	return func(float64) float64 {
+		i := atomic.AddInt64(&calls, 1) - 1
+		if i >= int64(len(values)) {
+			return math.NaN()
+		}
+		return values[i]
+	}
+}
+
+// NoisyFunction returns a Function that adds independent Gaussian noise of
+// standard deviation sigma to each evaluation of f. The noise is drawn
+// from a source seeded with seed, so the same sequence of calls gives the
+// same values; concurrent calls are serialized but their order is not.
+func NoisyFunction(f goint.Function, sigma float64, seed int64) goint.Function {
+	var mu sync.Mutex
+	rng := rand.New(rand.NewSource(seed))
+	return func(x float64) float64 {
+		mu.Lock()
+		noise := sigma * rng.NormFloat64()
+		mu.Unlock()
+		return f(x) + noise
+	}
+}
@@ -0,0 +1,62 @@
+package gointtest
+
+import (
+	"math"
+	"testing"
+
+	"goint"
+)
+
+func TestCountingFunction(t *testing.T) {
+	c := NewCountingFunction(math.Exp)
+	r, err := goint.IntegrateGK(c.Eval, 0, 1, 1e-10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Calls() != r.Evals {
+		t.Errorf("counted %d calls, integrator reported %d", c.Calls(), r.Evals)
+	}
+	c.Reset()
+	if c.Calls() != 0 {
+		t.Errorf("%d calls after Reset", c.Calls())
+	}
+}
+
+func TestFailAfter(t *testing.T) {
+	f := FailAfter(math.Exp, 2)
+	if f(0) != 1 || f(0) != 1 || !math.IsNaN(f(0)) {
+		t.Error("expected two values and then NaN")
+	}
+
+	// A failing integrand makes the estimate NaN rather than hanging
+	if _, err := goint.IntegrateGK(FailAfter(math.Exp, 10), 0, 1, 1e-8); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestScripted(t *testing.T) {
+	f := Scripted(1, 2, 3)
+	for i, want := range []float64{1, 2, 3} {
+		if got := f(-7); got != want {
+			t.Errorf("call %d: got %v, want %v", i, got, want)
+		}
+	}
+	if !math.IsNaN(f(0)) {
+		t.Error("expected NaN once exhausted")
+	}
+}
+
+func TestNoisyFunction(t *testing.T) {
+	f := NoisyFunction(math.Exp, .1, 42)
+	g := NoisyFunction(math.Exp, .1, 42)
+	for _, x := range []float64{0, .5, 1} {
+		if a, b := f(x), g(x); a != b {
+			t.Errorf("x = %v: same seed gave %v and %v", x, a, b)
+		}
+	}
+
+	h := NoisyFunction(math.Exp, 0, 1)
+	if h(1) != math.E {
+		t.Error("zero sigma should add no noise")
+	}
+}
@@ -0,0 +1,74 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAntiderivative(t *testing.T) {
+	const (
+		h = 1e-10
+	)
+
+	calls := 0
+	f := func(x float64) float64 {
+		calls++
+		return math.Cos(x)
+	}
+
+	F := Antiderivative(f, 0, h)
+	for _, x := range []float64{1, 2, 1.5, -1, 3} {
+		if msg, ok := checkValue(F(x), math.Sin(x), 4*h); !ok {
+			t.Error(msg)
+		}
+	}
+
+	// Revisiting a point is free
+	calls = 0
+	F(1.5)
+	if calls != 0 {
+		t.Errorf("cached point cost %d evaluations", calls)
+	}
+}
+
+func TestGini(t *testing.T) {
+	const (
+		tol = 1e-8
+	)
+
+	uniform := func(x float64) float64 { return 1 }
+	exponential := func(x float64) float64 { return math.Exp(-x) }
+
+	if msg, ok := checkValue(Gini(uniform, [2]float64{0, 1}, tol), 1./3, 1e-7); !ok {
+		t.Error(msg)
+	}
+	if msg, ok := checkValue(Gini(exponential, [2]float64{0, math.Inf(1)}, tol), .5, 1e-6); !ok {
+		t.Error(msg)
+	}
+
+	// A heavy Pareto tail, at a loose tolerance and at none
+	pareto := func(x float64) float64 { return 1.5 * math.Pow(x, -2.5) }
+	for _, h := range []float64{1e-3, 0} {
+		if msg, ok := checkValue(Gini(pareto, [2]float64{1, math.Inf(1)}, h), .5, math.Max(h, 1e-13)); !ok {
+			t.Errorf("pareto, tol = %g: %s", h, msg)
+		}
+	}
+	if g := Gini(uniform, [2]float64{0, 1}, math.NaN()); !math.IsNaN(g) {
+		t.Errorf("NaN tolerance gave %g", g)
+	}
+
+	L := LorenzCurve(uniform, [2]float64{0, 1}, tol)
+	for _, p := range []float64{0, .2, .5, .9, 1} {
+		if msg, ok := checkValue(L(p), p*p, 1e-6); !ok {
+			t.Errorf("uniform, p = %g: %s", p, msg)
+		}
+	}
+
+	L = LorenzCurve(exponential, [2]float64{0, math.Inf(1)}, tol)
+	for _, p := range []float64{.2, .5, .9} {
+		want := p + (1-p)*math.Log(1-p)
+		if msg, ok := checkValue(L(p), want, 1e-6); !ok {
+			t.Errorf("exponential, p = %g: %s", p, msg)
+		}
+	}
+}
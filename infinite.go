@@ -0,0 +1,125 @@
+package goint
+
+import (
+	"math"
+)
+
+// An InfiniteMap selects how an adaptive integrator maps an infinite
+// interval onto a finite one.
+type InfiniteMap int
+
+const (
+	// RationalMap substitutes x = a + t/(1-t) for t in [0, 1], or its
+	// reflections, and t/(1-t^2) on the whole line. Tails of every weight
+	// are covered exactly, but the region near a finite endpoint is
+	// squeezed into a short stretch of t.
+	RationalMap InfiniteMap = iota
+
+	// DoubleExponentialMap substitutes x = a + exp(π/2 sinh t), the
+	// exp-sinh map, on half-infinite intervals and x = sinh(π/2 sinh t),
+	// the sinh-sinh map, on the whole line. Both spread the region near a
+	// finite endpoint and the tails over a wide range of t, so integrands
+	// with endpoint singularities or slowly decaying tails are sampled
+	// evenly on a logarithmic scale. The range of t is cut off where x
+	// reaches about 1e226 and within about 1e-226 of a finite endpoint,
+	// so only tails decaying more slowly than 1/x^1.1 lose measurably.
+	DoubleExponentialMap
+
+	// TangentMap substitutes x = a + tan t for t in [0, π/2), or its
+	// reflection, and x = tan t on the whole line. It suits algebraic
+	// tails: f decaying like 1/x^2 becomes bounded and smooth in t.
+	TangentMap
+
+	// LogarithmicMap substitutes x = a - log(1 - t) for t in [0, 1), or
+	// its reflection, and the logit x = log(t / (1 - t)) on the whole
+	// line. It suits exponential tails: f decaying like e^-x becomes
+	// polynomial in t. Algebraic tails are pushed so close to t = 1 that
+	// much of their mass lies beyond the resolution of float64, so it
+	// should not be used for them.
+	LogarithmicMap
+)
+
+// The cutoff of the double exponential maps in the variable t.
+const deMaxT = 6.5
+
+// Returns the substitution x = phi(t), with its derivative, that maps the
+// finite interval [lo, hi] onto the infinite interval [a, b] under m, for
+// any map other than RationalMap, using the elementary functions of fn.
+func infiniteTransform(m InfiniteMap, a, b float64, fn *elementary) (phi func(t float64) (float64, float64), lo, hi float64) {
+	lower, upper := math.IsInf(a, -1), math.IsInf(b, 1)
+	switch m {
+	case DoubleExponentialMap:
+		return conformalMap(a, b, fn), -deMaxT, deMaxT
+
+	case TangentMap:
+		switch {
+		case lower && upper:
+			return func(t float64) (float64, float64) {
+				c := fn.cos(t)
+				return fn.tan(t), 1 / (c * c)
+			}, -math.Pi / 2, math.Pi / 2
+		case upper:
+			return func(t float64) (float64, float64) {
+				c := fn.cos(t)
+				return a + fn.tan(t), 1 / (c * c)
+			}, 0, math.Pi / 2
+		}
+		return func(t float64) (float64, float64) {
+			c := fn.cos(t)
+			return b - fn.tan(t), 1 / (c * c)
+		}, 0, math.Pi / 2
+	}
+
+	switch {
+	case lower && upper:
+		return func(t float64) (float64, float64) {
+			return fn.log(t / (1 - t)), 1 / (t * (1 - t))
+		}, 0, 1
+	case upper:
+		return func(t float64) (float64, float64) {
+			return a - fn.log1p(-t), 1 / (1 - t)
+		}, 0, 1
+	}
+	return func(t float64) (float64, float64) {
+		return b + fn.log1p(-t), 1 / (1 - t)
+	}, 0, 1
+}
+
+// Maps f over [a, b] onto a finite interval with the substitution m,
+// returning the new integrand and its limits. Finite intervals are
+// returned unchanged. Points that the substitution sends to or beyond
+// the limits contribute nothing.
+func transformCompactify(m InfiniteMap, f Function, a, b float64, fn *elementary) (g Function, lo, hi float64) {
+	if !math.IsInf(a, 0) && !math.IsInf(b, 0) {
+		return f, a, b
+	}
+	if m == RationalMap {
+		return compactify(f, a, b)
+	}
+
+	phi, lo, hi := infiniteTransform(m, a, b, fn)
+	return func(t float64) float64 {
+		x, w := phi(t)
+		if !(x > a && x < b) || w == 0 || math.IsInf(w, 0) {
+			return 0
+		}
+		return f(x) * w
+	}, lo, hi
+}
+
+// Returns the map from the variable of transformCompactify(m, f, a, b)
+// back to x.
+func transformUncompact(m InfiniteMap, a, b float64, fn *elementary) func(t float64) float64 {
+	if !math.IsInf(a, 0) && !math.IsInf(b, 0) {
+		return func(t float64) float64 { return t }
+	}
+	if m == RationalMap {
+		return uncompact(a, b)
+	}
+
+	phi, _, _ := infiniteTransform(m, a, b, fn)
+	return func(t float64) float64 {
+		x, _ := phi(t)
+		return x
+	}
+}
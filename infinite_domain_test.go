@@ -97,3 +97,33 @@ func TestNormal(t *testing.T) {
 		t.Error(msg)
 	}
 }
+
+/* Test that divergent integrals over infinite domains are not
+/* reported as finite */
+func TestDivergent(t *testing.T) {
+	const h = 1e-6
+
+	one := func(x float64) float64 { return 1 }
+	inverse := func(x float64) float64 { return 1 / x }
+
+	if v := Integrate(one, 0, math.Inf(1), h); !math.IsInf(v, 1) {
+		t.Errorf("integral of 1 over [0, Inf) gave %g", v)
+	}
+	if v := Integrate(one, math.Inf(-1), 0, h); !math.IsInf(v, 1) {
+		t.Errorf("integral of 1 over (-Inf, 0] gave %g", v)
+	}
+	if v := Integrate(inverse, 1, math.Inf(1), h); !math.IsInf(v, 1) {
+		t.Errorf("integral of 1/x over [1, Inf) gave %g", v)
+	}
+	if v := Integrate(inverse, math.Inf(-1), -1, h); !math.IsInf(v, -1) {
+		t.Errorf("integral of 1/x over (-Inf, -1] gave %g", v)
+	}
+	if v := Integrate(math.Sin, 0, math.Inf(1), h); !math.IsNaN(v) {
+		t.Errorf("integral of sin over [0, Inf) gave %g", v)
+	}
+
+	// Without a tolerance the estimates never settle either
+	if v := Integrate(inverse, 1, math.Inf(1), 0); !math.IsInf(v, 1) {
+		t.Errorf("integral of 1/x over [1, Inf) to within 0 gave %g", v)
+	}
+}
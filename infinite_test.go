@@ -0,0 +1,72 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestWithInfiniteMap(t *testing.T) {
+	const tol = 1e-10
+
+	inf := math.Inf(1)
+	cases := []struct {
+		f         Function
+		a, b      float64
+		correct   float64
+		algebraic bool
+	}{
+		{func(x float64) float64 { return math.Exp(-x) / math.Sqrt(x) }, 0, inf, math.SqrtPi, false},
+		{func(x float64) float64 { return 1 / (1 + x*x) }, -inf, inf, math.Pi, true},
+		{func(x float64) float64 { return 1 / (x * x) }, 1, inf, 1, true},
+		{math.Exp, -inf, -2, math.Exp(-2), false},
+		{func(x float64) float64 { return math.Exp(-x * x) }, inf, -inf, -math.SqrtPi, false},
+		{math.Exp, 0, 1, math.E - 1, false},
+	}
+	for _, m := range []InfiniteMap{RationalMap, DoubleExponentialMap, TangentMap, LogarithmicMap} {
+		for i, c := range cases {
+			if c.algebraic && m == LogarithmicMap {
+				continue
+			}
+			r, err := IntegrateGK(c.f, c.a, c.b, tol, WithInfiniteMap(m))
+			if err != nil {
+				t.Errorf("map %d, case %d: %v", m, i, err)
+			}
+			if msg, ok := checkValue(r.Value, c.correct, 10*tol); !ok {
+				t.Errorf("map %d, case %d: %s", m, i, msg)
+			}
+		}
+	}
+
+	// Each tail is easiest under the map that matches its decay
+	algebraic := func(x float64) float64 { return 1 / (1 + x*x) }
+	tan, _ := IntegrateGK(algebraic, 0, inf, tol, WithInfiniteMap(TangentMap))
+	log, _ := IntegrateGK(algebraic, 0, inf, tol, WithInfiniteMap(LogarithmicMap))
+	if tan.Evals >= log.Evals {
+		t.Errorf("algebraic tail: %d evaluations with TangentMap, %d with LogarithmicMap", tan.Evals, log.Evals)
+	}
+	exponential := func(x float64) float64 { return math.Exp(-x) }
+	tan, _ = IntegrateGK(exponential, 0, inf, tol, WithInfiniteMap(TangentMap))
+	log, _ = IntegrateGK(exponential, 0, inf, tol, WithInfiniteMap(LogarithmicMap))
+	if log.Evals >= tan.Evals {
+		t.Errorf("exponential tail: %d evaluations with LogarithmicMap, %d with TangentMap", log.Evals, tan.Evals)
+	}
+
+	// The endpoint singularity is spread out rather than squeezed
+	f := func(x float64) float64 { return math.Exp(-x) / math.Sqrt(x) }
+	de, _ := IntegrateGK(f, 0, inf, tol, WithInfiniteMap(DoubleExponentialMap))
+	rational, _ := IntegrateGK(f, 0, inf, tol)
+	if de.Evals >= rational.Evals {
+		t.Errorf("%d evaluations with DoubleExponentialMap, %d with RationalMap", de.Evals, rational.Evals)
+	}
+
+	// Integrate uses the double exponential map, which resolves a heavy
+	// tail together with an endpoint singularity
+	heavy := func(x float64) float64 { return 1 / (math.Sqrt(x) * (1 + x)) }
+	if msg, ok := checkValue(Integrate(heavy, 0, inf, 1e-8), math.Pi, 1e-7); !ok {
+		t.Error(msg)
+	}
+
+	if _, err := IntegrateGK(f, 0, inf, tol, WithInfiniteMap(InfiniteMap(7))); err != ErrInvalidOption {
+		t.Errorf("unknown map gave %v", err)
+	}
+}
@@ -22,57 +22,117 @@ func boolesrule(f Function, a, b float64) float64 {
 	return 2 * h * (7*fa + 32*f2 + 12*f3 + 32*f4 + 7*fb) / 45.0
 }
 
-/* Integrate a function f over the interval [a, b] to within err. Both
-/* a and b can be infinite. Integration will be done using Boole's
-/* rule. */
+// The most times Integrate doubles its grid before giving up and
+// returning its latest estimate.
+const maxRefinements = 20
+
+// Integrate a function f over the interval [a, b] to within err. Both
+// a and b can be infinite, in which case the interval is first mapped
+// onto a finite one with DoubleExponentialMap. Integration will be done
+// using Boole's rule. If the estimates have not settled after
+// maxRefinements doublings of the grid, as when err is zero, the latest
+// estimate is returned. On an infinite interval the map is cut off far
+// out, so the mass in the outermost stretches is checked too: if it is
+// not negligible, or the estimates have not settled, the integral is
+// taken to diverge and the result is +Inf or -Inf if the estimates were
+// still growing in that direction, and NaN otherwise. Slowly decaying
+// tails the map cannot resolve, like 1/x^1.01, therefore also give NaN.
+// The result is NaN if a bound, err or the integrand is NaN.
 func Integrate(f Function, a, b, err float64) float64 {
-	var ret float64
-
-	// Get an initial estimate, being conservative when there are infinities
-	if math.IsInf(a, -1) || math.IsInf(b, 1) {
-		ret = math.Inf(1)
-	} else {
-		ret = boolesrule(f, a, b)
+	if math.IsNaN(a) || math.IsNaN(b) || math.IsNaN(err) {
+		return math.NaN()
+	}
+	if a == b {
+		return 0
+	}
+	if a > b {
+		return -Integrate(f, b, a, err)
+	}
+	if math.IsInf(a, 0) || math.IsInf(b, 0) {
+		g, lo, hi := transformCompactify(DoubleExponentialMap, f, a, b, nativeMath)
+		return integrateSettled(g, lo, hi, err)
 	}
 
-	points := []float64{a, b}
-	done := false
-	for !done {
-		// Get a refined estimate
-		points = refinedPoints(points)
+	value, _ := integrateBoole(f, a, b, err)
+	return value
+}
 
-		// Skip extreme points
-		start := 1
-		end := len(points)
+// The relative change below which Integrate considers an integral over an
+// infinite interval settled, and the width in the mapped variable of the
+// outermost stretches whose mass it checks.
+const (
+	settledTol   = 1e-8
+	settledWidth = .5
+)
+
+// Implements Integrate on the image [lo, hi] of an infinite interval,
+// returning +Inf, -Inf or NaN if the estimates have not settled or the
+// outermost stretches of [lo, hi] still carry mass.
+func integrateSettled(g Function, lo, hi, err float64) float64 {
+	value, prev := integrateBoole(g, lo, hi, err)
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return value
+	}
+	tol := math.Max(err, settledTol*math.Abs(value))
 
-		if math.IsInf(points[0], -1) {
-			start += 1
+	// Successive estimates, or adjacent stretches ordered outwards, which
+	// keep growing in one direction suggest an infinite integral
+	diverged := func(inner, outer float64) float64 {
+		if inner*outer > 0 && math.Abs(outer) >= math.Abs(inner) {
+			return math.Inf(int(math.Copysign(1, outer)))
 		}
+		return math.NaN()
+	}
 
-		if math.IsInf(points[end-1], 1) {
-			end -= 1
+	if math.Abs(value-prev) > tol {
+		return diverged(prev, value)
+	}
+	for _, step := range []float64{settledWidth, -settledWidth} {
+		end := lo
+		if step < 0 {
+			end = hi
+		}
+		piece := func(from, to float64) float64 {
+			v, _ := integrateBoole(g, math.Min(from, to), math.Max(from, to), tol/4)
+			return v
 		}
+		outer := piece(end, end+step)
+		if math.Abs(outer) <= tol/4 {
+			continue
+		}
+		return diverged(piece(end+step, end+2*step), outer)
+	}
+
+	return value
+}
+
+// Implements Integrate on a finite interval, returning the final
+// estimate and the one before it.
+func integrateBoole(f Function, a, b, err float64) (float64, float64) {
+	ret := boolesrule(f, a, b)
+	points := []float64{a, b}
+	for level := 1; ; level++ {
+		// Get a refined estimate
+		points = refinedPoints(points)
 
 		refined := 0.0
-		L := points[start-1]
-		for _, R := range points[start:end] {
+		L := points[0]
+		for _, R := range points[1:] {
 			refined += boolesrule(f, L, R)
 			L = R
 		}
 
 		// Check for unbounded integrals
 		if math.IsInf(ret, 1) && math.IsInf(refined, 1) {
-			return ret
+			return ret, ret
 		} else if math.IsInf(ret, -1) && math.IsInf(refined, -1) {
-			return ret
-		} else if math.Abs(ret-refined) < err {
-			done = true
+			return ret, ret
+		} else if math.Abs(ret-refined) < err || math.IsNaN(refined) || level >= maxRefinements {
+			return refined, ret
 		}
 
 		ret = refined
 	}
-
-	return ret
 }
 
 /* Returns a new slice of values containing all the values in points
@@ -80,49 +140,12 @@ func Integrate(f Function, a, b, err float64) float64 {
 /*
 /*   refinedPoints([]float64{0, 2, 4}) == []float64{0, 1, 2, 3, 4} */
 func refinedPoints(points []float64) []float64 {
-	// Check for infinite extremes with only two points specified
-	if len(points) == 2 {
-		if math.IsInf(points[0], -1) && math.IsInf(points[1], 1) {
-			return []float64{points[0], 0, points[1]}
-		} else if math.IsInf(points[0], -1) && points[1] >= 0 {
-			return []float64{points[0], -1, points[1]}
-		} else if math.IsInf(points[1], 1) && points[0] <= 0 {
-			return []float64{points[0], 1, points[1]}
-		}
-	}
-
 	refined := make([]float64, len(points)*2-1)
-
-	// Check the left endpoint for -Inf
-	if math.IsInf(points[0], -1) {
-		refined[0] = points[0]
-		refined[1] = points[1] * 2
-	} else {
-		refined[0] = points[0]
-		refined[1] = (points[0] + points[1]) / 2
-	}
-
-	points_end := len(points) - 1
-	refined_end := len(refined) - 1
-
-	// Interpolate all intermediate points
-	ndx := 2
-	for i := 1; i < points_end-1; i++ {
-		refined[ndx] = points[i]
-		refined[ndx+1] = (points[i] + points[i+1]) / 2
-		ndx += 2
-	}
-
-	// Check the right endpoint for +Inf
-	if math.IsInf(points[points_end], 1) {
-		refined[refined_end] = points[points_end]
-		refined[refined_end-1] = points[points_end-1] * 2
-		refined[refined_end-2] = points[points_end-1]
-	} else {
-		refined[refined_end] = points[points_end]
-		refined[refined_end-1] = (points[points_end] + points[points_end-1]) / 2
-		refined[refined_end-2] = points[points_end-1]
+	for i, x := range points[:len(points)-1] {
+		refined[2*i] = x
+		refined[2*i+1] = (x + points[i+1]) / 2
 	}
+	refined[len(refined)-1] = points[len(points)-1]
 
 	return refined
 }
@@ -0,0 +1,94 @@
+package goint
+
+import (
+	"errors"
+	"math"
+	"sync"
+)
+
+// ErrCanceled is returned by Job.Result when the job was canceled before
+// meeting its tolerance.
+var ErrCanceled = errors.New("goint: integration canceled")
+
+// A Job is an integration running in the background, whose converging
+// estimate can be polled while refinement continues, for instance to show
+// it live in a user interface. Its methods are safe for concurrent use.
+type Job struct {
+	mu       sync.Mutex
+	value    float64
+	err      float64
+	canceled bool
+
+	done   chan struct{}
+	result Result
+	status error
+}
+
+// StartIntegration begins integrating f over [a, b] to within tol as
+// IntegrateGK does, on a new goroutine, and returns a handle to the
+// running job. The estimate is published after every refinement step.
+func StartIntegration(f Function, a, b, tol float64, opts ...Option) *Job {
+	j := &Job{
+		value: math.NaN(),
+		err:   math.Inf(1),
+		done:  make(chan struct{}),
+	}
+
+	go func() {
+		stop := func(value, err float64) bool {
+			j.mu.Lock()
+			defer j.mu.Unlock()
+			j.value, j.err = value, err
+			return j.canceled
+		}
+		r, err := integrateGK(f, a, b, tol, newConfig(opts), stop)
+
+		j.mu.Lock()
+		j.value, j.err = r.Value, r.Error
+		if j.canceled && !(r.Error <= tol) {
+			err = ErrCanceled
+		}
+		j.result, j.status = r, err
+		j.mu.Unlock()
+		close(j.done)
+	}()
+
+	return j
+}
+
+// Estimate returns the latest estimate of the integral, or NaN before the
+// first is available.
+func (j *Job) Estimate() float64 {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.value
+}
+
+// Error returns the estimated absolute error of the latest estimate, or
+// +Inf before the first is available.
+func (j *Job) Error() float64 {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.err
+}
+
+// Cancel asks the job to stop refining. It returns at once; the job stops
+// after its current step, and Result then reports ErrCanceled unless the
+// tolerance had already been met.
+func (j *Job) Cancel() {
+	j.mu.Lock()
+	j.canceled = true
+	j.mu.Unlock()
+}
+
+// Done returns a channel that is closed when the job finishes.
+func (j *Job) Done() <-chan struct{} {
+	return j.done
+}
+
+// Result waits for the job to finish and returns its final result and
+// error, as IntegrateGK would.
+func (j *Job) Result() (Result, error) {
+	<-j.done
+	return j.result, j.status
+}
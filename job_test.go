@@ -0,0 +1,52 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestStartIntegration(t *testing.T) {
+	const tol = 1e-10
+
+	f := func(x float64) float64 { return 1 / (1e-4 + x*x) }
+	j := StartIntegration(f, -1, 1, tol)
+	r, err := j.Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := IntegrateGK(f, -1, 1, tol)
+	if r != want {
+		t.Errorf("job gave %+v, want %+v", r, want)
+	}
+	if j.Estimate() != r.Value || j.Error() != r.Error {
+		t.Errorf("final estimate %v ± %v, want %v ± %v", j.Estimate(), j.Error(), r.Value, r.Error)
+	}
+
+	// A job that cannot converge runs until canceled, publishing estimates
+	// along the way
+	started := make(chan struct{})
+	release := make(chan struct{})
+	once := false
+	slow := func(x float64) float64 {
+		if !once {
+			once = true
+			close(started)
+			<-release
+		}
+		return math.Sin(1 / x)
+	}
+	j = StartIntegration(slow, 0, 1, 0)
+	<-started
+	if v := j.Estimate(); !math.IsNaN(v) {
+		t.Errorf("estimate %v before the first step", v)
+	}
+	close(release)
+	j.Cancel()
+	<-j.Done()
+	if _, err := j.Result(); err != ErrCanceled {
+		t.Errorf("canceled job gave %v", err)
+	}
+	if math.IsNaN(j.Estimate()) || math.IsInf(j.Error(), 1) {
+		t.Errorf("no estimate published: %v ± %v", j.Estimate(), j.Error())
+	}
+}
@@ -0,0 +1,98 @@
+package goint
+
+import (
+	"math"
+)
+
+// A KLExpansion is a truncated Karhunen-Loeve expansion of a random field
+// on [a, b] with a given covariance kernel,
+//
+//	X(x) = sum over k of sqrt(Variances[k]) xi_k Modes[k](x),
+//
+// where the xi_k are uncorrelated with unit variance.
+type KLExpansion struct {
+	// Variances holds the eigenvalues of the covariance operator in
+	// decreasing order.
+	Variances []float64
+
+	// Modes holds the eigenfunctions, orthonormal with respect to the
+	// quadrature used to compute them.
+	Modes []Function
+
+	// Total is the total variance of the field, the integral of
+	// cov(x, x) over [a, b], computed with the same quadrature.
+	Total float64
+}
+
+// KarhunenLoeve computes the first m modes of the Karhunen-Loeve expansion
+// of a field with covariance kernel cov on [a, b], discretizing the
+// covariance operator on n Gauss-Legendre nodes. m is clamped to [0, n].
+// Eigenvalues that are negative through rounding are reported as zero.
+// The limits may be given in either order. nil is returned if n < 1.
+func KarhunenLoeve(cov Kernel, a, b float64, m, n int) *KLExpansion {
+	if n < 1 {
+		return nil
+	}
+	a, b = math.Min(a, b), math.Max(a, b)
+
+	if m > n {
+		m = n
+	}
+	if m < 0 {
+		m = 0
+	}
+
+	vals, funcs := OperatorEigen(cov, a, b, n)
+
+	// A covariance operator is positive semidefinite, so the leading
+	// eigenvalues by magnitude are the leading ones by value, save for
+	// rounding noise at the tail
+	kl := &KLExpansion{
+		Variances: make([]float64, m),
+		Modes:     funcs[:m],
+	}
+	for k := range kl.Variances {
+		kl.Variances[k] = math.Max(vals[k], 0)
+	}
+
+	ts, ws := legendreNodes(a, b, n)
+	for i, t := range ts {
+		kl.Total += ws[i] * cov(t, t)
+	}
+
+	return kl
+}
+
+// Explained returns the fraction of the total variance captured by the
+// retained modes.
+func (kl *KLExpansion) Explained() float64 {
+	sum := 0.0
+	for _, v := range kl.Variances {
+		sum += v
+	}
+
+	return sum / kl.Total
+}
+
+// Field returns the realization of the truncated expansion for the given
+// standard coefficients xi, which typically are independent standard
+// normal draws. Only the first min(len(xi), len(Modes)) modes are used.
+func (kl *KLExpansion) Field(xi []float64) Function {
+	m := len(xi)
+	if m > len(kl.Modes) {
+		m = len(kl.Modes)
+	}
+
+	scales := make([]float64, m)
+	for k := range scales {
+		scales[k] = math.Sqrt(kl.Variances[k]) * xi[k]
+	}
+
+	return func(x float64) float64 {
+		ret := 0.0
+		for k, s := range scales {
+			ret += s * kl.Modes[k](x)
+		}
+		return ret
+	}
+}
@@ -0,0 +1,56 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestKarhunenLoeve(t *testing.T) {
+	// An exponential covariance on [0, 1] with unit variance
+	cov := func(x, y float64) float64 { return math.Exp(-math.Abs(x-y) / .5) }
+
+	kl := KarhunenLoeve(cov, 0, 1, 5, 60)
+	if len(kl.Variances) != 5 || len(kl.Modes) != 5 {
+		t.Fatalf("got %d variances and %d modes", len(kl.Variances), len(kl.Modes))
+	}
+	if msg, ok := checkValue(kl.Total, 1, 1e-12); !ok {
+		t.Error(msg)
+	}
+
+	for k := 1; k < 5; k++ {
+		if kl.Variances[k] > kl.Variances[k-1] {
+			t.Errorf("variances are not decreasing at mode %d", k)
+		}
+	}
+	if e := kl.Explained(); e < .8 || e > 1 {
+		t.Errorf("five modes explain %g of the variance", e)
+	}
+
+	// Modes are orthonormal
+	for j := 0; j < 3; j++ {
+		for k := 0; k <= j; k++ {
+			prod := Overlap(kl.Modes[j], kl.Modes[k], 0, 1, 1e-8)
+			correct := 0.0
+			if j == k {
+				correct = 1
+			}
+			if msg, ok := checkValue(prod, correct, 5e-3); !ok {
+				t.Errorf("modes %d and %d: %s", j, k, msg)
+			}
+		}
+	}
+
+	// A realization with a single unit coefficient is the scaled mode
+	X := kl.Field([]float64{1})
+	if msg, ok := checkValue(X(.3), math.Sqrt(kl.Variances[0])*kl.Modes[0](.3), 1e-15); !ok {
+		t.Error(msg)
+	}
+
+	// Out of range mode counts are clamped
+	if kl := KarhunenLoeve(cov, 0, 1, -1, 10); len(kl.Variances) != 0 || len(kl.Modes) != 0 {
+		t.Errorf("m = -1 gave %d variances and %d modes", len(kl.Variances), len(kl.Modes))
+	}
+	if kl := KarhunenLoeve(cov, 0, 1, 20, 10); len(kl.Variances) != 10 {
+		t.Errorf("m = 20 on 10 nodes gave %d variances", len(kl.Variances))
+	}
+}
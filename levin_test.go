@@ -0,0 +1,72 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestIntegrateLevin(t *testing.T) {
+	const tol = 1e-10
+
+	// The integrals of e^x cos(wx) and e^x sin(wx) over [0, 1]
+	for _, w := range []float64{1, 10, 1e3, 1e5} {
+		e, d := math.E, 1+w*w
+		c := (e*(math.Cos(w)+w*math.Sin(w)) - 1) / d
+		s := (e*(math.Sin(w)-w*math.Cos(w)) + w) / d
+
+		r, err := IntegrateLevin(math.Exp, 0, 1, FourierKernel(w, Cosine), tol)
+		if err != nil {
+			t.Errorf("omega = %v: %v", w, err)
+		}
+		if msg, ok := checkValue(r.Value, c, 10*tol); !ok {
+			t.Errorf("cos, omega = %v: %s", w, msg)
+		}
+		if r.Evals > 500 {
+			t.Errorf("omega = %v: %d evaluations", w, r.Evals)
+		}
+
+		r, err = IntegrateLevin(math.Exp, 0, 1, FourierKernel(w, Sine), tol)
+		if err != nil {
+			t.Errorf("omega = %v: %v", w, err)
+		}
+		if msg, ok := checkValue(r.Value, s, 10*tol); !ok {
+			t.Errorf("sin, omega = %v: %s", w, msg)
+		}
+	}
+
+	// The integral of x J_0(wx) is x J_1(wx) / w
+	for _, w := range []float64{5, 200, 1e4} {
+		f := func(x float64) float64 { return x }
+		correct := (2*math.J1(2*w) - math.J1(w)) / w
+		r, err := IntegrateLevin(f, 1, 2, BesselKernel(0, w), tol)
+		if err != nil {
+			t.Errorf("omega = %v: %v", w, err)
+		}
+		if msg, ok := checkValue(r.Value, correct, 10*tol); !ok {
+			t.Errorf("bessel, omega = %v: %s", w, msg)
+		}
+	}
+
+	// Without oscillation the system is singular and IntegrateGK is used
+	r, err := IntegrateLevin(math.Exp, 0, 1, FourierKernel(0, Cosine), tol)
+	if err != nil {
+		t.Error(err)
+	}
+	if msg, ok := checkValue(r.Value, math.E-1, tol); !ok {
+		t.Error(msg)
+	}
+
+	if _, err := IntegrateLevin(math.Exp, 0, math.Inf(1), FourierKernel(1, Cosine), tol); err != ErrInvalidInput {
+		t.Errorf("infinite bound gave %v", err)
+	}
+
+	// A zero tolerance cannot be met, but the work is bounded
+	r, err = IntegrateLevin(math.Exp, 0, 1, FourierKernel(50, Cosine), 0)
+	correct := (math.E*(math.Cos(50)+50*math.Sin(50)) - 1) / (1 + 50*50)
+	if err != ErrNotConverged || r.Evals > 2*levinMaxEvals {
+		t.Errorf("tol = 0 gave %v in %d evaluations", err, r.Evals)
+	}
+	if msg, ok := checkValue(r.Value, correct, 1e-13); !ok {
+		t.Errorf("tol = 0: %s", msg)
+	}
+}
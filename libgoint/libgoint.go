@@ -0,0 +1,152 @@
+// Command libgoint builds the integrators of goint into a shared library
+// with a C ABI, for use from C and from languages with a C foreign
+// function interface, such as Python's ctypes, R's .C and Julia's ccall.
+// Build it with
+//
+//	go build -buildmode=c-shared -o libgoint.so goint/libgoint
+//
+// which also writes the header libgoint.h. Integrands are C function
+// pointers of type goint_function, called with the point of evaluation
+// and an opaque pointer passed through unchanged. Options for
+// goint_integrate_gk and goint_fixed_integrate are gathered in a
+// goint_options handle, created by goint_options_new and released by
+// goint_options_free; a zero handle means no options. Every integrator
+// writes a goint_result, unless its pointer is null, and returns a status,
+// GOINT_OK on success.
+package main
+
+/*
+#include <stdint.h>
+
+typedef double (*goint_function)(double x, void *data);
+typedef uintptr_t goint_options;
+
+typedef struct {
+	double value;
+	double error;
+	int64_t evals;
+	int64_t panels;
+} goint_result;
+
+enum {
+	GOINT_OK = 0,
+	GOINT_NOT_CONVERGED = 1,
+	GOINT_INVALID_INPUT = 2,
+	GOINT_MEMORY_LIMIT = 3,
+	GOINT_INVALID_OPTION = 4,
+	GOINT_FAILED = 5
+};
+
+static double goint_call(goint_function f, double x, void *data) {
+	return f(x, data);
+}
+*/
+import "C"
+
+import (
+	"unsafe"
+
+	"goint"
+)
+
+func main() {}
+
+// Returns the C integrand f with its data as a Function.
+func function(f C.goint_function, data unsafe.Pointer) goint.Function {
+	return func(x float64) float64 {
+		return float64(C.goint_call(f, C.double(x), data))
+	}
+}
+
+// Writes r to out, if it is not null, and returns the status of err.
+func finish(r goint.Result, err error, out *C.goint_result) C.int {
+	if out != nil {
+		*out = C.goint_result{
+			value:  C.double(r.Value),
+			error:  C.double(r.Error),
+			evals:  C.int64_t(r.Evals),
+			panels: C.int64_t(r.Panels),
+		}
+	}
+	return C.int(statusOf(err))
+}
+
+//export goint_integrate_gk
+func goint_integrate_gk(f C.goint_function, data unsafe.Pointer, a, b, tol C.double, opts C.goint_options, out *C.goint_result) C.int {
+	options, ok := lookupOptions(uintptr(opts))
+	if !ok {
+		return finish(goint.Result{}, goint.ErrInvalidOption, out)
+	}
+	r, err := goint.IntegrateGK(function(f, data), float64(a), float64(b), float64(tol), options...)
+	return finish(r, err, out)
+}
+
+//export goint_tanh_sinh
+func goint_tanh_sinh(f C.goint_function, data unsafe.Pointer, a, b, tol C.double, out *C.goint_result) C.int {
+	r, err := goint.TanhSinh(function(f, data), float64(a), float64(b), float64(tol))
+	return finish(r, err, out)
+}
+
+//export goint_integrate_patterson
+func goint_integrate_patterson(f C.goint_function, data unsafe.Pointer, a, b, tol C.double, out *C.goint_result) C.int {
+	r, err := goint.IntegratePatterson(function(f, data), float64(a), float64(b), float64(tol))
+	return finish(r, err, out)
+}
+
+//export goint_integrate_periodic
+func goint_integrate_periodic(f C.goint_function, data unsafe.Pointer, a, b, tol C.double, out *C.goint_result) C.int {
+	r, err := goint.IntegratePeriodic(function(f, data), float64(a), float64(b), float64(tol))
+	return finish(r, err, out)
+}
+
+//export goint_fixed_integrate
+func goint_fixed_integrate(f C.goint_function, data unsafe.Pointer, a, b C.double, n C.int, opts C.goint_options, out *C.goint_result) C.int {
+	options, ok := lookupOptions(uintptr(opts))
+	if !ok {
+		return finish(goint.Result{}, goint.ErrInvalidOption, out)
+	}
+	r, err := goint.FixedIntegrate(function(f, data), float64(a), float64(b), int(n), options...)
+	return finish(r, err, out)
+}
+
+//export goint_options_new
+func goint_options_new() C.goint_options {
+	return C.goint_options(newOptions())
+}
+
+//export goint_options_free
+func goint_options_free(opts C.goint_options) {
+	freeOptions(uintptr(opts))
+}
+
+//export goint_options_kronrod_order
+func goint_options_kronrod_order(opts C.goint_options, points C.int) C.int {
+	return C.int(addOption(uintptr(opts), goint.WithKronrodOrder(int(points))))
+}
+
+//export goint_options_max_memory
+func goint_options_max_memory(opts C.goint_options, bytes C.int64_t) C.int {
+	return C.int(addOption(uintptr(opts), goint.WithMaxMemoryBytes(int64(bytes))))
+}
+
+//export goint_options_breakpoints
+func goint_options_breakpoints(opts C.goint_options, xs *C.double, n C.int) C.int {
+	if n < 0 || n > 0 && xs == nil {
+		return C.int(statusOf(goint.ErrInvalidOption))
+	}
+	points := make([]float64, n)
+	for i, x := range unsafe.Slice(xs, int(n)) {
+		points[i] = float64(x)
+	}
+	return C.int(addOption(uintptr(opts), goint.WithBreakpoints(points...)))
+}
+
+//export goint_options_boundary_layer
+func goint_options_boundary_layer(opts C.goint_options, width C.double) C.int {
+	return C.int(addOption(uintptr(opts), goint.WithBoundaryLayer(float64(width))))
+}
+
+//export goint_options_extrapolation
+func goint_options_extrapolation(opts C.goint_options) C.int {
+	return C.int(addOption(uintptr(opts), goint.WithExtrapolation()))
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"errors"
+	"sync"
+
+	"goint"
+)
+
+// The statuses returned through the C API, matching the GOINT_ constants
+// of the header.
+const (
+	statusOK = iota
+	statusNotConverged
+	statusInvalidInput
+	statusMemoryLimit
+	statusInvalidOption
+	statusFailed
+)
+
+// Returns the status reporting err.
+func statusOf(err error) int {
+	switch {
+	case err == nil:
+		return statusOK
+	case errors.Is(err, goint.ErrNotConverged):
+		return statusNotConverged
+	case errors.Is(err, goint.ErrInvalidInput):
+		return statusInvalidInput
+	case errors.Is(err, goint.ErrMemoryLimit):
+		return statusMemoryLimit
+	case errors.Is(err, goint.ErrInvalidOption):
+		return statusInvalidOption
+	}
+	return statusFailed
+}
+
+// The option lists held for C callers, by handle. Handles are never
+// reused, so a stale one is reported rather than aliasing a new list.
+var options = struct {
+	sync.Mutex
+	next  uintptr
+	lists map[uintptr][]goint.Option
+}{lists: map[uintptr][]goint.Option{}}
+
+// Returns the handle of a new, empty option list.
+func newOptions() uintptr {
+	options.Lock()
+	defer options.Unlock()
+	options.next++
+	options.lists[options.next] = nil
+	return options.next
+}
+
+// Releases the option list with handle h, if there is one.
+func freeOptions(h uintptr) {
+	options.Lock()
+	defer options.Unlock()
+	delete(options.lists, h)
+}
+
+// Returns a copy of the option list with handle h, which is empty for the
+// zero handle, and whether the handle is valid.
+func lookupOptions(h uintptr) ([]goint.Option, bool) {
+	if h == 0 {
+		return nil, true
+	}
+	options.Lock()
+	defer options.Unlock()
+	list, ok := options.lists[h]
+	return append([]goint.Option(nil), list...), ok
+}
+
+// Appends opt to the option list with handle h, returning the status.
+func addOption(h uintptr, opt goint.Option) int {
+	options.Lock()
+	defer options.Unlock()
+	list, ok := options.lists[h]
+	if !ok {
+		return statusInvalidOption
+	}
+	options.lists[h] = append(list, opt)
+	return statusOK
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"goint"
+)
+
+func TestOptions(t *testing.T) {
+	h := newOptions()
+	if status := addOption(h, goint.WithKronrodOrder(21)); status != statusOK {
+		t.Fatalf("status %d", status)
+	}
+	opts, ok := lookupOptions(h)
+	if !ok || len(opts) != 1 {
+		t.Fatalf("got %d options, %v", len(opts), ok)
+	}
+	r, err := goint.IntegrateGK(math.Exp, 0, 1, 1e-12, opts...)
+	if err != nil || r.Evals%21 != 0 {
+		t.Errorf("got %d evaluations, %v", r.Evals, err)
+	}
+
+	freeOptions(h)
+	if _, ok := lookupOptions(h); ok {
+		t.Error("freed handle still valid")
+	}
+	if status := addOption(h, goint.WithExtrapolation()); status != statusInvalidOption {
+		t.Errorf("status %d for a freed handle", status)
+	}
+	if opts, ok := lookupOptions(0); !ok || opts != nil {
+		t.Error("zero handle is not the empty list")
+	}
+	if newOptions() == h {
+		t.Error("handle reused")
+	}
+}
+
+func TestStatus(t *testing.T) {
+	for _, c := range []struct {
+		err    error
+		status int
+	}{
+		{nil, statusOK},
+		{goint.ErrNotConverged, statusNotConverged},
+		{&goint.ProblemError{Reason: "test"}, statusInvalidInput},
+		{fmt.Errorf("wrapped: %w", goint.ErrMemoryLimit), statusMemoryLimit},
+		{goint.ErrInvalidOption, statusInvalidOption},
+		{goint.ErrSingular, statusFailed},
+	} {
+		if got := statusOf(c.err); got != c.status {
+			t.Errorf("%v: got status %d, want %d", c.err, got, c.status)
+		}
+	}
+}
@@ -0,0 +1,262 @@
+package goint
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// ErrSingular is returned when a linear system arising from a
+// discretization cannot be solved.
+var ErrSingular = errors.New("goint: singular linear system")
+
+// Returns a new n x m matrix of zeros.
+func newMatrix(n, m int) [][]float64 {
+	data := make([]float64, n*m)
+	A := make([][]float64, n)
+	for i := range A {
+		A[i] = data[i*m : (i+1)*m]
+	}
+
+	return A
+}
+
+// Solves the square system A x = b by Gaussian elimination with partial
+// pivoting. ErrSingular is returned if a pivot is negligible relative to
+// the largest entry of A. Neither A nor b is modified.
+func solve(A [][]float64, b []float64) ([]float64, error) {
+	n := len(b)
+	M := newMatrix(n, n+1)
+	scale := 0.0
+	for i := range A {
+		copy(M[i], A[i])
+		M[i][n] = b[i]
+		for _, a := range A[i] {
+			scale = math.Max(scale, math.Abs(a))
+		}
+	}
+	tiny := 1e-14 * float64(n) * scale
+
+	for k := 0; k < n; k++ {
+		pivot := k
+		for i := k + 1; i < n; i++ {
+			if math.Abs(M[i][k]) > math.Abs(M[pivot][k]) {
+				pivot = i
+			}
+		}
+		if math.Abs(M[pivot][k]) <= tiny {
+			return nil, ErrSingular
+		}
+		M[k], M[pivot] = M[pivot], M[k]
+
+		for i := k + 1; i < n; i++ {
+			factor := M[i][k] / M[k][k]
+			for j := k; j <= n; j++ {
+				M[i][j] -= factor * M[k][j]
+			}
+		}
+	}
+
+	x := make([]float64, n)
+	for i := n - 1; i >= 0; i-- {
+		sum := M[i][n]
+		for j := i + 1; j < n; j++ {
+			sum -= M[i][j] * x[j]
+		}
+		x[i] = sum / M[i][i]
+	}
+
+	return x, nil
+}
+
+// Computes the thin singular value decomposition A = U diag(s) V^T of the
+// m x n matrix A, m >= n, with the one-sided Jacobi method. Singular values
+// are returned in decreasing order; the columns of U and V are the
+// corresponding singular vectors. A is not modified.
+func svd(A [][]float64) ([][]float64, []float64, [][]float64) {
+	m := len(A)
+	n := len(A[0])
+
+	U := newMatrix(m, n)
+	for i := range A {
+		copy(U[i], A[i])
+	}
+	V := newMatrix(n, n)
+	for i := range V {
+		V[i][i] = 1
+	}
+
+	for sweep := 0; sweep < 60; sweep++ {
+		rotated := false
+		for p := 0; p < n-1; p++ {
+			for q := p + 1; q < n; q++ {
+				alpha, beta, gamma := 0.0, 0.0, 0.0
+				for i := 0; i < m; i++ {
+					alpha += U[i][p] * U[i][p]
+					beta += U[i][q] * U[i][q]
+					gamma += U[i][p] * U[i][q]
+				}
+				if gamma == 0 || math.Abs(gamma) <= 1e-15*math.Sqrt(alpha*beta) {
+					continue
+				}
+				rotated = true
+
+				zeta := (beta - alpha) / (2 * gamma)
+				t := math.Copysign(1, zeta) / (math.Abs(zeta) + math.Sqrt(1+zeta*zeta))
+				c := 1 / math.Sqrt(1+t*t)
+				s := c * t
+				for i := 0; i < m; i++ {
+					up, uq := U[i][p], U[i][q]
+					U[i][p] = c*up - s*uq
+					U[i][q] = s*up + c*uq
+				}
+				for i := 0; i < n; i++ {
+					vp, vq := V[i][p], V[i][q]
+					V[i][p] = c*vp - s*vq
+					V[i][q] = s*vp + c*vq
+				}
+			}
+		}
+		if !rotated {
+			break
+		}
+	}
+
+	s := make([]float64, n)
+	for j := 0; j < n; j++ {
+		norm := 0.0
+		for i := 0; i < m; i++ {
+			norm += U[i][j] * U[i][j]
+		}
+		s[j] = math.Sqrt(norm)
+		if s[j] > 0 {
+			for i := 0; i < m; i++ {
+				U[i][j] /= s[j]
+			}
+		}
+	}
+
+	// Sort the singular triplets into decreasing order
+	order := make([]int, n)
+	for j := range order {
+		order[j] = j
+	}
+	sort.SliceStable(order, func(i, j int) bool { return s[order[i]] > s[order[j]] })
+
+	Us := newMatrix(m, n)
+	Vs := newMatrix(n, n)
+	ss := make([]float64, n)
+	for k, j := range order {
+		ss[k] = s[j]
+		for i := 0; i < m; i++ {
+			Us[i][k] = U[i][j]
+		}
+		for i := 0; i < n; i++ {
+			Vs[i][k] = V[i][j]
+		}
+	}
+
+	return Us, ss, Vs
+}
+
+// Returns the barycentric Lagrange interpolant through the points
+// (nodes[i], values[i]). The nodes must be distinct.
+func interpolant(nodes, values []float64) Function {
+	n := len(nodes)
+	bw := make([]float64, n)
+	for i := range bw {
+		bw[i] = 1
+		for j := range nodes {
+			if j != i {
+				bw[i] /= nodes[i] - nodes[j]
+			}
+		}
+	}
+
+	return func(x float64) float64 {
+		num, den := 0.0, 0.0
+		for i, xi := range nodes {
+			if x == xi {
+				return values[i]
+			}
+			c := bw[i] / (x - xi)
+			num += c * values[i]
+			den += c
+		}
+		return num / den
+	}
+}
+
+// Computes the eigenvalues and eigenvectors of the symmetric matrix A with
+// the cyclic Jacobi method. Eigenvalues are returned in order of decreasing
+// magnitude, and column k of the returned matrix is the unit eigenvector of
+// the k-th eigenvalue. A is not modified.
+func symEigen(A [][]float64) ([]float64, [][]float64) {
+	n := len(A)
+	M := newMatrix(n, n)
+	V := newMatrix(n, n)
+	for i := range A {
+		copy(M[i], A[i])
+		V[i][i] = 1
+	}
+
+	for sweep := 0; sweep < 60; sweep++ {
+		off, diag := 0.0, 0.0
+		for i := 0; i < n; i++ {
+			diag += M[i][i] * M[i][i]
+			for j := i + 1; j < n; j++ {
+				off += M[i][j] * M[i][j]
+			}
+		}
+		if off <= 1e-30*diag || off == 0 {
+			break
+		}
+
+		for p := 0; p < n-1; p++ {
+			for q := p + 1; q < n; q++ {
+				if M[p][q] == 0 {
+					continue
+				}
+				theta := (M[q][q] - M[p][p]) / (2 * M[p][q])
+				t := math.Copysign(1, theta) / (math.Abs(theta) + math.Sqrt(theta*theta+1))
+				c := 1 / math.Sqrt(t*t+1)
+				s := t * c
+
+				for k := 0; k < n; k++ {
+					mkp, mkq := M[k][p], M[k][q]
+					M[k][p] = c*mkp - s*mkq
+					M[k][q] = s*mkp + c*mkq
+				}
+				for k := 0; k < n; k++ {
+					mpk, mqk := M[p][k], M[q][k]
+					M[p][k] = c*mpk - s*mqk
+					M[q][k] = s*mpk + c*mqk
+				}
+				for k := 0; k < n; k++ {
+					vkp, vkq := V[k][p], V[k][q]
+					V[k][p] = c*vkp - s*vkq
+					V[k][q] = s*vkp + c*vkq
+				}
+			}
+		}
+	}
+
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return math.Abs(M[order[i]][order[i]]) > math.Abs(M[order[j]][order[j]])
+	})
+
+	vals := make([]float64, n)
+	vecs := newMatrix(n, n)
+	for k, j := range order {
+		vals[k] = M[j][j]
+		for i := 0; i < n; i++ {
+			vecs[i][k] = V[i][j]
+		}
+	}
+
+	return vals, vecs
+}
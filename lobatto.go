@@ -0,0 +1,64 @@
+package goint
+
+import (
+	"math"
+)
+
+// The most evaluations AdaptiveLobatto makes; once they are spent, every
+// remaining panel is accepted as it stands.
+const lobattoMaxEvals = 1 << 22
+
+// The interior Gauss-Lobatto and Kronrod abscissae on [-1, 1] used by
+// AdaptiveLobatto.
+var (
+	lobattoAlpha = math.Sqrt(2.0 / 3)
+	lobattoBeta  = 1 / math.Sqrt(5)
+)
+
+// AdaptiveLobatto integrates f over [a, b] to within tol with Gander and
+// Gautschi's adaptive Gauss-Lobatto scheme, the algorithm behind MATLAB's
+// quadl. Each panel is estimated with the 4-point Gauss-Lobatto rule and
+// its 7-point Kronrod extension; where they disagree by more than tol,
+// the panel is split into the six subintervals between the Kronrod nodes.
+// As the rules include the endpoints, each subinterval inherits its
+// endpoint values, and the whole interval is compared against one
+// tolerance rather than shares of it, as in the original. Either limit may
+// be infinite, in which case the interval is first mapped onto a finite
+// one. The result is NaN if a bound, tol or the integrand is NaN.
+func AdaptiveLobatto(f Function, a, b, tol float64) float64 {
+	if math.IsNaN(a) || math.IsNaN(b) || math.IsNaN(tol) {
+		return math.NaN()
+	}
+	if a > b {
+		return -AdaptiveLobatto(f, b, a, tol)
+	}
+	g, lo, hi := compactify(f, a, b)
+
+	budget := lobattoMaxEvals - 2
+	return lobattoStep(g, lo, hi, g(lo), g(hi), tol, &budget)
+}
+
+// Integrates over [a, b] given f at both endpoints, drawing evaluations
+// from budget.
+func lobattoStep(f Function, a, b, fa, fb, tol float64, budget *int) float64 {
+	h := (b - a) / 2
+	m := a + h
+	mll, ml := m-lobattoAlpha*h, m-lobattoBeta*h
+	mr, mrr := m+lobattoBeta*h, m+lobattoAlpha*h
+
+	fmll, fml, fm, fmr, fmrr := f(mll), f(ml), f(m), f(mr), f(mrr)
+	*budget -= 5
+	lobatto := h / 6 * (fa + fb + 5*(fml+fmr))
+	kronrod := h / 1470 * (77*(fa+fb) + 432*(fmll+fmrr) + 625*(fml+fmr) + 672*fm)
+
+	if !(math.Abs(kronrod-lobatto) > tol) || *budget <= 0 || mll <= a || b <= mrr {
+		return kronrod
+	}
+
+	return lobattoStep(f, a, mll, fa, fmll, tol, budget) +
+		lobattoStep(f, mll, ml, fmll, fml, tol, budget) +
+		lobattoStep(f, ml, m, fml, fm, tol, budget) +
+		lobattoStep(f, m, mr, fm, fmr, tol, budget) +
+		lobattoStep(f, mr, mrr, fmr, fmrr, tol, budget) +
+		lobattoStep(f, mrr, b, fmrr, fb, tol, budget)
+}
@@ -0,0 +1,40 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAdaptiveLobatto(t *testing.T) {
+	const tol = 1e-10
+
+	inf := math.Inf(1)
+	cases := []struct {
+		f       Function
+		a, b    float64
+		correct float64
+	}{
+		{math.Exp, 0, 1, math.E - 1},
+		{math.Exp, 1, 0, 1 - math.E},
+		{func(x float64) float64 { return math.Sqrt(x) }, 0, 1, 2.0 / 3},
+		{func(x float64) float64 { return 1 / (1e-4 + x*x) }, -1, 1, 200 * math.Atan(100)},
+		{func(x float64) float64 { return math.Exp(-x) }, 0, inf, 1},
+	}
+
+	for i, c := range cases {
+		if msg, ok := checkValue(AdaptiveLobatto(c.f, c.a, c.b, tol), c.correct, 100*tol); !ok {
+			t.Errorf("case %d: %s", i, msg)
+		}
+	}
+
+	// Smooth integrands need few evaluations
+	evals := 0
+	AdaptiveLobatto(func(x float64) float64 { evals++; return math.Exp(x) }, 0, 1, tol)
+	if evals > 50 {
+		t.Errorf("%d evaluations for exp", evals)
+	}
+
+	if !math.IsNaN(AdaptiveLobatto(math.Exp, 0, 1, math.NaN())) {
+		t.Error("expected NaN for a NaN tolerance")
+	}
+}
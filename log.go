@@ -0,0 +1,165 @@
+package goint
+
+import (
+	"encoding/csv"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// An EvaluationRecord describes one evaluation of the integrand made by an
+// adaptive integrator.
+type EvaluationRecord struct {
+	// X is the point of evaluation and Y the value of the integrand there.
+	X, Y float64
+
+	// Panel numbers the panel estimates in the order they were made, from
+	// zero; a bisected panel's children get new numbers. It is -1 for
+	// evaluations outside any panel estimate, such as those of
+	// WithPrecisionCheck and WithMonotone.
+	Panel int
+
+	// Level is the number of bisections separating the panel from the
+	// initial partition.
+	Level int
+}
+
+// A RecordWriter receives the records of an evaluation log. CSVLog writes
+// them as text and ArrowLog as an Arrow IPC stream, which standard tooling
+// can analyze directly or convert to Parquet.
+type RecordWriter interface {
+	WriteRecord(r EvaluationRecord) error
+}
+
+// WithEvaluationLog passes a record of every evaluation of the integrand
+// made by IntegrateGK or IntegrateBatch to w, as it is made. After the
+// first error from w no more records are written, and the error is
+// returned in place of a nil one.
+func WithEvaluationLog(w RecordWriter) Option {
+	return func(c *config) { c.log = w }
+}
+
+// A CSVLog is a RecordWriter writing comma-separated lines of x, y, panel
+// and level, preceded by a header line. Records are buffered; Flush must
+// be called once the log is complete.
+type CSVLog struct {
+	w      *csv.Writer
+	header bool
+}
+
+// NewCSVLog returns a CSVLog writing to w.
+func NewCSVLog(w io.Writer) *CSVLog {
+	return &CSVLog{w: csv.NewWriter(w)}
+}
+
+// WriteRecord writes r as a single line.
+func (l *CSVLog) WriteRecord(r EvaluationRecord) error {
+	if !l.header {
+		l.header = true
+		if err := l.w.Write([]string{"x", "y", "panel", "level"}); err != nil {
+			return err
+		}
+	}
+	return l.w.Write([]string{
+		strconv.FormatFloat(r.X, 'g', -1, 64),
+		strconv.FormatFloat(r.Y, 'g', -1, 64),
+		strconv.Itoa(r.Panel),
+		strconv.Itoa(r.Level),
+	})
+}
+
+// Flush writes any buffered lines, returning the first error met.
+func (l *CSVLog) Flush() error {
+	l.w.Flush()
+	return l.w.Error()
+}
+
+// An evaluationLog tags the evaluations of an integrand with the panel
+// being estimated and passes them on to a RecordWriter.
+type evaluationLog struct {
+	w      RecordWriter
+	points []float64
+	panels int
+	panel  int
+	level  int
+	err    error
+
+	// Reports whether evaluations are not yet real, as while a batcher
+	// is recording
+	skip func() bool
+}
+
+// Returns a log writing to w. Its points must be set to the initial
+// partition before any panel is estimated.
+func newEvaluationLog(w RecordWriter) *evaluationLog {
+	return &evaluationLog{w: w, panel: -1}
+}
+
+// Returns f wrapped to log each evaluation.
+func (l *evaluationLog) record(f Function) Function {
+	return func(x float64) float64 {
+		y := f(x)
+		if l.err == nil && (l.skip == nil || !l.skip()) {
+			l.err = l.w.WriteRecord(EvaluationRecord{X: x, Y: y, Panel: l.panel, Level: l.level})
+		}
+		return y
+	}
+}
+
+// Returns rule with the evaluations of each call tagged with a new panel
+// number and the panel's level.
+func (l *evaluationLog) rule(rule panelRule) panelRule {
+	return func(g Function, lo, hi float64) (float64, float64) {
+		l.panel, l.level = l.panels, l.levelOf(lo, hi)
+		l.panels++
+		value, err := rule(g, lo, hi)
+		l.panel, l.level = -1, 0
+		return value, err
+	}
+}
+
+// Returns the number of bisections from the initial panel containing
+// [lo, hi] to it.
+func (l *evaluationLog) levelOf(lo, hi float64) int {
+	i := sort.SearchFloat64s(l.points, lo)
+	if i == len(l.points) || l.points[i] > lo {
+		i--
+	}
+	if i < 0 || i+1 >= len(l.points) || !(hi > lo) {
+		return 0
+	}
+	return int(math.Round(math.Log2((l.points[i+1] - l.points[i]) / (hi - lo))))
+}
+
+// ReadCSVLog reads the records written by a CSVLog.
+func ReadCSVLog(r io.Reader) ([]EvaluationRecord, error) {
+	lines, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(lines) > 0 && lines[0][0] == "x" {
+		lines = lines[1:]
+	}
+	records := make([]EvaluationRecord, 0, len(lines))
+	for _, line := range lines {
+		if len(line) != 4 {
+			return nil, ErrInvalidInput
+		}
+		var rec EvaluationRecord
+		var errs [4]error
+		rec.X, errs[0] = strconv.ParseFloat(line[0], 64)
+		rec.Y, errs[1] = strconv.ParseFloat(line[1], 64)
+		rec.Panel, errs[2] = strconv.Atoi(line[2])
+		rec.Level, errs[3] = strconv.Atoi(line[3])
+		for _, err := range errs {
+			if err != nil {
+				return nil, err
+			}
+		}
+		records = append(records, rec)
+	}
+
+	return records, nil
+}
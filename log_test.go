@@ -0,0 +1,131 @@
+package goint
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+// A RecordWriter keeping its records, failing after fail of them if fail
+// is positive.
+type recordSlice struct {
+	records []EvaluationRecord
+	fail    int
+}
+
+var errRecordSlice = errors.New("record slice full")
+
+func (s *recordSlice) WriteRecord(r EvaluationRecord) error {
+	if s.fail > 0 && len(s.records) == s.fail {
+		return errRecordSlice
+	}
+	s.records = append(s.records, r)
+	return nil
+}
+
+func TestWithEvaluationLog(t *testing.T) {
+	f := func(x float64) float64 { return math.Sqrt(x) }
+
+	var log recordSlice
+	r, err := IntegrateGK(f, 0, 1, 1e-10, WithEvaluationLog(&log))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(log.records) != r.Evals {
+		t.Errorf("%d records of %d evaluations", len(log.records), r.Evals)
+	}
+	deepest := 0
+	for i, rec := range log.records {
+		if rec.Y != f(rec.X) {
+			t.Errorf("record %d: f(%g) = %g, logged %g", i, rec.X, f(rec.X), rec.Y)
+		}
+		if rec.Panel != i/15 {
+			t.Errorf("record %d: panel %d, expected %d", i, rec.Panel, i/15)
+		}
+		if rec.Level < 0 || rec.Level > 60 {
+			t.Errorf("record %d: level %d", i, rec.Level)
+		}
+		deepest = max(deepest, rec.Level)
+	}
+	// Bisection towards the singularity at 0 goes many levels deep
+	if deepest < 10 {
+		t.Errorf("deepest level %d", deepest)
+	}
+
+	// Evaluations outside the panels
+	log = recordSlice{}
+	r, _ = IntegrateGK(f, 0, 1, 1e-6, WithEvaluationLog(&log), WithMonotone())
+	if len(log.records) != r.Evals {
+		t.Errorf("%d records of %d evaluations", len(log.records), r.Evals)
+	}
+	if last := log.records[len(log.records)-1]; last.Panel != -1 {
+		t.Errorf("endpoint evaluation in panel %d", last.Panel)
+	}
+
+	// Batched evaluations are logged once
+	log = recordSlice{}
+	batch := func(xs, ys []float64) {
+		for i, x := range xs {
+			ys[i] = f(x)
+		}
+	}
+	r, _ = IntegrateBatch(batch, 0, 1, 1e-6, WithEvaluationLog(&log))
+	if len(log.records) != r.Evals {
+		t.Errorf("batched: %d records of %d evaluations", len(log.records), r.Evals)
+	}
+
+	// Writer errors
+	log = recordSlice{fail: 20}
+	if _, err := IntegrateGK(f, 0, 1, 1e-6, WithEvaluationLog(&log)); err != errRecordSlice {
+		t.Errorf("got error %v, expected %v", err, errRecordSlice)
+	}
+	if len(log.records) != 20 {
+		t.Errorf("%d records written after failure", len(log.records))
+	}
+}
+
+func TestCSVLog(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewCSVLog(&buf)
+	r, err := IntegrateGK(math.Exp, 0, 1, 1e-10, WithEvaluationLog(w))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	text := buf.String()
+	lines, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lines) != r.Evals+1 {
+		t.Fatalf("%d lines for %d evaluations", len(lines), r.Evals)
+	}
+	if got := lines[0]; len(got) != 4 || got[0] != "x" || got[3] != "level" {
+		t.Errorf("header %q", got)
+	}
+	if got := lines[1]; got[2] != "0" || got[3] != "0" {
+		t.Errorf("first record %q", got)
+	}
+
+	records, err := ReadCSVLog(strings.NewReader(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	replayed, err := Replay(records, 0, 1, 1e-10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replayed.Value != r.Value {
+		t.Errorf("replayed %g, recorded %g", replayed.Value, r.Value)
+	}
+
+	if _, err := ReadCSVLog(strings.NewReader("x,y,panel,level\n1,2,3\n")); err == nil {
+		t.Error("expected error for a short line")
+	}
+}
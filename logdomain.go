@@ -0,0 +1,33 @@
+package goint
+
+import (
+	"math"
+)
+
+// IntegrateLog integrates f over [a, b] to within err after substituting
+// x = exp(u), so that Integrate refines uniformly in log x rather than in
+// x. Integrands spanning many decades, such as spectra and power laws, are
+// then resolved evenly on every scale. a must be nonnegative, and may be
+// zero and b infinite, which map to infinite limits in u; otherwise the
+// result is NaN.
+func IntegrateLog(f Function, a, b, err float64) float64 {
+	if !(a >= 0) || !(b >= 0) {
+		return math.NaN()
+	}
+
+	g := func(u float64) float64 {
+		// Far enough out x underflows or overflows, where an integrable f
+		// contributes nothing
+		x := math.Exp(u)
+		if x == 0 || math.IsInf(x, 1) {
+			return 0
+		}
+		return f(x) * x
+	}
+
+	if a > b {
+		return -IntegrateLog(f, b, a, err)
+	}
+
+	return Integrate(g, math.Log(a), math.Log(b), err)
+}
@@ -0,0 +1,32 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestIntegrateLog(t *testing.T) {
+	const tol = 1e-9
+
+	inf := math.Inf(1)
+	cases := []struct {
+		f       Function
+		a, b    float64
+		correct float64
+	}{
+		{func(x float64) float64 { return 1 / x }, 1e-6, 1e6, 12 * math.Ln10},
+		{func(x float64) float64 { return math.Pow(x, -1.5) }, 1, inf, 2},
+		{func(x float64) float64 { return 1 / (math.Sqrt(x) * (1 + x)) }, 0, inf, math.Pi},
+		{func(x float64) float64 { return x }, 2, 1, -1.5},
+	}
+
+	for i, c := range cases {
+		if msg, ok := checkValue(IntegrateLog(c.f, c.a, c.b, tol), c.correct, 10*tol); !ok {
+			t.Errorf("case %d: %s", i, msg)
+		}
+	}
+
+	if !math.IsNaN(IntegrateLog(math.Exp, -1, 1, tol)) {
+		t.Error("expected NaN for a negative limit")
+	}
+}
@@ -0,0 +1,90 @@
+package goint
+
+import (
+	"math"
+	"sort"
+)
+
+// The most bisections used to locate the ends of a positive excursion.
+const massBisections = 60
+
+// A MassInterval is a maximal interval on which an integrand is positive,
+// with its integral there.
+type MassInterval struct {
+	A, B float64
+	Mass float64
+}
+
+// FindMassIntervals returns, in increasing order, the maximal
+// subintervals of the finite interval [a, b] on which f is positive and
+// whose integral exceeds threshold, such as the peaks of a spectrum or the
+// periods of high load in a profile; subtract any baseline from f first.
+// The sign of f is resolved on the samples of its adaptive integration
+// over [a, b] to within tol, so excursions, and dips between them, that
+// fall between samples of that partition are missed. Each change of sign
+// between samples is located by bisection, and each excursion integrated
+// to within an equal share of tol. Infinite or NaN limits, b <= a, a NaN
+// threshold or a NaN or negative tolerance give ErrInvalidInput; if any
+// integration falls short of its tolerance, the intervals are returned
+// with ErrNotConverged.
+func FindMassIntervals(f Function, a, b, threshold, tol float64) ([]MassInterval, error) {
+	if math.IsNaN(a) || math.IsNaN(b) || math.IsInf(a, 0) || math.IsInf(b, 0) || !(a < b) ||
+		math.IsNaN(threshold) || !(tol >= 0) {
+		return nil, ErrInvalidInput
+	}
+
+	samples := &sampleSet{}
+	_, status := IntegrateGK(samples.record(f), a, b, tol)
+	sort.Sort(samples)
+
+	// The positive runs of samples, extended to the sign changes
+	var runs [][2]float64
+	start := math.NaN()
+	if len(samples.xs) > 0 && samples.ys[0] > 0 {
+		start = a
+	}
+	for i := 1; i < len(samples.xs); i++ {
+		was, is := samples.ys[i-1] > 0, samples.ys[i] > 0
+		if was == is {
+			continue
+		}
+		x := bisectSign(f, samples.xs[i-1], samples.xs[i], was)
+		if is {
+			start = x
+		} else {
+			runs = append(runs, [2]float64{start, x})
+		}
+	}
+	if n := len(samples.xs); n > 0 && samples.ys[n-1] > 0 {
+		runs = append(runs, [2]float64{start, b})
+	}
+
+	var intervals []MassInterval
+	for _, run := range runs {
+		r, err := IntegrateGK(f, run[0], run[1], tol/float64(len(runs)))
+		if err != nil {
+			status = err
+		}
+		if r.Value > threshold {
+			intervals = append(intervals, MassInterval{A: run[0], B: run[1], Mass: r.Value})
+		}
+	}
+	return intervals, status
+}
+
+// Returns the point in [lo, hi] at which f changes from positive to not,
+// or the reverse if positive is false, found by bisection.
+func bisectSign(f Function, lo, hi float64, positive bool) float64 {
+	for i := 0; i < massBisections; i++ {
+		mid := lo + (hi-lo)/2
+		if mid <= lo || mid >= hi {
+			break
+		}
+		if (f(mid) > 0) == positive {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return lo + (hi-lo)/2
+}
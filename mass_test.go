@@ -0,0 +1,59 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFindMassIntervals(t *testing.T) {
+	// Three bumps on [0, 6] of heights 1, 0.1 and 2, each sin^2 over a
+	// unit interval, with zero in between
+	bump := func(x, lo, height float64) float64 {
+		if x <= lo || x >= lo+1 {
+			return 0
+		}
+		s := math.Sin(math.Pi * (x - lo))
+		return height * s * s
+	}
+	f := func(x float64) float64 {
+		return bump(x, .5, 1) + bump(x, 2.5, .1) + bump(x, 4.25, 2)
+	}
+
+	got, err := FindMassIntervals(f, 0, 6, .2, 1e-10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []MassInterval{{.5, 1.5, .5}, {4.25, 5.25, 1}}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, expected %v", got, want)
+	}
+	for i := range want {
+		for _, pair := range [][2]float64{{got[i].A, want[i].A}, {got[i].B, want[i].B}, {got[i].Mass, want[i].Mass}} {
+			if msg, ok := checkValue(pair[0], pair[1], 1e-8); !ok {
+				t.Errorf("interval %d: %s", i, msg)
+			}
+		}
+	}
+
+	// Excursions reaching the ends of the interval, separated by a
+	// negative one
+	got, err = FindMassIntervals(math.Cos, 0, 2*math.Pi, 0, 1e-10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].A != 0 || got[1].B != 2*math.Pi {
+		t.Fatalf("got %v", got)
+	}
+	for i, wantA := range []float64{0, 1.5 * math.Pi} {
+		if msg, ok := checkValue(got[i].A, wantA, 1e-12); !ok {
+			t.Error(msg)
+		}
+		if msg, ok := checkValue(got[i].Mass, 1, 1e-10); !ok {
+			t.Error(msg)
+		}
+	}
+
+	if _, err := FindMassIntervals(f, 0, math.Inf(1), 0, 1e-10); err != ErrInvalidInput {
+		t.Errorf("got error %v, expected %v", err, ErrInvalidInput)
+	}
+}
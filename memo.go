@@ -0,0 +1,16 @@
+package goint
+
+// Returns a Function that caches the values of f by abscissa, so that
+// repeated evaluations at the same point call f only once. The returned
+// Function is not safe for concurrent use.
+func memoize(f Function) Function {
+	cache := make(map[float64]float64)
+	return func(x float64) float64 {
+		if y, ok := cache[x]; ok {
+			return y
+		}
+		y := f(x)
+		cache[x] = y
+		return y
+	}
+}
@@ -0,0 +1,94 @@
+package goint
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// ErrBudgetExhausted is returned with the partial result of an
+// integration stopped by the budget of WithEvaluationBudget before
+// meeting its tolerance.
+var ErrBudgetExhausted = errors.New("goint: evaluation budget exhausted")
+
+// WithEvaluationBudget limits IntegrateGK, and the integrators built on
+// it, to at most n evaluations of the integrand, for integrands that call
+// a metered service with an external quota. Refinement stops once another
+// bisection could overrun the budget, and the estimate so far is
+// returned, with ErrBudgetExhausted unless it meets the tolerance. Any
+// evaluation requested beyond the budget, as by WithPrecisionCheck, is
+// refused and taken to be NaN. A non-positive n means no budget.
+func WithEvaluationBudget(n int) Option {
+	return func(c *config) { c.budget = n }
+}
+
+// WithRateLimit spaces the evaluations of the integrand made by
+// IntegrateGK, and the integrators built on it, at least 1/perSecond
+// seconds apart, sleeping as needed, so that a metered service is never
+// called faster than it allows. A non-positive or infinite rate means no
+// limit.
+func WithRateLimit(perSecond float64) Option {
+	return func(c *config) { c.rate = perSecond }
+}
+
+// A meter enforces the evaluation budget and rate limit of a
+// configuration.
+type meter struct {
+	budget   int
+	interval time.Duration
+	next     time.Time
+
+	calls    int
+	perPanel int
+	refused  bool
+	stopped  bool
+}
+
+// Returns the meter for c, or nil if it sets no budget or rate limit.
+func (c *config) meter() *meter {
+	m := &meter{}
+	if c.budget > 0 {
+		m.budget = c.budget
+	}
+	if c.rate > 0 && !math.IsInf(c.rate, 1) {
+		m.interval = time.Duration(float64(time.Second) / c.rate)
+	}
+	if m.budget == 0 && m.interval == 0 {
+		return nil
+	}
+	return m
+}
+
+// Returns f wrapped to refuse evaluations beyond the budget and to wait
+// out the rate limit.
+func (m *meter) record(f Function) Function {
+	return func(x float64) float64 {
+		if m.budget > 0 && m.calls >= m.budget {
+			m.refused = true
+			return math.NaN()
+		}
+		if m.interval > 0 {
+			now := time.Now()
+			if wait := m.next.Sub(now); wait > 0 {
+				time.Sleep(wait)
+				now = m.next
+			}
+			m.next = now.Add(m.interval)
+		}
+		m.calls++
+		return f(x)
+	}
+}
+
+// Reports whether refinement must stop to keep within the budget, the
+// evaluations of the initial panels having shown what each panel costs.
+func (m *meter) stop(initial int) bool {
+	if m.budget == 0 {
+		return false
+	}
+	if m.perPanel == 0 {
+		m.perPanel = max(m.calls/max(initial, 1), 1)
+	}
+	m.stopped = m.stopped || m.refused || m.calls+2*m.perPanel > m.budget
+	return m.stopped
+}
@@ -0,0 +1,65 @@
+package goint
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestEvaluationBudget(t *testing.T) {
+	calls := 0
+	f := func(x float64) float64 {
+		calls++
+		return math.Sqrt(x)
+	}
+
+	// The unbudgeted integration needs far more than the budget
+	full, _ := IntegrateGK(math.Sqrt, 0, 1, 1e-13)
+	if full.Evals <= 200 {
+		t.Fatalf("only %d evaluations", full.Evals)
+	}
+
+	r, err := IntegrateGK(f, 0, 1, 1e-13, WithEvaluationBudget(200))
+	if err != ErrBudgetExhausted {
+		t.Errorf("got %v", err)
+	}
+	if calls > 200 || r.Evals != calls {
+		t.Errorf("%d calls, %d evaluations reported", calls, r.Evals)
+	}
+	if math.Abs(r.Value-2./3) > r.Error || r.Error > 1e-4 {
+		t.Errorf("partial result %g with error estimate %g", r.Value, r.Error)
+	}
+
+	// A budget that suffices does not change the result
+	r, err = IntegrateGK(math.Sqrt, 0, 1, 1e-13, WithEvaluationBudget(full.Evals+30))
+	if err != nil || r.Value != full.Value {
+		t.Errorf("got %g, %v, want %g", r.Value, err, full.Value)
+	}
+
+	// A budget smaller than the first panel gives nothing
+	calls = 0
+	r, err = IntegrateGK(f, 0, 1, 1e-13, WithEvaluationBudget(10))
+	if err != ErrBudgetExhausted || calls != 10 || !math.IsNaN(r.Value) {
+		t.Errorf("got %g, %v after %d calls", r.Value, err, calls)
+	}
+
+	if _, err := IntegrateBatch(func(xs, ys []float64) {}, 0, 1, 1e-8, WithEvaluationBudget(10)); err != ErrInvalidOption {
+		t.Errorf("got %v from IntegrateBatch", err)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	var times []time.Time
+	f := func(x float64) float64 {
+		times = append(times, time.Now())
+		return x
+	}
+
+	r, err := IntegrateGK(f, 0, 1, 1e-10, WithRateLimit(1000))
+	if err != nil || r.Evals != 15 {
+		t.Fatalf("%d evaluations, %v", r.Evals, err)
+	}
+	if elapsed := times[len(times)-1].Sub(times[0]); elapsed < 14*time.Millisecond {
+		t.Errorf("15 evaluations in %v", elapsed)
+	}
+}
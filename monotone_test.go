@@ -0,0 +1,43 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestWithMonotone(t *testing.T) {
+	tol := 1e-10
+	cases := []struct {
+		f       Function
+		a, b    float64
+		correct float64
+	}{
+		{math.Exp, 0, 1, math.E - 1},
+		{func(x float64) float64 { return -math.Sqrt(x) }, 0, 4, -16.0 / 3},
+		{math.Atan, 1, -2, (2*math.Atan(2) - math.Log(5)/2 - math.Pi/4 + math.Log(2)/2)},
+	}
+
+	for i, c := range cases {
+		r, err := IntegrateGK(c.f, c.a, c.b, tol, WithMonotone())
+		if err != nil {
+			t.Errorf("case %d: %v", i, err)
+		}
+		if msg, ok := checkValue(r.Value, c.correct, tol); !ok {
+			t.Errorf("case %d: %s", i, msg)
+		}
+		if !(r.Lower <= c.correct && c.correct <= r.Upper) {
+			t.Errorf("case %d: [%v, %v] does not contain %v", i, r.Lower, r.Upper, c.correct)
+		}
+		// The samples are no more than a quarter of the interval apart
+		if gap := math.Abs((c.f(c.b) - c.f(c.a)) * (c.b - c.a)); !(r.Upper-r.Lower <= gap/4) {
+			t.Errorf("case %d: loose bounds [%v, %v]", i, r.Lower, r.Upper)
+		}
+	}
+
+	if _, err := IntegrateGK(math.Sin, 0, 10, tol, WithMonotone()); err != ErrNotMonotone {
+		t.Errorf("expected ErrNotMonotone, got %v", err)
+	}
+	if _, err := IntegrateGK(math.Exp, math.Inf(-1), 0, tol, WithMonotone()); err != ErrInvalidOption {
+		t.Errorf("expected ErrInvalidOption, got %v", err)
+	}
+}
@@ -0,0 +1,137 @@
+package goint
+
+import (
+	"math"
+)
+
+// MultivariateCDF computes the cumulative distribution function of the
+// density pdf at upper, the integral of pdf over the orthant
+// (-Inf, upper[0]] x ... x (-Inf, upper[n-1]], to within tol using
+// quasi-Monte Carlo. Each coordinate is mapped onto (0, 1] with
+// x = upper[i] - (1 - t) / t, which concentrates points near the corner
+// of the orthant where most of the mass of a well-centred density lies.
+func MultivariateCDF(pdf MultiFunction, upper []float64, tol float64) float64 {
+	dim := len(upper)
+	x := make([]float64, dim)
+
+	g := func(t []float64) float64 {
+		jacobian := 1.0
+		for i, ti := range t {
+			if ti == 0 {
+				return 0
+			}
+			x[i] = upper[i] - (1-ti)/ti
+			jacobian /= ti * ti
+		}
+		return pdf(x) * jacobian
+	}
+
+	value, _ := IntegrateQMC(g, dim, tol)
+	return value
+}
+
+// Returns the standard normal cumulative distribution function.
+func normalCDF(x float64) float64 {
+	return math.Erfc(-x/math.Sqrt2) / 2
+}
+
+// Returns the inverse of the standard normal cumulative distribution
+// function.
+func normalQuantile(p float64) float64 {
+	return -math.Sqrt2 * math.Erfcinv(2*p)
+}
+
+// Reports whether A is an n by n matrix.
+func isSquare(A [][]float64, n int) bool {
+	if len(A) != n {
+		return false
+	}
+	for _, row := range A {
+		if len(row) != n {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Computes the lower-triangular Cholesky factor of the positive definite
+// matrix A, returning nil if A is not positive definite.
+func cholesky(A [][]float64) [][]float64 {
+	n := len(A)
+	L := newMatrix(n, n)
+	for i := 0; i < n; i++ {
+		for j := 0; j <= i; j++ {
+			sum := A[i][j]
+			for k := 0; k < j; k++ {
+				sum -= L[i][k] * L[j][k]
+			}
+			if i == j {
+				if !(sum > 0) {
+					return nil
+				}
+				L[i][i] = math.Sqrt(sum)
+			} else {
+				L[i][j] = sum / L[j][j]
+			}
+		}
+	}
+
+	return L
+}
+
+// MultivariateNormalCDF computes P(X <= upper) for X normal with mean zero
+// and covariance matrix cov, to within tol. The orthant probability is
+// rewritten with Genz's separation of variables as an integral over a
+// cube of one fewer dimension with a smooth integrand, which is then
+// integrated with quasi-Monte Carlo. Entries of upper may be +Inf or -Inf.
+// NaN is returned if upper is empty, cov is not a square matrix of the
+// same dimension or it is not positive definite.
+func MultivariateNormalCDF(upper []float64, cov [][]float64, tol float64) float64 {
+	if len(upper) == 0 || !isSquare(cov, len(upper)) {
+		return math.NaN()
+	}
+	L := cholesky(cov)
+	if L == nil {
+		return math.NaN()
+	}
+
+	dim := len(upper)
+	e1 := normalCDF(upper[0] / L[0][0])
+	if dim == 1 {
+		return e1
+	}
+
+	y := make([]float64, dim)
+	g := func(w []float64) float64 {
+		e := e1
+		f := e1
+		for i := 1; i < dim; i++ {
+			y[i-1] = normalQuantile(w[i-1] * e)
+			s := upper[i]
+			for j := 0; j < i; j++ {
+				s -= L[i][j] * y[j]
+			}
+			e = normalCDF(s / L[i][i])
+			f *= e
+		}
+		return f
+	}
+
+	value, _ := IntegrateQMC(g, dim-1, tol)
+	return value
+}
+
+// GaussianCopula evaluates the Gaussian copula with correlation matrix corr
+// at u, the probability that every component of a correlated uniform
+// vector lies below the corresponding entry of u, to within tol. NaN is
+// returned if u is empty or corr is not a square matrix of the same
+// dimension.
+func GaussianCopula(u []float64, corr [][]float64, tol float64) float64 {
+	upper := make([]float64, len(u))
+	for i, ui := range u {
+		upper[i] = normalQuantile(ui)
+	}
+
+	return MultivariateNormalCDF(upper, corr, tol)
+}
@@ -0,0 +1,106 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestIntegrateQMC(t *testing.T) {
+	const (
+		tol = 1e-5
+	)
+
+	// The integral of prod(1 + (x_i - 1/2)) over the unit cube is one
+	f := func(x []float64) float64 {
+		ret := 1.0
+		for _, xi := range x {
+			ret *= 1 + (xi - .5)
+		}
+		return ret
+	}
+
+	value, err := IntegrateQMC(f, 5, tol)
+	if err > tol {
+		t.Errorf("error estimate %g exceeds %g", err, tol)
+	}
+	if msg, ok := checkValue(value, 1, 10*tol); !ok {
+		t.Error(msg)
+	}
+}
+
+func TestMultivariateNormalCDF(t *testing.T) {
+	const (
+		tol = 1e-5
+	)
+
+	// Independent components factor
+	eye := [][]float64{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}
+	upper := []float64{.5, -1, 2}
+	want := normalCDF(.5) * normalCDF(-1) * normalCDF(2)
+	if msg, ok := checkValue(MultivariateNormalCDF(upper, eye, tol), want, tol); !ok {
+		t.Error(msg)
+	}
+
+	// The bivariate orthant probability at zero is 1/4 + asin(rho) / (2 pi)
+	rho := .6
+	corr := [][]float64{{1, rho}, {rho, 1}}
+	want = .25 + math.Asin(rho)/(2*math.Pi)
+	if msg, ok := checkValue(MultivariateNormalCDF([]float64{0, 0}, corr, tol), want, tol); !ok {
+		t.Error(msg)
+	}
+	if msg, ok := checkValue(GaussianCopula([]float64{.5, .5}, corr, tol), want, tol); !ok {
+		t.Error(msg)
+	}
+
+	if !math.IsNaN(MultivariateNormalCDF([]float64{0, 0}, [][]float64{{1, 2}, {2, 1}}, tol)) {
+		t.Error("expected NaN for an indefinite covariance")
+	}
+
+	// Missing or mismatched shapes
+	for i, c := range []struct {
+		upper []float64
+		cov   [][]float64
+	}{
+		{nil, nil},
+		{[]float64{}, [][]float64{}},
+		{[]float64{0, 0}, nil},
+		{[]float64{0, 0}, eye},
+		{[]float64{0, 0, 0}, [][]float64{{1, 0, 0}, {0, 1}, {0, 0, 1}}},
+	} {
+		if v := MultivariateNormalCDF(c.upper, c.cov, tol); !math.IsNaN(v) {
+			t.Errorf("case %d: expected NaN, got %g", i, v)
+		}
+		if v := GaussianCopula(c.upper, c.cov, tol); !math.IsNaN(v) {
+			t.Errorf("case %d: expected NaN from the copula, got %g", i, v)
+		}
+	}
+}
+
+func TestMultivariateCDF(t *testing.T) {
+	const (
+		tol = 1e-4
+	)
+
+	// Two independent unit exponentials, using only their upper corner
+	pdf := func(x []float64) float64 {
+		if x[0] < 0 || x[1] < 0 {
+			return 0
+		}
+		return math.Exp(-x[0] - x[1])
+	}
+
+	// The normal density with an identity covariance
+	normal := func(x []float64) float64 {
+		return math.Exp(-(x[0]*x[0]+x[1]*x[1])/2) / (2 * math.Pi)
+	}
+
+	got := MultivariateCDF(pdf, []float64{1, 2}, tol)
+	if msg, ok := checkValue(got, (1-math.Exp(-1))*(1-math.Exp(-2)), 1e-3); !ok {
+		t.Error(msg)
+	}
+
+	got = MultivariateCDF(normal, []float64{0, 1}, tol)
+	if msg, ok := checkValue(got, normalCDF(0)*normalCDF(1), 1e-3); !ok {
+		t.Error(msg)
+	}
+}
@@ -0,0 +1,188 @@
+package goint
+
+import (
+	"container/heap"
+	"math"
+)
+
+// NormalizedExpectation returns the ratio of the integrals of g w and w
+// over [a, b], the expectation of g under the density proportional to w,
+// as needed for self-normalized importance estimates and Boltzmann
+// averages. Both integrals are computed on one adaptive partition from the
+// same evaluations of w, and the error of the ratio R is estimated from
+// the correlated errors of the two: on each panel it is the difference
+// between the 15-point Kronrod and 7-point Gauss estimates of the integral
+// of (g - R) w, relative to the integral of w. Errors common to both
+// integrals therefore cancel, and panels where g is nearly constant need
+// no refinement however large w is there. Either limit may be infinite. The
+// value is NaN if w integrates to zero; if the tolerance cannot be met, the
+// best estimate is returned with ErrNotConverged. A NaN limit or a
+// negative or NaN tolerance gives NaN with ErrInvalidInput.
+func NormalizedExpectation(g, w Function, a, b, tol float64) (Result, error) {
+	if math.IsNaN(a) || math.IsNaN(b) || !(tol >= 0) {
+		return Result{Value: math.NaN()}, ErrInvalidInput
+	}
+	if a > b {
+		a, b = b, a
+	}
+
+	evals := 0
+	x := uncompact(a, b)
+	weight, lo, hi := compactify(w, a, b)
+	pair := func(t float64) (float64, float64) {
+		evals++
+		wt := weight(t)
+		if wt == 0 {
+			return 0, 0
+		}
+		return g(x(t)) * wt, wt
+	}
+
+	s := &ratioPanels{}
+	s.add(pair, lo, hi)
+
+	// The ratio is refreshed whenever the partition doubles and before
+	// convergence is accepted
+	ratio := s.refresh()
+	refreshed := 1
+	for {
+		if s.total <= tol*math.Abs(s.den) {
+			if ratio = s.refresh(); s.total <= tol*math.Abs(s.den) {
+				break
+			}
+			refreshed = len(s.lefts)
+		}
+		if len(s.lefts) >= maxPanels || !s.bisect(pair) {
+			ratio = s.refresh()
+			r := Result{Value: ratio, Error: s.total / math.Abs(s.den), Evals: evals, Panels: len(s.lefts)}
+			return r, ErrNotConverged
+		}
+		if len(s.lefts) >= 2*refreshed {
+			ratio = s.refresh()
+			refreshed = len(s.lefts)
+		}
+	}
+
+	return Result{Value: ratio, Error: s.total / math.Abs(s.den), Evals: evals, Panels: len(s.lefts)}, nil
+}
+
+// An adaptive partition for the integrals N of g w and D of w. For each
+// panel it holds the Kronrod estimates of both and the differences from
+// the Gauss estimates; the error of a panel is that of N - R D for the
+// current ratio R. order is a heap of panel indices with the largest error
+// on top.
+type ratioPanels struct {
+	lefts, rights []float64
+	nums, dens    []float64
+	dnums, ddens  []float64
+	errors        []float64
+	order         []int
+
+	ratio, num, den, total float64
+}
+
+func (s *ratioPanels) Len() int           { return len(s.order) }
+func (s *ratioPanels) Less(i, j int) bool { return s.errors[s.order[i]] > s.errors[s.order[j]] }
+func (s *ratioPanels) Swap(i, j int)      { s.order[i], s.order[j] = s.order[j], s.order[i] }
+func (s *ratioPanels) Push(x interface{}) { s.order = append(s.order, x.(int)) }
+func (s *ratioPanels) Pop() interface{} {
+	i := s.order[len(s.order)-1]
+	s.order = s.order[:len(s.order)-1]
+	return i
+}
+
+// Integrates over [a, b], storing the panel and returning its index
+// without touching the heap.
+func (s *ratioPanels) add(pair func(float64) (float64, float64), a, b float64) int {
+	num, dnum, den, dden := gk15.pair(pair, a, b)
+	err := math.Abs(dnum - s.ratio*dden)
+
+	s.lefts = append(s.lefts, a)
+	s.rights = append(s.rights, b)
+	s.nums = append(s.nums, num)
+	s.dens = append(s.dens, den)
+	s.dnums = append(s.dnums, dnum)
+	s.ddens = append(s.ddens, dden)
+	s.errors = append(s.errors, err)
+	s.num += num
+	s.den += den
+	s.total += err
+
+	return len(s.lefts) - 1
+}
+
+// Bisects the panel with the largest error, reporting false if it is too
+// narrow to bisect.
+func (s *ratioPanels) bisect(pair func(float64) (float64, float64)) bool {
+	worst := s.order[0]
+	a, b := s.lefts[worst], s.rights[worst]
+	m := a + (b-a)/2
+	if m <= a || m >= b {
+		return false
+	}
+
+	// Remove the panel's contribution and reuse its slot for the left half
+	s.num -= s.nums[worst]
+	s.den -= s.dens[worst]
+	s.total -= s.errors[worst]
+	left := s.add(pair, a, m)
+	s.rights[worst], s.nums[worst], s.dens[worst] = m, s.nums[left], s.dens[left]
+	s.dnums[worst], s.ddens[worst], s.errors[worst] = s.dnums[left], s.ddens[left], s.errors[left]
+	s.lefts, s.rights = s.lefts[:left], s.rights[:left]
+	s.nums, s.dens = s.nums[:left], s.dens[:left]
+	s.dnums, s.ddens, s.errors = s.dnums[:left], s.ddens[:left], s.errors[:left]
+	heap.Fix(s, 0)
+
+	heap.Push(s, s.add(pair, m, b))
+	return true
+}
+
+// Recomputes the totals in bulk, updates the ratio and the panel errors
+// to match it, and rebuilds the heap, returning the new ratio.
+func (s *ratioPanels) refresh() float64 {
+	s.num, s.den = 0, 0
+	for i := range s.nums {
+		s.num += s.nums[i]
+		s.den += s.dens[i]
+	}
+	s.ratio = s.num / s.den
+	if s.den == 0 {
+		s.ratio = math.NaN()
+	}
+
+	s.total = 0
+	s.order = s.order[:0]
+	for i := range s.errors {
+		s.errors[i] = math.Abs(s.dnums[i] - s.ratio*s.ddens[i])
+		s.total += s.errors[i]
+		s.order = append(s.order, i)
+	}
+	heap.Init(s)
+
+	return s.ratio
+}
+
+// Applies the rule over [a, b] to both components of f at once, returning
+// for each the Kronrod estimate and its difference from the Gauss
+// estimate.
+func (r *kronrodRule) pair(f func(float64) (float64, float64), a, b float64) (k1, d1, k2, d2 float64) {
+	center := (a + b) / 2
+	half := (b - a) / 2
+	last := len(r.nodes) - 1
+
+	f1, f2 := f(center)
+	k1, k2 = f1*r.weights[last], f2*r.weights[last]
+	g1, g2 := f1*r.gauss[last], f2*r.gauss[last]
+	for i := 0; i < last; i++ {
+		dx := half * r.nodes[i]
+		l1, l2 := f(center - dx)
+		u1, u2 := f(center + dx)
+
+		k1 += r.weights[i] * (l1 + u1)
+		k2 += r.weights[i] * (l2 + u2)
+		g1 += r.gauss[i] * (l1 + u1)
+		g2 += r.gauss[i] * (l2 + u2)
+	}
+
+	return k1 * half, (k1 - g1) * half, k2 * half, (k2 - g2) * half
+}
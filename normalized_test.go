@@ -0,0 +1,53 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNormalizedExpectation(t *testing.T) {
+	const tol = 1e-10
+
+	inf := math.Inf(1)
+	shifted := func(x float64) float64 { return 1e8 * math.Exp(-(x-2)*(x-2)/2) }
+	cases := []struct {
+		g, w    Function
+		a, b    float64
+		correct float64
+	}{
+		{func(x float64) float64 { return x * x }, func(x float64) float64 { return math.Exp(-x * x / 2) }, -inf, inf, 1},
+		{func(x float64) float64 { return x }, shifted, -inf, inf, 2},
+		{func(x float64) float64 { return x }, math.Exp, 1, 0, 1 / (math.E - 1)},
+		{math.Sqrt, func(x float64) float64 { return math.Exp(-x) }, 0, inf, math.SqrtPi / 2},
+	}
+	for i, c := range cases {
+		r, err := NormalizedExpectation(c.g, c.w, c.a, c.b, tol)
+		if err != nil {
+			t.Errorf("case %d: %v", i, err)
+		}
+		if msg, ok := checkValue(r.Value, c.correct, 10*tol); !ok {
+			t.Errorf("case %d: %s", i, msg)
+		}
+	}
+
+	// A constant g needs no refinement, however awkward w is
+	spike := func(x float64) float64 { return 1 / (1e-6 + x*x) }
+	three := func(x float64) float64 { return 3 }
+	r, err := NormalizedExpectation(three, spike, -1, 1, tol)
+	if err != nil || r.Panels != 1 {
+		t.Errorf("constant g: %+v, %v", r, err)
+	}
+	if msg, ok := checkValue(r.Value, 3, 1e-14); !ok {
+		t.Error(msg)
+	}
+
+	zero := func(x float64) float64 { return 0 }
+	if r, _ := NormalizedExpectation(three, zero, 0, 1, tol); !math.IsNaN(r.Value) {
+		t.Errorf("zero weight gave %v", r.Value)
+	}
+	for _, h := range []float64{-1, math.NaN()} {
+		if r, err := NormalizedExpectation(three, spike, 0, 1, h); err != ErrInvalidInput || !math.IsNaN(r.Value) {
+			t.Errorf("tolerance %v gave %v, %v", h, r.Value, err)
+		}
+	}
+}
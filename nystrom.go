@@ -0,0 +1,304 @@
+package goint
+
+import (
+	"math"
+)
+
+// A Kernel is a function of two variables, K(x, t), defining an integral
+// operator f -> integral of K(x, t) f(t) dt.
+type Kernel func(x, t float64) float64
+
+// SolveFredholm2 solves the Fredholm equation of the second kind
+//
+//	f(x) = g(x) + lambda * integral over [a, b] of K(x, t) f(t) dt
+//
+// with the Nystrom method on n Gauss-Legendre nodes. The returned Function
+// is the Nystrom interpolant of the solution, which is as accurate between
+// the nodes as at them. ErrSingular is returned if lambda is (numerically)
+// a characteristic value of K.
+func SolveFredholm2(K Kernel, g Function, lambda, a, b float64, n int) (Function, error) {
+	ts, ws := legendreNodes(a, b, n)
+
+	A := newMatrix(n, n)
+	rhs := make([]float64, n)
+	for i, x := range ts {
+		for j, t := range ts {
+			A[i][j] = -lambda * ws[j] * K(x, t)
+		}
+		A[i][i] += 1
+		rhs[i] = g(x)
+	}
+
+	fs, err := solve(A, rhs)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(x float64) float64 {
+		ret := g(x)
+		for j, t := range ts {
+			ret += lambda * ws[j] * K(x, t) * fs[j]
+		}
+		return ret
+	}, nil
+}
+
+// Returns the n-point Gauss-Legendre rule mapped onto [a, b].
+func legendreNodes(a, b float64, n int) ([]float64, []float64) {
+	nodes, weights := gaussLegendre(n)
+	return mapRule(nodes, weights, a, b)
+}
+
+// A FirstKind is a quadrature discretization of the ill-posed Fredholm
+// equation of the first kind
+//
+//	integral over [a, b] of K(x, t) f(t) dt = g(x),
+//
+// which must be regularized to be solved. The discretization is
+// symmetrized with the square roots of the quadrature weights so that
+// vector norms approximate L2 norms on [a, b].
+type FirstKind struct {
+	nodes, sqrtw []float64
+	U, V         [][]float64
+	s            []float64
+}
+
+// NewFirstKind discretizes K on n Gauss-Legendre nodes in [a, b] and
+// computes the singular value decomposition used by the solvers. The
+// limits may be given in either order. nil is returned if n < 1.
+func NewFirstKind(K Kernel, a, b float64, n int) *FirstKind {
+	if n < 1 {
+		return nil
+	}
+	a, b = math.Min(a, b), math.Max(a, b)
+
+	ts, ws := legendreNodes(a, b, n)
+
+	sqrtw := make([]float64, n)
+	for i, w := range ws {
+		sqrtw[i] = math.Sqrt(w)
+	}
+
+	A := newMatrix(n, n)
+	for i, x := range ts {
+		for j, t := range ts {
+			A[i][j] = sqrtw[i] * K(x, t) * sqrtw[j]
+		}
+	}
+
+	U, s, V := svd(A)
+	return &FirstKind{ts, sqrtw, U, V, s}
+}
+
+// SingularValues returns the singular values of the discretized operator
+// in decreasing order. Their decay indicates how ill-posed the problem is.
+func (p *FirstKind) SingularValues() []float64 {
+	return append([]float64(nil), p.s...)
+}
+
+// Tikhonov returns the solution minimizing ||K f - g||^2 + alpha^2 ||f||^2,
+// interpolated between the quadrature nodes.
+func (p *FirstKind) Tikhonov(g Function, alpha float64) Function {
+	return p.solution(p.filter(g, func(i int) float64 {
+		s := p.s[i]
+		return s / (s*s + alpha*alpha)
+	}))
+}
+
+// TSVD returns the truncated singular value decomposition solution, which
+// keeps only the k largest singular values.
+func (p *FirstKind) TSVD(g Function, k int) Function {
+	return p.solution(p.filter(g, func(i int) float64 {
+		if i >= k || p.s[i] == 0 {
+			return 0
+		}
+		return 1 / p.s[i]
+	}))
+}
+
+// LCurve computes the residual norms ||K f - g|| and solution norms ||f||
+// of the Tikhonov solutions for each regularization parameter in alphas.
+// Plotted against each other on log-log axes these trace out the L-curve.
+func (p *FirstKind) LCurve(g Function, alphas []float64) (residuals, norms []float64) {
+	beta := p.project(g)
+
+	// The part of g outside the range of U is independent of alpha
+	outside := 0.0
+	for i, x := range p.nodes {
+		gi := p.sqrtw[i] * g(x)
+		outside += gi * gi
+	}
+	for _, b := range beta {
+		outside -= b * b
+	}
+	outside = math.Max(outside, 0)
+
+	residuals = make([]float64, len(alphas))
+	norms = make([]float64, len(alphas))
+	for k, alpha := range alphas {
+		res, norm := outside, 0.0
+		for i, s := range p.s {
+			a2 := alpha * alpha
+			r := a2 / (s*s + a2) * beta[i]
+			x := s / (s*s + a2) * beta[i]
+			res += r * r
+			norm += x * x
+		}
+		residuals[k] = math.Sqrt(res)
+		norms[k] = math.Sqrt(norm)
+	}
+
+	return residuals, norms
+}
+
+// LCurveCorner returns the parameter from alphas at the corner of the
+// L-curve, the point of maximum curvature in log-log coordinates, as a
+// choice of regularization parameter. alphas should be monotone and have
+// at least three entries; otherwise the first entry is returned, or NaN if
+// there is none.
+func (p *FirstKind) LCurveCorner(g Function, alphas []float64) float64 {
+	if len(alphas) == 0 {
+		return math.NaN()
+	}
+	if len(alphas) < 3 {
+		return alphas[0]
+	}
+
+	residuals, norms := p.LCurve(g, alphas)
+	xs := make([]float64, len(alphas))
+	ys := make([]float64, len(alphas))
+	for i := range alphas {
+		xs[i] = math.Log(residuals[i])
+		ys[i] = math.Log(norms[i])
+	}
+
+	best, kappa := 1, math.Inf(-1)
+	for i := 1; i < len(alphas)-1; i++ {
+		// Curvature of the circle through three consecutive points
+		ax, ay := xs[i]-xs[i-1], ys[i]-ys[i-1]
+		bx, by := xs[i+1]-xs[i], ys[i+1]-ys[i]
+		cx, cy := xs[i+1]-xs[i-1], ys[i+1]-ys[i-1]
+		cross := ax*by - ay*bx
+		denom := math.Hypot(ax, ay) * math.Hypot(bx, by) * math.Hypot(cx, cy)
+		if denom == 0 {
+			continue
+		}
+
+		// The corner of an L-curve traversed with decreasing alpha bends
+		// towards the origin
+		k := 2 * cross / denom
+		if alphas[0] > alphas[1] {
+			k = -k
+		}
+		if k > kappa {
+			best, kappa = i, k
+		}
+	}
+
+	return alphas[best]
+}
+
+// Returns the coefficients of the weighted right-hand side in the basis of
+// left singular vectors.
+func (p *FirstKind) project(g Function) []float64 {
+	n := len(p.nodes)
+	gs := make([]float64, n)
+	for i, x := range p.nodes {
+		gs[i] = p.sqrtw[i] * g(x)
+	}
+
+	beta := make([]float64, n)
+	for k := range beta {
+		for i := range gs {
+			beta[k] += p.U[i][k] * gs[i]
+		}
+	}
+
+	return beta
+}
+
+// Applies the spectral filter phi to g, returning the weighted solution
+// at the nodes.
+func (p *FirstKind) filter(g Function, phi func(i int) float64) []float64 {
+	beta := p.project(g)
+
+	n := len(p.nodes)
+	y := make([]float64, n)
+	for k := range beta {
+		c := phi(k) * beta[k]
+		for i := 0; i < n; i++ {
+			y[i] += c * p.V[i][k]
+		}
+	}
+
+	return y
+}
+
+// Removes the quadrature weighting from a solution at the nodes and
+// interpolates it.
+func (p *FirstKind) solution(y []float64) Function {
+	fs := make([]float64, len(y))
+	for i := range y {
+		fs[i] = y[i] / p.sqrtw[i]
+	}
+
+	return interpolant(p.nodes, fs)
+}
+
+// OperatorEigen computes the leading eigenpairs of the integral operator
+// with symmetric kernel K on [a, b], discretized on n Gauss-Legendre nodes.
+// Eigenvalues are returned in order of decreasing magnitude. Each
+// eigenfunction is returned as its Nystrom interpolant, normalized so that
+// the quadrature approximation of the integral of its square over [a, b]
+// is one. The limits may be given in either order, and nothing is
+// returned if n < 1.
+func OperatorEigen(K Kernel, a, b float64, n int) ([]float64, []Function) {
+	if n < 1 {
+		return nil, nil
+	}
+	a, b = math.Min(a, b), math.Max(a, b)
+
+	ts, ws := legendreNodes(a, b, n)
+
+	sqrtw := make([]float64, n)
+	for i, w := range ws {
+		sqrtw[i] = math.Sqrt(w)
+	}
+
+	A := newMatrix(n, n)
+	for i, x := range ts {
+		for j, t := range ts {
+			A[i][j] = sqrtw[i] * K(x, t) * sqrtw[j]
+		}
+	}
+
+	vals, vecs := symEigen(A)
+
+	funcs := make([]Function, n)
+	for k := range funcs {
+		phi := make([]float64, n)
+		for i := range phi {
+			phi[i] = vecs[i][k] / sqrtw[i]
+		}
+		funcs[k] = eigenfunction(K, ts, ws, vals[k], phi)
+	}
+
+	return vals, funcs
+}
+
+// Returns the Nystrom interpolant of the eigenfunction with values phi at
+// the nodes ts, falling back to polynomial interpolation for a zero
+// eigenvalue.
+func eigenfunction(K Kernel, ts, ws []float64, lambda float64, phi []float64) Function {
+	if lambda == 0 {
+		return interpolant(ts, phi)
+	}
+
+	return func(x float64) float64 {
+		ret := 0.0
+		for j, t := range ts {
+			ret += ws[j] * K(x, t) * phi[j]
+		}
+		return ret / lambda
+	}
+}
@@ -0,0 +1,130 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSolveFredholm2(t *testing.T) {
+	const (
+		h = 1e-12
+	)
+
+	// f(x) = x solves f(x) = 2x/3 + integral of x t f(t) dt over [0, 1]
+	K := func(x, t float64) float64 { return x * t }
+	g := func(x float64) float64 { return 2 * x / 3 }
+
+	f, err := SolveFredholm2(K, g, 1, 0, 1, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, x := range []float64{0, .3, .77, 1} {
+		if msg, ok := checkValue(f(x), x, h); !ok {
+			t.Error(msg)
+		}
+	}
+
+	// lambda = 3 is the characteristic value of this kernel
+	if _, err := SolveFredholm2(K, g, 3, 0, 1, 8); err != ErrSingular {
+		t.Errorf("expected ErrSingular, got %v", err)
+	}
+}
+
+func TestFirstKind(t *testing.T) {
+	// A smoothing Gaussian kernel applied to f(t) = sin(pi t)
+	K := func(x, t float64) float64 {
+		d := (x - t) / .1
+		return math.Exp(-d * d / 2)
+	}
+	f := func(t float64) float64 { return math.Sin(math.Pi * t) }
+	ts, ws := legendreNodes(0, 1, 60)
+	g := func(x float64) float64 {
+		ret := 0.0
+		for j, t := range ts {
+			ret += ws[j] * K(x, t) * f(t)
+		}
+		return ret
+	}
+
+	p := NewFirstKind(K, 0, 1, 30)
+
+	s := p.SingularValues()
+	if s[0] < s[len(s)-1] || s[len(s)-1] > 1e-6*s[0] {
+		t.Errorf("expected rapidly decaying singular values, got %g ... %g", s[0], s[len(s)-1])
+	}
+
+	alphas := []float64{1e-1, 1e-2, 1e-3, 1e-4, 1e-5, 1e-6, 1e-7, 1e-8, 1e-9}
+	residuals, norms := p.LCurve(g, alphas)
+	for i := 1; i < len(alphas); i++ {
+		if residuals[i] > residuals[i-1] || norms[i] < norms[i-1] {
+			t.Errorf("L-curve is not monotone at alpha = %g", alphas[i])
+		}
+	}
+
+	alpha := p.LCurveCorner(g, alphas)
+	for _, fa := range []Function{p.Tikhonov(g, alpha), p.TSVD(g, 12)} {
+		for _, x := range []float64{.25, .5, .75} {
+			if msg, ok := checkValue(fa(x), f(x), .05); !ok {
+				t.Error(msg)
+			}
+		}
+	}
+
+	if !math.IsNaN(p.LCurveCorner(g, nil)) {
+		t.Error("expected NaN without parameters")
+	}
+
+	// Reversed limits discretize the same interval
+	r := NewFirstKind(K, 1, 0, 30).SingularValues()
+	for i := range s {
+		if msg, ok := checkValue(r[i], s[i], 1e-12); !ok {
+			t.Errorf("reversed limits, singular value %d: %s", i, msg)
+		}
+	}
+	if NewFirstKind(K, 0, 1, 0) != nil {
+		t.Error("expected nil with no nodes")
+	}
+}
+
+func TestOperatorEigen(t *testing.T) {
+	const (
+		h = 1e-8
+	)
+
+	// The Brownian motion covariance min(x, t) on [0, 1] has eigenvalues
+	// 1 / ((k - 1/2)^2 pi^2) and eigenfunctions sqrt(2) sin((k - 1/2) pi x)
+	K := func(x, t float64) float64 { return math.Min(x, t) }
+	vals, funcs := OperatorEigen(K, 0, 1, 80)
+
+	for k := 0; k < 3; k++ {
+		w := (float64(k) + .5) * math.Pi
+		if msg, ok := checkValue(vals[k], 1/(w*w), 5e-3*vals[k]); !ok {
+			t.Errorf("eigenvalue %d: %s", k, msg)
+		}
+
+		phi := funcs[k]
+		sign := math.Copysign(1, phi(.1))
+		for _, x := range []float64{.1, .5, .9} {
+			if msg, ok := checkValue(sign*phi(x), math.Sqrt2*math.Sin(w*x), 1e-2); !ok {
+				t.Errorf("eigenfunction %d: %s", k, msg)
+			}
+		}
+	}
+
+	// A rank-one kernel has a single nonzero eigenvalue
+	vals, funcs = OperatorEigen(func(x, t float64) float64 { return x * t }, 0, 1, 6)
+	if msg, ok := checkValue(vals[0], 1./3, h); !ok {
+		t.Error(msg)
+	}
+	if msg, ok := checkValue(Integrate(func(x float64) float64 { return funcs[0](x) * funcs[0](x) }, 0, 1, h), 1, 1e-6); !ok {
+		t.Error(msg)
+	}
+
+	vals, _ = OperatorEigen(K, 1, 0, 80)
+	if msg, ok := checkValue(vals[0], 4/(math.Pi*math.Pi), 5e-3); !ok {
+		t.Errorf("reversed limits: %s", msg)
+	}
+	if vals, funcs = OperatorEigen(K, 0, 1, 0); vals != nil || funcs != nil {
+		t.Error("expected nothing with no nodes")
+	}
+}
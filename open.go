@@ -0,0 +1,28 @@
+package goint
+
+import (
+	"math"
+)
+
+// IntegrateOpen integrates f over the open interval (a, b) to within tol
+// as IntegrateGK does, guaranteeing that f is never evaluated at a or b,
+// for integrands undefined at a limit, such as log(x) or 1/sqrt(x) at
+// zero. The Gauss-Kronrod nodes are interior to every panel, but a panel
+// bisected down to a few units in the last place at a limit can round a
+// node onto it; such a node, and any endpoint requested by a closed rule
+// set with WithRule, is moved to the nearest float64 inside the interval.
+// WithMonotone is not supported and gives ErrInvalidOption, as its bounds
+// need the values at the limits.
+func IntegrateOpen(f Function, a, b, tol float64, opts ...Option) (Result, error) {
+	cfg := newConfig(opts)
+	if cfg.monotone {
+		return Result{Value: math.NaN()}, ErrInvalidOption
+	}
+	lo, hi := math.Min(a, b), math.Max(a, b)
+	inner, outer := math.Nextafter(lo, hi), math.Nextafter(hi, lo)
+
+	open := func(x float64) float64 {
+		return f(math.Max(inner, math.Min(x, outer)))
+	}
+	return integrateGK(open, a, b, tol, cfg, nil)
+}
@@ -0,0 +1,43 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestIntegrateOpen(t *testing.T) {
+	guarded := func(a, b float64, f Function) Function {
+		return func(x float64) float64 {
+			if !(x > a && x < b) {
+				t.Fatalf("evaluated at %g", x)
+			}
+			return f(x)
+		}
+	}
+
+	f := guarded(0, 1, func(x float64) float64 { return 1 / math.Sqrt(x) })
+	r, err := IntegrateOpen(f, 0, 1, 1e-10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg, ok := checkValue(r.Value, 2, 1e-10); !ok {
+		t.Error(msg)
+	}
+
+	// A closed rule asks for the limits, which are moved inside
+	r, _ = IntegrateOpen(guarded(0, 1, math.Log), 1, 0, 1e-6, WithRule(NewtonCotes(5)))
+	if msg, ok := checkValue(r.Value, 1, 1e-6); !ok {
+		t.Error(msg)
+	}
+
+	// So are nodes rounded onto a limit far from zero
+	g := guarded(1, 2, func(x float64) float64 { return math.Log(x - 1) })
+	r, _ = IntegrateOpen(g, 1, 2, 1e-14)
+	if msg, ok := checkValue(r.Value, -1, 1e-12); !ok {
+		t.Error(msg)
+	}
+
+	if _, err := IntegrateOpen(math.Log, 0, 1, 1e-6, WithMonotone()); err != ErrInvalidOption {
+		t.Errorf("got %v with WithMonotone", err)
+	}
+}
@@ -0,0 +1,205 @@
+package goint
+
+import (
+	"errors"
+)
+
+// ErrMemoryLimit is returned alongside the best available estimate when an
+// adaptive integrator runs out of its memory allowance before meeting its
+// tolerance.
+var ErrMemoryLimit = errors.New("goint: memory limit reached")
+
+// A MemoryPolicy determines what an adaptive integrator does when its
+// partition reaches the memory allowance set by WithMaxMemoryBytes. No
+// policy drops cached values: the integrators keep no cache of their
+// own, and a Store given to WithCache belongs to the caller, is not
+// counted against the allowance, and is the caller's to bound.
+type MemoryPolicy int
+
+const (
+	// MemoryFail stops refinement and returns ErrMemoryLimit.
+	MemoryFail MemoryPolicy = iota
+
+	// MemoryMerge merges the half of the panels with the smallest errors
+	// into a single fixed contribution, freeing their storage, and
+	// continues refining the rest. Merged panels are never refined again,
+	// so their error can never be reduced; once it would exceed half the
+	// tolerance, merging stops and ErrMemoryLimit is returned.
+	MemoryMerge
+)
+
+// ErrInvalidOption is returned when an Option's value is not supported by
+// the integrator it is passed to.
+var ErrInvalidOption = errors.New("goint: invalid option")
+
+// An Option configures an adaptive integrator.
+type Option func(*config)
+
+// The settings assembled from a list of Options.
+type config struct {
+	maxMemory    int64
+	memoryPolicy MemoryPolicy
+	kronrodOrder int
+	rule         Rule
+	errorMetric  ErrorFunctional
+	autoOrder    bool
+	workers      int
+	strict       bool
+	infiniteMap  InfiniteMap
+
+	precisionCheck bool
+	doubleDouble   bool
+	monotone       bool
+	breakpoints    []float64
+	portable       bool
+	evalOrder      EvaluationOrder
+	batch          *batcher
+	extrapolate    bool
+	log            RecordWriter
+	layer          float64
+	budget         int
+	rate           float64
+	cache          Store
+	order          int
+}
+
+// Applies opts to the default configuration.
+func newConfig(opts []Option) *config {
+	c := &config{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// WithMaxMemoryBytes bounds the memory used to store the adaptive
+// partition, so that unattended runs cannot exhaust the host. A
+// non-positive value means no bound beyond the integrator's own panel
+// limit.
+func WithMaxMemoryBytes(n int64) Option {
+	return func(c *config) { c.maxMemory = n }
+}
+
+// WithMemoryPolicy sets the action taken when the memory bound is reached.
+func WithMemoryPolicy(p MemoryPolicy) Option {
+	return func(c *config) { c.memoryPolicy = p }
+}
+
+// WithKronrodOrder selects the Gauss-Kronrod pair used on each panel by
+// its number of Kronrod points: 15, the default, 21, 31, 41, 51 or 61.
+// Higher orders cost more per panel but need far fewer panels on smooth
+// integrands, oscillatory ones in particular.
+func WithKronrodOrder(n int) Option {
+	return func(c *config) { c.kronrodOrder = n }
+}
+
+// WithAutoOrder chooses the rule for each panel from a cheap probe of the
+// integrand's smoothness there, the decay of its divided differences at
+// the nodes of the 15-point Gauss-Kronrod pair: that pair is kept near
+// kinks and other rough features, and the 61-point pair used where the
+// integrand is smooth but not yet resolved. The probe reuses the 15-point
+// evaluations, so oscillatory stretches are resolved in far fewer
+// evaluations while rough ones cost about the same. It overrides
+// WithKronrodOrder but not WithRule.
+func WithAutoOrder() Option {
+	return func(c *config) { c.autoOrder = true }
+}
+
+// WithRule replaces the Gauss-Kronrod pair used on each panel by an
+// adaptive integrator with r, which may be a FixedRule, whose error
+// estimate on each panel compares it on the whole panel with it on the
+// two halves, a pair from GaussKronrod, or any other implementation of
+// Rule. A nil rule is ignored.
+func WithRule(r Rule) Option {
+	return func(c *config) {
+		if fr, ok := r.(*FixedRule); ok && fr == nil {
+			return
+		}
+		c.rule = r
+	}
+}
+
+// An ErrorFunctional measures the error of a panel [a, b] whose estimate
+// value has the absolute error estimate err. It must be nonnegative.
+type ErrorFunctional func(a, b, value, err float64) float64
+
+// WithErrorFunctional replaces the absolute error estimate of each panel
+// by e, so that the adaptive partition is refined to minimize the error
+// the application cares about, for instance one weighted by |x| or
+// restricted to a region of interest. The tolerance then bounds the sum of
+// e over the panels, which is also what Result.Error reports. Panels are
+// given in the original coordinates even when a limit is infinite; a
+// panel reaching infinity has that as its bound.
+func WithErrorFunctional(e ErrorFunctional) Option {
+	return func(c *config) { c.errorMetric = e }
+}
+
+// WithWorkers sets the number of goroutines used by integrators that
+// evaluate the integrand in parallel. A non-positive value, the default,
+// means GOMAXPROCS.
+func WithWorkers(n int) Option {
+	return func(c *config) { c.workers = n }
+}
+
+// Deterministic guarantees bit-identical results from run to run and for
+// any number of workers. Panels are then selected and stored in an order
+// fixed by their errors and positions alone, evaluations made in
+// parallel are written to fixed slots, and every total is reduced by a
+// balanced pairwise tree over that order, so no sum depends on scheduling
+// or on the history of running totals. The integrators in this package
+// already schedule their work this way; the option pins the reductions as
+// well, at the cost of a bulk pass over the partition when a result is
+// returned.
+func Deterministic() Option {
+	return func(c *config) { c.strict = true }
+}
+
+// WithInfiniteMap selects the substitution used to map an infinite
+// interval onto a finite one. The default is RationalMap; matching the map
+// to the decay of the tails, algebraic or exponential, can save many
+// refinements.
+func WithInfiniteMap(m InfiniteMap) Option {
+	return func(c *config) { c.infiniteMap = m }
+}
+
+// Maps f over [a, b] onto a finite interval with the configured
+// substitution.
+func (c *config) compactify(f Function, a, b float64) (Function, float64, float64) {
+	if c.layered(a, b) {
+		return layerCompactify(f, a, b, c.layer)
+	}
+	return transformCompactify(c.infiniteMap, f, a, b, c.elementary())
+}
+
+// Returns the inverse of the configured substitution.
+func (c *config) uncompact(a, b float64) func(t float64) float64 {
+	if c.layered(a, b) {
+		return layerUncompact(a, b, c.layer)
+	}
+	return transformUncompact(c.infiniteMap, a, b, c.elementary())
+}
+
+// WithDoubleDouble carries out the node arithmetic and weighted sums of
+// the Gauss-Kronrod pairs, and the final sum over the panels, in
+// double-double arithmetic, about 32 digits, so that each panel's
+// estimate is rounded only once and the total accumulates no further
+// rounding. It costs a few times the float64 arithmetic but no extra
+// evaluations. It does not affect rules set by WithRule or WithAutoOrder.
+func WithDoubleDouble() Option {
+	return func(c *config) { c.doubleDouble = true }
+}
+
+// Returns the number of panels of the given size that fit in the memory
+// allowance, capped at limit. At least two are always allowed.
+func (c *config) panelLimit(bytes int, limit int) int {
+	if c.maxMemory <= 0 {
+		return limit
+	}
+	if n := c.maxMemory / int64(bytes); n < int64(limit) {
+		if n < 2 {
+			return 2
+		}
+		return int(n)
+	}
+	return limit
+}
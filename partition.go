@@ -0,0 +1,134 @@
+package goint
+
+import (
+	"math"
+	"sort"
+)
+
+// A Partition is the final adaptive partition of the integral of a
+// function over [a, b], holding the running integral at each breakpoint
+// so that the antiderivative can be evaluated anywhere in the interval.
+type Partition struct {
+	f          Function
+	points     []float64
+	cumulative []float64
+	errors     []float64
+	tol        float64
+	opts       []Option
+}
+
+// NewPartition integrates f over the finite interval [a, b], a <= b, to
+// within tol as IntegrateGK does, and returns the resulting partition.
+// After convergence adjacent panels are merged wherever re-estimating
+// their union keeps the total error within tol, so the partition carries
+// as few breakpoints as the tolerance allows rather than every panel the
+// refinement left behind. MemoryMerge is ignored, as merged panels would
+// leave gaps, and so is WithBoundaryLayer, as the panels must be in x. The
+// partition is returned with ErrNotConverged if the tolerance was not met,
+// and is nil with ErrInvalidInput if a bound is infinite or NaN, b < a, or
+// tol is NaN or negative.
+func NewPartition(f Function, a, b, tol float64, opts ...Option) (*Partition, error) {
+	if math.IsNaN(a) || math.IsNaN(b) || math.IsInf(a, 0) || math.IsInf(b, 0) || b < a || !(tol >= 0) {
+		return nil, ErrInvalidInput
+	}
+	cfg := newConfig(opts)
+	cfg.layer = 0
+	panel, err := cfg.panel(a, b)
+	if err != nil {
+		return nil, err
+	}
+	p := &Partition{f: f, points: []float64{a}, cumulative: []float64{0}, tol: tol, opts: opts}
+	if a == b {
+		return p, nil
+	}
+
+	done := func(value, total, worst float64) bool { return total <= tol }
+	s := adapt(f, cfg.initialPoints(a, b, a, b), panel, cfg.panelLimit(panelBytes, maxPanels), nil, done)
+	_, total := s.sum()
+	converged := total <= tol
+	s.compact(f, panel, tol-total)
+
+	order := make([]int, len(s.lefts))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool { return s.lefts[order[i]] < s.lefts[order[j]] })
+	sum := 0.0
+	for _, i := range order {
+		sum += s.values[i]
+		p.points = append(p.points, s.rights[i])
+		p.cumulative = append(p.cumulative, sum)
+		p.errors = append(p.errors, s.errors[i])
+	}
+
+	if !converged {
+		return p, ErrNotConverged
+	}
+	return p, nil
+}
+
+// Merges adjacent panels of s while re-estimating their union with rule
+// increases the total error by no more than slack in all.
+func (s *panelSet) compact(f Function, rule panelRule, slack float64) {
+	for merged := true; merged; {
+		merged = false
+
+		order := make([]int, len(s.lefts))
+		for i := range order {
+			order[i] = i
+		}
+		sort.Slice(order, func(i, j int) bool { return s.lefts[order[i]] < s.lefts[order[j]] })
+
+		var kept panelSet
+		for k := 0; k < len(order); k++ {
+			i := order[k]
+			if k+1 < len(order) {
+				j := order[k+1]
+				value, err := rule(f, s.lefts[i], s.rights[j])
+				if increase := err - s.errors[i] - s.errors[j]; increase <= slack {
+					slack -= math.Max(increase, 0)
+					kept.add(s.lefts[i], s.rights[j], value, err)
+					merged = true
+					k++
+					continue
+				}
+			}
+			kept.add(s.lefts[i], s.rights[i], s.values[i], s.errors[i])
+		}
+		s.lefts, s.rights = kept.lefts, kept.rights
+		s.values, s.errors = kept.values, kept.errors
+	}
+	s.order = s.order[:0]
+}
+
+// Breakpoints returns the points separating the panels of p, from a to b.
+func (p *Partition) Breakpoints() []float64 {
+	return append([]float64(nil), p.points...)
+}
+
+// Integral returns the estimated integral over [a, b] and its estimated
+// absolute error.
+func (p *Partition) Integral() (value, err float64) {
+	for _, e := range p.errors {
+		err += e
+	}
+	return p.cumulative[len(p.cumulative)-1], err
+}
+
+// Antiderivative returns the integral of f from a to x, found by
+// integrating from the breakpoint below x with IntegrateGK to within the
+// share of tol of the distance between them. It is NaN outside [a, b].
+func (p *Partition) Antiderivative(x float64) float64 {
+	if !(x >= p.points[0] && x <= p.points[len(p.points)-1]) {
+		return math.NaN()
+	}
+	i := sort.SearchFloat64s(p.points, x)
+	if p.points[i] == x {
+		return p.cumulative[i]
+	}
+	i--
+
+	width := p.points[len(p.points)-1] - p.points[0]
+	r, _ := IntegrateGK(p.f, p.points[i], x, p.tol*(x-p.points[i])/width, p.opts...)
+	return p.cumulative[i] + r.Value
+}
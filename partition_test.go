@@ -0,0 +1,43 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNewPartition(t *testing.T) {
+	const tol = 1e-8
+	f := func(x float64) float64 { return math.Sqrt(x) + math.Cos(20*x) }
+	F := func(x float64) float64 { return 2 * math.Pow(x, 1.5) / 3 }
+	G := func(x float64) float64 { return F(x) + math.Sin(20*x)/20 }
+
+	p, err := NewPartition(f, 0, 2, tol)
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, e := p.Integral()
+	if msg, ok := checkValue(value, G(2), tol); !ok {
+		t.Error(msg)
+	}
+	if e > tol {
+		t.Errorf("error %v exceeds %v", e, tol)
+	}
+	for _, x := range []float64{0, 1e-9, .3, 1, 1.7, 2} {
+		if msg, ok := checkValue(p.Antiderivative(x), G(x), 2*tol); !ok {
+			t.Errorf("x = %v: %s", x, msg)
+		}
+	}
+	if !math.IsNaN(p.Antiderivative(3)) {
+		t.Error("expected NaN outside the interval")
+	}
+
+	// Compaction leaves fewer breakpoints than the refinement produced
+	r, _ := IntegrateGK(f, 0, 2, tol)
+	if n := len(p.Breakpoints()) - 1; n >= r.Panels {
+		t.Errorf("%d panels after compaction, %d before", n, r.Panels)
+	}
+
+	if _, err := NewPartition(f, 0, math.Inf(1), tol); err != ErrInvalidInput {
+		t.Errorf("infinite bound gave %v", err)
+	}
+}
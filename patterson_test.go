@@ -0,0 +1,80 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPattersonSequence(t *testing.T) {
+	p := patterson()
+	if len(p.nodes) != 127 {
+		t.Fatalf("%d nodes", len(p.nodes))
+	}
+
+	// The 3-point rule is Gauss-Legendre and the 7-point rule its Kronrod
+	// extension
+	if msg, ok := checkValue(p.nodes[2], math.Sqrt(.6), 1e-15); !ok {
+		t.Error(msg)
+	}
+	if msg, ok := checkValue(p.weights[1][0], 8.0/9, 1e-15); !ok {
+		t.Error(msg)
+	}
+
+	for k, weights := range p.weights {
+		n := len(weights)
+		m := (n - 1) / 2
+		for i, w := range weights {
+			if !(w > 0) {
+				t.Errorf("level %d: weight %d is %g", k, i, w)
+			}
+		}
+
+		// Exact to degree 3m + 1 for the m nodes of the previous level,
+		// and one more by symmetry
+		degree := 3*m + 2
+		if k == 0 {
+			degree = 1
+		}
+		for d := 0; d <= degree; d += 2 {
+			sum := 0.0
+			for i, w := range weights {
+				sum += w * math.Pow(p.nodes[i], float64(d))
+			}
+			if msg, ok := checkValue(sum, 2/float64(d+1), 1e-13); !ok {
+				t.Errorf("level %d, degree %d: %s", k, d, msg)
+			}
+		}
+	}
+}
+
+func TestIntegratePatterson(t *testing.T) {
+	r, err := IntegratePatterson(math.Exp, 0, 1, 1e-13)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg, ok := checkValue(r.Value, math.E-1, 1e-14); !ok {
+		t.Error(msg)
+	}
+	if r.Evals != 15 {
+		t.Errorf("%d evaluations", r.Evals)
+	}
+
+	f := func(x float64) float64 { return 1 / (1 + 25*x*x) }
+	r, err = IntegratePatterson(f, 1, -1, 1e-9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg, ok := checkValue(r.Value, -2*math.Atan(5)/5, 1e-9); !ok {
+		t.Error(msg)
+	}
+	if r.Evals != 127 {
+		t.Errorf("%d evaluations", r.Evals)
+	}
+
+	if _, err := IntegratePatterson(math.Sqrt, 0, 1, 1e-14); err != ErrNotConverged {
+		t.Errorf("got error %v, expected %v", err, ErrNotConverged)
+	}
+	if r, err := IntegratePatterson(math.Exp, 0, math.NaN(), 1e-10); err != ErrInvalidInput || !math.IsNaN(r.Value) {
+		t.Errorf("got %g, %v for a NaN limit", r.Value, err)
+	}
+}
@@ -0,0 +1,61 @@
+package goint
+
+import (
+	"math"
+)
+
+// The bounds on the number of trapezoid nodes used by IntegratePeriodic.
+const (
+	periodicMinNodes = 8
+	periodicMaxNodes = 1 << 20
+)
+
+// IntegratePeriodic integrates f over [a, b] to within tol, where f is
+// periodic with period b - a, or a divisor of it, such as an integrand
+// for a Fourier coefficient or an average over a cycle. The trapezoid
+// rule on n equally spaced nodes then converges geometrically in n for
+// smooth f, and much faster than any adaptive rule. The number of nodes
+// is doubled from 8, each time evaluating f only at the new midpoints,
+// until two successive estimates agree to within tol; their difference
+// is the error estimate. Components of f at frequencies that are
+// multiples of n alias onto the mean, so f must be sampled finely enough
+// at 8 nodes per period to reveal its features. Infinite or NaN limits or
+// a NaN or negative tolerance give a NaN estimate and ErrInvalidInput; if
+// the tolerance is not met with 2^20 nodes, the best estimate is returned
+// with ErrNotConverged.
+func IntegratePeriodic(f Function, a, b, tol float64) (Result, error) {
+	if math.IsNaN(a) || math.IsNaN(b) || math.IsInf(a, 0) || math.IsInf(b, 0) || !(tol >= 0) {
+		return Result{Value: math.NaN()}, ErrInvalidInput
+	}
+	if a == b {
+		return Result{}, nil
+	}
+
+	// By periodicity f(b) = f(a), so the trapezoid rule is a plain sum
+	// over the nodes left of b
+	h := (b - a) / periodicMinNodes
+	sum := 0.0
+	for i := 0; i < periodicMinNodes; i++ {
+		sum += f(a + float64(i)*h)
+	}
+	r := Result{Value: h * sum, Error: math.Inf(1), Evals: periodicMinNodes}
+	for n := periodicMinNodes; n < periodicMaxNodes; n *= 2 {
+		for i := 0; i < n; i++ {
+			sum += f(a + (float64(i)+.5)*h)
+		}
+		h /= 2
+		r.Evals += n
+
+		value := h * sum
+		r.Error = math.Abs(value - r.Value)
+		r.Value = value
+		if r.Error <= tol {
+			return r, nil
+		}
+		if math.IsNaN(value) {
+			break
+		}
+	}
+
+	return r, ErrNotConverged
+}
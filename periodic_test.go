@@ -0,0 +1,53 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestIntegratePeriodic(t *testing.T) {
+	const tol = 1e-12
+
+	// The modified Bessel function I_0(1) is the mean of exp(cos x)
+	r, err := IntegratePeriodic(func(x float64) float64 { return math.Exp(math.Cos(x)) }, 0, 2*math.Pi, tol)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg, ok := checkValue(r.Value, 2*math.Pi*1.2660658777520083, 1e-13); !ok {
+		t.Error(msg)
+	}
+	if r.Evals > 64 {
+		t.Errorf("%d evaluations", r.Evals)
+	}
+	gk, _ := IntegrateGK(func(x float64) float64 { return math.Exp(math.Cos(x)) }, 0, 2*math.Pi, tol)
+	if r.Evals >= gk.Evals {
+		t.Errorf("%d evaluations, %d by IntegrateGK", r.Evals, gk.Evals)
+	}
+
+	// A shifted, reversed period of a Fourier coefficient integrand
+	f := func(x float64) float64 { return math.Pow(math.Sin(x), 2) * math.Cos(2*x) }
+	r, err = IntegratePeriodic(f, 3+math.Pi, 3, tol)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg, ok := checkValue(r.Value, math.Pi/4, 1e-13); !ok {
+		t.Error(msg)
+	}
+
+	// Nearly singular integrands converge slowly
+	g := func(x float64) float64 { return 1 / (1.0001 - math.Cos(x)) }
+	r, err = IntegratePeriodic(g, 0, 2*math.Pi, tol)
+	if err != nil {
+		t.Error(err)
+	}
+	if msg, ok := checkValue(r.Value, 2*math.Pi/math.Sqrt(1.0001*1.0001-1), 1e-8); !ok {
+		t.Error(msg)
+	}
+
+	if r, err := IntegratePeriodic(math.Sin, 0, math.Inf(1), tol); err != ErrInvalidInput || !math.IsNaN(r.Value) {
+		t.Errorf("got %g, %v on an infinite interval", r.Value, err)
+	}
+	if _, err := IntegratePeriodic(math.Exp, 0, 1, 0); err != ErrNotConverged {
+		t.Errorf("got error %v, expected %v", err, ErrNotConverged)
+	}
+}
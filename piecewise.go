@@ -0,0 +1,101 @@
+package goint
+
+import (
+	"math"
+	"sort"
+)
+
+// A Piecewise is a function defined by different Functions between
+// breakpoints, such as a tax schedule, a tariff or a spline. Its method
+// value Eval is a Function, but Integrate integrates each piece
+// separately over its own interval, so that no rule straddles a
+// breakpoint and no piece is ever evaluated outside its interval.
+//
+// A Function is only a func, so the integrators cannot tell that Eval
+// came from a Piecewise. IntegrateGK splits the interval at the
+// breakpoints only if they are also given with
+// WithBreakpoints(p.Breakpoints()...), and Integrate, IntegrateAuto and
+// the other integrators without options cannot be told of them at all.
+type Piecewise struct {
+	breaks []float64
+	pieces []Function
+}
+
+// NewPiecewise returns the function equal to pieces[0] below breaks[0],
+// pieces[i] on [breaks[i-1], breaks[i]) and the last piece from the last
+// breakpoint on. The breakpoints are copied. It is nil unless there is one
+// more piece than breakpoints, no piece is nil and the breakpoints are
+// finite and strictly increasing.
+func NewPiecewise(breaks []float64, pieces ...Function) *Piecewise {
+	if len(pieces) != len(breaks)+1 {
+		return nil
+	}
+	for _, f := range pieces {
+		if f == nil {
+			return nil
+		}
+	}
+	for i, x := range breaks {
+		if math.IsNaN(x) || math.IsInf(x, 0) || i > 0 && !(x > breaks[i-1]) {
+			return nil
+		}
+	}
+
+	return &Piecewise{
+		breaks: append([]float64(nil), breaks...),
+		pieces: append([]Function(nil), pieces...),
+	}
+}
+
+// Breakpoints returns a copy of the breakpoints.
+func (p *Piecewise) Breakpoints() []float64 {
+	return append([]float64(nil), p.breaks...)
+}
+
+// Eval evaluates the function at x.
+func (p *Piecewise) Eval(x float64) float64 {
+	i := sort.Search(len(p.breaks), func(i int) bool { return p.breaks[i] > x })
+	return p.pieces[i](x)
+}
+
+// Integrate integrates the function over [a, b] to within tol with
+// IntegrateGK and opts, one piece at a time. The tolerance is shared
+// between the pieces in proportion to their widths, or evenly if the
+// interval is infinite, and the Results are summed. If any piece fails,
+// the sum is returned with the error of the last to fail.
+func (p *Piecewise) Integrate(a, b, tol float64, opts ...Option) (Result, error) {
+	if math.IsNaN(a) || math.IsNaN(b) || !(tol >= 0) {
+		return Result{Value: math.NaN()}, ErrInvalidInput
+	}
+	if a == b {
+		return Result{}, nil
+	}
+	if a > b {
+		r, err := p.Integrate(b, a, tol, opts...)
+		r.Value = -r.Value
+		return r, err
+	}
+
+	lo := sort.Search(len(p.breaks), func(i int) bool { return p.breaks[i] > a })
+	hi := sort.Search(len(p.breaks), func(i int) bool { return p.breaks[i] >= b })
+	points := append(append([]float64{a}, p.breaks[lo:hi]...), b)
+
+	var r Result
+	var status error
+	for i := 1; i < len(points); i++ {
+		pieceTol := tol / float64(len(points)-1)
+		if !math.IsInf(b-a, 0) {
+			pieceTol = tol * (points[i] - points[i-1]) / (b - a)
+		}
+		piece, err := IntegrateGK(p.pieces[lo+i-1], points[i-1], points[i], pieceTol, opts...)
+		r.Value += piece.Value
+		r.Error += piece.Error
+		r.Evals += piece.Evals
+		r.Panels += piece.Panels
+		if err != nil {
+			status = err
+		}
+	}
+
+	return r, status
+}
@@ -0,0 +1,66 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPiecewise(t *testing.T) {
+	// A tax schedule: nothing up to 10, 20% to 40 and 40% above
+	tax := NewPiecewise([]float64{10, 40},
+		func(x float64) float64 { return 0 },
+		func(x float64) float64 { return .2 * (x - 10) },
+		func(x float64) float64 { return 6 + .4*(x-40) },
+	)
+	for _, c := range []struct{ x, y float64 }{{5, 0}, {10, 0}, {40, 6}, {50, 10}} {
+		if got := tax.Eval(c.x); got != c.y {
+			t.Errorf("tax at %g: got %g, want %g", c.x, got, c.y)
+		}
+	}
+
+	// Each piece is linear, so one panel per piece is exact
+	r, err := tax.Integrate(0, 60, 1e-9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg, ok := checkValue(r.Value, 90+200, 1e-13); !ok {
+		t.Error(msg)
+	}
+	if r.Panels != 3 {
+		t.Errorf("%d panels", r.Panels)
+	}
+	r, _ = tax.Integrate(60, 25, 1e-9)
+	if msg, ok := checkValue(r.Value, -(67.5 + 200), 1e-13); !ok {
+		t.Error(msg)
+	}
+
+	// Pieces are never evaluated outside their intervals
+	guarded := NewPiecewise([]float64{0},
+		func(x float64) float64 {
+			if x > 0 {
+				t.Errorf("left piece evaluated at %g", x)
+			}
+			return math.Exp(x)
+		},
+		func(x float64) float64 {
+			if x < 0 {
+				t.Errorf("right piece evaluated at %g", x)
+			}
+			return math.Exp(-x)
+		},
+	)
+	r, _ = guarded.Integrate(math.Inf(-1), math.Inf(1), 1e-10)
+	if msg, ok := checkValue(r.Value, 2, 1e-10); !ok {
+		t.Error(msg)
+	}
+
+	for _, bad := range []*Piecewise{
+		NewPiecewise([]float64{0}, math.Exp),
+		NewPiecewise([]float64{1, 0}, math.Exp, math.Exp, math.Exp),
+		NewPiecewise(nil, nil),
+	} {
+		if bad != nil {
+			t.Error("accepted an invalid function")
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestIntegratePole(t *testing.T) {
+	const tol = 1e-9
+
+	// A Lorentzian of width q, which has poles at p +- iq, on a smooth
+	// background
+	p, q := .3, 1e-3
+	f := func(x float64) float64 { return 1/((x-p)*(x-p)+q*q) + math.Cos(x) }
+	pole := complex(p, q)
+	residue := complex(0, -1/(2*q))
+
+	a, b := 0.0, 1.0
+	correct := (math.Atan((b-p)/q)-math.Atan((a-p)/q))/q + math.Sin(b) - math.Sin(a)
+	if msg, ok := checkValue(IntegratePole(f, a, b, pole, residue, tol), correct, 10*tol); !ok {
+		t.Error(msg)
+	}
+
+	// Reversed limits
+	if msg, ok := checkValue(IntegratePole(f, b, a, pole, residue, tol), -correct, 10*tol); !ok {
+		t.Error(msg)
+	}
+
+	if !math.IsNaN(IntegratePole(f, a, b, complex(p, 0), residue, tol)) {
+		t.Error("expected NaN for a pole on the interval")
+	}
+
+	// A real pole outside the interval is counted once
+	g := func(x float64) float64 { return 1/(x-2) + x }
+	if msg, ok := checkValue(IntegratePole(g, 0, 1, 2, 1, tol), math.Log(.5)+.5, 10*tol); !ok {
+		t.Error(msg)
+	}
+	if msg, ok := checkValue(IntegratePole(g, 3, 4, 2, 1, tol), math.Log(2)+3.5, 10*tol); !ok {
+		t.Error(msg)
+	}
+}
@@ -0,0 +1,272 @@
+package goint
+
+import (
+	"math"
+)
+
+// WithPortableMath makes the substitutions that map infinite intervals
+// onto finite ones, and the error scaling of the Gauss-Kronrod rule, use
+// portable implementations of exp, log, log1p, sinh, cosh, sin, cos, tan
+// and pow in place of the math package's, whose results can differ in the
+// last bit between architectures with their assembly kernels and their
+// fused multiply-adds. Each is evaluated in double-double arithmetic
+// without fusable operations and rounded once, so it is correctly rounded
+// in all but rare cases and, more to the point, gives the same bits on
+// every platform. Together with the fusion-free arithmetic of the rule
+// itself, results then match across amd64 and arm64, provided the
+// integrand does. It costs a few hundred floating-point operations per
+// elementary function, which matters only for the substitutions of
+// cheap integrands on infinite intervals. Only the tabulated 15-point
+// rule is covered: WithKronrodOrder, WithRule, WithAutoOrder and
+// WithDoubleDouble, which compute nodes or sums by other means, give
+// ErrInvalidOption alongside it.
+func WithPortableMath() Option {
+	return func(c *config) { c.portable = true }
+}
+
+// The elementary functions used by the substitutions and rules of the
+// adaptive integrators.
+type elementary struct {
+	exp, log, log1p, sinh, cosh, sin, cos, tan func(x float64) float64
+	pow                                        func(x, y float64) float64
+}
+
+// The math package's elementary functions.
+var nativeMath = &elementary{
+	exp: math.Exp, log: math.Log, log1p: math.Log1p,
+	sinh: math.Sinh, cosh: math.Cosh,
+	sin: math.Sin, cos: math.Cos, tan: math.Tan,
+	pow: math.Pow,
+}
+
+// The portable elementary functions of WithPortableMath.
+var portableMath = &elementary{
+	exp: portableExp, log: portableLog, log1p: portableLog1p,
+	sinh: portableSinh, cosh: portableCosh,
+	sin: portableSin, cos: portableCos, tan: portableTan,
+	pow: portablePow,
+}
+
+// Returns the elementary functions selected by the configuration.
+func (c *config) elementary() *elementary {
+	if c.portable {
+		return portableMath
+	}
+	return nativeMath
+}
+
+// ln 2 and pi/2 in double-double.
+var (
+	ddLn2    = DoubleDouble{0.6931471805599453, 2.3190468138462996e-17}
+	ddHalfPi = DoubleDouble{1.5707963267948966, 6.123233995736766e-17}
+)
+
+// The largest argument for which e^x is finite.
+const ddOverflow = 709.782712893384
+
+// Returns d / e.
+func (d DoubleDouble) div(e DoubleDouble) DoubleDouble {
+	q1 := d.Hi / e.Hi
+	r := d.Add(e.MulFloat(-q1))
+	q2 := r.Hi / e.Hi
+	r = r.Add(e.MulFloat(-q2))
+	q3 := r.Hi / e.Hi
+	return quickTwoSum(q1, q2).AddFloat(q3)
+}
+
+// Returns d divided by the integer n, exactly representable as a float64.
+func (d DoubleDouble) divInt(n int) DoubleDouble {
+	return d.div(DoubleDouble{Hi: float64(n)})
+}
+
+// Returns d 2^k, exactly unless the result is subnormal.
+func (d DoubleDouble) ldexp(k int) DoubleDouble {
+	return DoubleDouble{math.Ldexp(d.Hi, k), math.Ldexp(d.Lo, k)}
+}
+
+// Returns m and k with e^x = m 2^k, for |x| no larger than about 746.
+func ddExp(x DoubleDouble) (DoubleDouble, int) {
+	k := math.RoundToEven(x.Hi / ddLn2.Hi)
+	r := x.Add(ddLn2.MulFloat(-k)).ldexp(-9)
+
+	// u = e^r - 1 by its series, then squared back up as
+	// (1 + u)^2 - 1 = u (2 + u) nine times
+	u, term := r, r
+	for n := 2; n <= 12; n++ {
+		term = term.Mul(r).divInt(n)
+		u = u.Add(term)
+	}
+	for i := 0; i < 9; i++ {
+		u = u.Mul(u.AddFloat(2))
+	}
+
+	return u.AddFloat(1), int(k)
+}
+
+// Returns log x for a positive, finite x.
+func ddLog(x DoubleDouble) DoubleDouble {
+	m, e := math.Frexp(x.Hi)
+	mm := DoubleDouble{m, math.Ldexp(x.Lo, -e)}
+	if m < math.Sqrt2/2 {
+		mm, e = mm.ldexp(1), e-1
+	}
+
+	// log m = 2 atanh s with s = (m - 1) / (m + 1), |s| < 0.172
+	s := mm.AddFloat(-1).div(mm.AddFloat(1))
+	s2 := s.Mul(s)
+	sum, power := s, s
+	for k := 1; k <= 22; k++ {
+		power = power.Mul(s2)
+		sum = sum.Add(power.divInt(2*k + 1))
+	}
+
+	return ddLn2.MulFloat(float64(e)).Add(sum.MulFloat(2))
+}
+
+// Returns e^x, overflowing to +Inf and underflowing to zero as math.Exp.
+func ddExpFloat(x DoubleDouble) float64 {
+	switch {
+	case math.IsNaN(x.Hi):
+		return x.Hi
+	case x.Hi > ddOverflow:
+		return math.Inf(1)
+	case x.Hi < -746:
+		return 0
+	}
+	m, k := ddExp(x)
+	return math.Ldexp(m.Float64(), k)
+}
+
+func portableExp(x float64) float64 {
+	return ddExpFloat(DoubleDouble{Hi: x})
+}
+
+func portableLog(x float64) float64 {
+	if !(x > 0) || math.IsInf(x, 1) {
+		return math.Log(x)
+	}
+	return ddLog(DoubleDouble{Hi: x}).Float64()
+}
+
+func portableLog1p(x float64) float64 {
+	if !(x > -1) || math.IsInf(x, 1) {
+		return math.Log1p(x)
+	}
+	if x == 0 {
+		return x
+	}
+	s, err := twoSum(1, x)
+	return ddLog(DoubleDouble{s, err}).Float64()
+}
+
+func portablePow(x, y float64) float64 {
+	if !(x > 0) || math.IsInf(x, 1) || math.IsNaN(y) || math.IsInf(y, 0) || y == 0 || x == 1 {
+		return math.Pow(x, y)
+	}
+	return ddExpFloat(ddLog(DoubleDouble{Hi: x}).MulFloat(y))
+}
+
+// Returns sinh x, or cosh x when cosh is set, from (e^x -+ e^-x) / 2.
+func ddHyperbolic(x float64, cosh bool) float64 {
+	ax := math.Abs(x)
+	if ax > 40 || math.IsNaN(x) {
+		// e^-|x| is negligible, and e^|x| / 2 = e^(|x| - ln 2) delays the
+		// overflow
+		v := ddExpFloat(DoubleDouble{Hi: ax}.Add(DoubleDouble{-ddLn2.Hi, -ddLn2.Lo}))
+		if !cosh && x < 0 {
+			v = -v
+		}
+		return v
+	}
+
+	m, k := ddExp(DoubleDouble{Hi: x})
+	e := m.ldexp(k)
+	inv := DoubleDouble{Hi: 1}.div(e)
+	if cosh {
+		return e.Add(inv).MulFloat(.5).Float64()
+	}
+	return e.Add(DoubleDouble{-inv.Hi, -inv.Lo}).MulFloat(.5).Float64()
+}
+
+func portableSinh(x float64) float64 {
+	if x == 0 || math.IsInf(x, 0) {
+		return x
+	}
+	if math.Abs(x) < 1 {
+		// The difference of exponentials cancels; sum the series
+		xx := DoubleDouble{Hi: x}
+		x2 := xx.Mul(xx)
+		sum, term := xx, xx
+		for n := 2; n <= 28; n += 2 {
+			term = term.Mul(x2).divInt(n * (n + 1))
+			sum = sum.Add(term)
+		}
+		return sum.Float64()
+	}
+	return ddHyperbolic(x, false)
+}
+
+func portableCosh(x float64) float64 {
+	if math.IsInf(x, 0) {
+		return math.Inf(1)
+	}
+	return ddHyperbolic(x, true)
+}
+
+// Returns sin x and cos x, reducing x by multiples of pi/2 in
+// double-double. The reduction is accurate for arguments of moderate size,
+// up to about 1e6.
+func ddSincos(x float64) (sin, cos DoubleDouble) {
+	k := math.RoundToEven(x / ddHalfPi.Hi)
+	r := DoubleDouble{Hi: x}.Add(ddHalfPi.MulFloat(-k))
+
+	r2 := r.Mul(r)
+	s, c := r, DoubleDouble{Hi: 1}
+	sterm, cterm := r, DoubleDouble{Hi: 1}
+	for n := 2; n <= 28; n += 2 {
+		cterm = cterm.Mul(r2).divInt((n - 1) * n)
+		sterm = sterm.Mul(r2).divInt(n * (n + 1))
+		if n%4 == 2 {
+			c = c.Add(DoubleDouble{-cterm.Hi, -cterm.Lo})
+			s = s.Add(DoubleDouble{-sterm.Hi, -sterm.Lo})
+		} else {
+			c = c.Add(cterm)
+			s = s.Add(sterm)
+		}
+	}
+
+	neg := func(d DoubleDouble) DoubleDouble { return DoubleDouble{-d.Hi, -d.Lo} }
+	switch q := int(math.Mod(k, 4)+4) % 4; q {
+	case 1:
+		return c, neg(s)
+	case 2:
+		return neg(s), neg(c)
+	case 3:
+		return neg(c), s
+	}
+	return s, c
+}
+
+func portableSin(x float64) float64 {
+	if x == 0 || math.IsNaN(x) || math.IsInf(x, 0) {
+		return math.Sin(x)
+	}
+	s, _ := ddSincos(x)
+	return s.Float64()
+}
+
+func portableCos(x float64) float64 {
+	if math.IsNaN(x) || math.IsInf(x, 0) {
+		return math.Cos(x)
+	}
+	_, c := ddSincos(x)
+	return c.Float64()
+}
+
+func portableTan(x float64) float64 {
+	if x == 0 || math.IsNaN(x) || math.IsInf(x, 0) {
+		return math.Tan(x)
+	}
+	s, c := ddSincos(x)
+	return s.div(c).Float64()
+}
@@ -0,0 +1,164 @@
+package goint
+
+import (
+	"math"
+	"math/big"
+	"testing"
+)
+
+// The precision of the reference values.
+const refPrec = 256
+
+var refPi, _ = new(big.Float).SetPrec(refPrec).SetString(
+	"3.14159265358979323846264338327950288419716939937510582097494459")
+
+func newRef(x float64) *big.Float {
+	return new(big.Float).SetPrec(refPrec).SetFloat64(x)
+}
+
+// Returns e^x as exp(x / 2^20) squared twenty times.
+func refExp(x *big.Float) *big.Float {
+	if x.Sign() < 0 {
+		return new(big.Float).SetPrec(refPrec).Quo(newRef(1), refExp(new(big.Float).Neg(x)))
+	}
+	r := new(big.Float).SetPrec(refPrec).SetMantExp(x, -20)
+	sum, term := newRef(1), newRef(1)
+	for n := 1; n < 40; n++ {
+		term.Mul(term, r)
+		term.Quo(term, newRef(float64(n)))
+		sum.Add(sum, term)
+	}
+	for i := 0; i < 20; i++ {
+		sum.Mul(sum, sum)
+	}
+	return sum
+}
+
+// Returns log x by Newton's method on refExp.
+func refLog(x *big.Float) *big.Float {
+	xf, _ := x.Float64()
+	m, e := math.Frexp(xf)
+	y := newRef(math.Log(m) + float64(e)*math.Ln2)
+	for i := 0; i < 5; i++ {
+		ey := refExp(y)
+		num := new(big.Float).SetPrec(refPrec).Sub(x, ey)
+		den := new(big.Float).SetPrec(refPrec).Add(x, ey)
+		num.Quo(num, den)
+		y.Add(y, num.Mul(num, newRef(2)))
+	}
+	return y
+}
+
+// Returns sin x and cos x by their series after reduction modulo 2 pi.
+func refSincos(x *big.Float) (*big.Float, *big.Float) {
+	twoPi := new(big.Float).SetPrec(refPrec).Mul(refPi, newRef(2))
+	q, _ := new(big.Float).Quo(x, twoPi).Float64()
+	r := new(big.Float).SetPrec(refPrec).Mul(twoPi, newRef(math.Round(q)))
+	r.Sub(x, r)
+
+	sin, cos := newRef(0), newRef(0)
+	term := newRef(1)
+	for n := 0; n < 90; n++ {
+		if n > 0 {
+			term.Mul(term, r)
+			term.Quo(term, newRef(float64(n)))
+		}
+		t := new(big.Float).Set(term)
+		if n%4 >= 2 {
+			t.Neg(t)
+		}
+		if n%2 == 0 {
+			cos.Add(cos, t)
+		} else {
+			sin.Add(sin, t)
+		}
+	}
+	return sin, cos
+}
+
+func refFloat(x *big.Float) float64 {
+	f, _ := x.Float64()
+	return f
+}
+
+func TestPortableKernels(t *testing.T) {
+	cases := []struct {
+		name     string
+		portable func(float64) float64
+		ref      func(*big.Float) *big.Float
+		xs       []float64
+	}{
+		{"exp", portableExp, refExp, []float64{-700, -20, -1, -1e-10, 1e-300, .5, 1, 2, 10, 100, 709.7}},
+		{"log", portableLog, refLog, []float64{1e-310, 1e-200, .1, .5, .7071, 1 + 1e-15, 1.5, 2, 10, 1e300}},
+		{"log1p", portableLog1p, func(x *big.Float) *big.Float {
+			return refLog(new(big.Float).SetPrec(refPrec).Add(x, newRef(1)))
+		}, []float64{-.999, -.5, -1e-9, 1e-12, .25, 1, 1e10}},
+		{"sinh", portableSinh, func(x *big.Float) *big.Float {
+			d := new(big.Float).SetPrec(refPrec).Sub(refExp(x), refExp(new(big.Float).Neg(x)))
+			return d.Quo(d, newRef(2))
+		}, []float64{-50, -3, -.5, -1e-8, .1, .99, 1, 6.5, 30, 522, 710}},
+		{"cosh", portableCosh, func(x *big.Float) *big.Float {
+			d := new(big.Float).SetPrec(refPrec).Add(refExp(x), refExp(new(big.Float).Neg(x)))
+			return d.Quo(d, newRef(2))
+		}, []float64{-50, -1, 1e-8, .5, 1, 6.5, 41, 522, 710}},
+		{"sin", portableSin, func(x *big.Float) *big.Float { s, _ := refSincos(x); return s },
+			[]float64{-10, -math.Pi / 2, -1, 1e-9, .5, 1, math.Pi / 4, math.Pi, 3, 100, 1e5}},
+		{"cos", portableCos, func(x *big.Float) *big.Float { _, c := refSincos(x); return c },
+			[]float64{-10, -1, 1e-9, .5, 1, math.Pi / 2, 3, 100, 1e5}},
+		{"tan", portableTan, func(x *big.Float) *big.Float {
+			s, c := refSincos(x)
+			return s.Quo(s, c)
+		}, []float64{-1.5, -1, 1e-9, .5, .785, 1, 1.5707963, math.Pi / 2, 4}},
+	}
+	for _, c := range cases {
+		for _, x := range c.xs {
+			if got, want := c.portable(x), refFloat(c.ref(newRef(x))); got != want {
+				t.Errorf("%s(%v) = %v, want %v", c.name, x, got, want)
+			}
+		}
+	}
+
+	for _, c := range [][2]float64{{2, .5}, {1e-5, 1.5}, {7, -3.25}, {.3, 100}} {
+		l := refLog(newRef(c[0]))
+		want := refFloat(refExp(l.Mul(l, newRef(c[1]))))
+		if got := portablePow(c[0], c[1]); got != want {
+			t.Errorf("pow(%v, %v) = %v, want %v", c[0], c[1], got, want)
+		}
+	}
+
+	// Special values follow the math package
+	for _, x := range []float64{math.Inf(-1), -1, 0, math.Inf(1), math.NaN()} {
+		same := func(a, b float64) bool { return a == b || (math.IsNaN(a) && math.IsNaN(b)) }
+		if !same(portableLog(x), math.Log(x)) || !same(portableLog1p(x-1), math.Log1p(x-1)) ||
+			!same(portableExp(x), math.Exp(x)) || !same(portableSin(x), math.Sin(x)) {
+			t.Errorf("special value %v differs from the math package", x)
+		}
+	}
+}
+
+func TestWithPortableMath(t *testing.T) {
+	const tol = 1e-10
+	f := func(x float64) float64 { return math.Exp(-x * x) }
+	for _, m := range []InfiniteMap{RationalMap, DoubleExponentialMap, TangentMap, LogarithmicMap} {
+		r, err := IntegrateGK(f, math.Inf(-1), math.Inf(1), tol, WithInfiniteMap(m), WithPortableMath())
+		if err != nil {
+			t.Errorf("map %d: %v", m, err)
+		}
+		if msg, ok := checkValue(r.Value, math.Sqrt(math.Pi), tol); !ok {
+			t.Errorf("map %d: %s", m, msg)
+		}
+	}
+
+	// These bits are the same on every platform
+	r, _ := IntegrateGK(func(x float64) float64 { return 1 / (1 + x*x) }, 0, math.Inf(1), tol,
+		WithInfiniteMap(DoubleExponentialMap), WithPortableMath())
+	if got := math.Float64bits(r.Value); got != portableGolden {
+		t.Errorf("got bits %#x, want %#x", got, portableGolden)
+	}
+
+	if _, err := IntegrateGK(f, 0, 1, tol, WithPortableMath(), WithKronrodOrder(21)); err != ErrInvalidOption {
+		t.Errorf("expected ErrInvalidOption, got %v", err)
+	}
+}
+
+const portableGolden = 0x3ff921fb54442d18
@@ -0,0 +1,56 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestWithPrecisionCheck(t *testing.T) {
+	const tol = 1e-12
+
+	// Large cancelling contributions expose summation rounding
+	f := func(x float64) float64 { return 1e8 * math.Sin(50*x) }
+	for _, opts := range [][]Option{
+		{WithPrecisionCheck()},
+		{WithPrecisionCheck(), WithKronrodOrder(31)},
+		{WithPrecisionCheck(), WithAutoOrder()},
+		{WithPrecisionCheck(), WithRule(GaussLegendre(6))},
+	} {
+		r, err := IntegrateGK(f, 0, 2*math.Pi, 1e-4, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.Rounding != math.Abs(r.Value-r.Precise) {
+			t.Errorf("inconsistent result %+v", r)
+		}
+		if msg, ok := checkValue(r.Precise, 0, 1e-4); !ok {
+			t.Error(msg)
+		}
+	}
+
+	// The check agrees with the float64 value where rounding is negligible
+	r, err := IntegrateGK(math.Exp, 1, 0, tol, WithPrecisionCheck())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg, ok := checkValue(r.Precise, 1-math.E, tol); !ok || r.Rounding > 1e-15 {
+		t.Errorf("%s; rounding %v", msg, r.Rounding)
+	}
+
+	// WithOrder is checked with the rule it selects
+	g := func(x float64) float64 { return 1 / (1e-2 + x*x) }
+	for k := 1; k <= 3; k++ {
+		byOrder, err := IntegrateGK(g, -1, 1, 1e-6, WithOrder(k), WithPrecisionCheck())
+		if err != nil {
+			t.Fatal(err)
+		}
+		byRule, _ := IntegrateGK(g, -1, 1, 1e-6, WithRule(orderRule(k)), WithPrecisionCheck())
+		if byOrder.Rounding != byRule.Rounding || byOrder.Rounding > 1e-12 {
+			t.Errorf("order %d: rounding %v, with the rule %v", k, byOrder.Rounding, byRule.Rounding)
+		}
+	}
+
+	if r, _ := IntegrateGK(math.Exp, 0, 1, tol); r.Precise != 0 || r.Rounding != 0 {
+		t.Errorf("unrequested check gave %+v", r)
+	}
+}
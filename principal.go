@@ -0,0 +1,67 @@
+package goint
+
+import (
+	"math"
+)
+
+const (
+	// The degrees of the Chebyshev interpolants compared by
+	// PrincipalValue on the window about the pole.
+	principalCoarse = 32
+	principalFine   = 2 * principalCoarse
+
+	// The most times PrincipalValue halves the window about the pole.
+	principalMaxHalvings = 30
+)
+
+// PrincipalValue computes the Cauchy principal value of the integral of
+// f(x)/(x-c) over [a, b], where a < c < b, to within tol, in the manner of
+// QUADPACK's dqawc. On the largest interval [c-h, c+h] inside [a, b], f is
+// interpolated by a Chebyshev series and the principal value of each
+// term against the pole is added up from modified Chebyshev moments, which
+// satisfy a three-term recurrence; the rest of [a, b] is integrated
+// directly. Where interpolants of degree 32 and 64 disagree by more than
+// half of tol, f is not resolved on the window and it is halved. f need
+// only be smooth near c. The result is NaN unless a < c < b with finite a
+// and b.
+func PrincipalValue(f Function, a, b, c, tol float64) float64 {
+	if !(a < c && c < b) || math.IsInf(a, 0) || math.IsInf(b, 0) {
+		return math.NaN()
+	}
+
+	h := math.Min(c-a, b-c)
+	var window float64
+	for i := 0; ; i++ {
+		g := func(t float64) float64 { return f(c + h*t) }
+		coarse := chebPrincipal(NewChebyshev(g, -1, 1, principalCoarse).Coefficients())
+		window = chebPrincipal(NewChebyshev(g, -1, 1, principalFine).Coefficients())
+		if math.Abs(window-coarse) <= tol/2 || i == principalMaxHalvings {
+			break
+		}
+		h /= 2
+	}
+
+	g := func(x float64) float64 { return f(x) / (x - c) }
+	return Integrate(g, a, c-h, tol/4) + window + Integrate(g, c+h, b, tol/4)
+}
+
+// Returns the principal value of the integral of p(t)/t over [-1, 1],
+// where p has the given Chebyshev coefficients. The moments
+// m_k = p.v. int T_k(t)/t dt satisfy m_0 = 0, m_1 = 2 and
+// m_{k+1} = 2 int T_k dt - m_{k-1}, so that only odd k contribute.
+func chebPrincipal(coefs []float64) float64 {
+	sum := 0.0
+	prev, cur := 0.0, 2.0
+	for k := 1; k < len(coefs); k++ {
+		sum += coefs[k] * cur
+
+		// int T_k over [-1, 1] is 2 / (1 - k^2) for even k and 0 for odd
+		integral := 0.0
+		if k%2 == 0 {
+			integral = 2 / float64(1-k*k)
+		}
+		prev, cur = cur, 2*integral-prev
+	}
+
+	return sum
+}
@@ -0,0 +1,260 @@
+package goint
+
+import (
+	"math"
+	"sort"
+)
+
+// A Symmetry of an integrand about the center of its interval of
+// integration, which is zero for the whole real line.
+type Symmetry int
+
+const (
+	// NoSymmetry makes no claim about the integrand.
+	NoSymmetry Symmetry = iota
+
+	// EvenSymmetry declares that f(c - x) = f(c + x), so that the
+	// integral is twice that over the upper half.
+	EvenSymmetry
+
+	// OddSymmetry declares that f(c - x) = -f(c + x), so that the
+	// integral vanishes.
+	OddSymmetry
+)
+
+// A Method selects the integrator that solves a Problem.
+type Method int
+
+const (
+	// MethodAuto uses IntegrateGK, split at the singularities and
+	// accelerated by WithExtrapolation when there are any.
+	MethodAuto Method = iota
+
+	// MethodGK uses IntegrateGK with only the Problem's options.
+	MethodGK
+
+	// MethodTanhSinh uses TanhSinh on each piece between singularities,
+	// which suits singularities at the ends of the pieces.
+	MethodTanhSinh
+
+	// MethodPeriodic uses IntegratePeriodic, for integrands periodic
+	// over the interval.
+	MethodPeriodic
+
+	// MethodPatterson uses IntegratePatterson, for smooth integrands.
+	MethodPatterson
+)
+
+// A ProblemError describes why a Problem is inconsistent. It wraps
+// ErrInvalidInput.
+type ProblemError struct {
+	Reason string
+}
+
+func (e *ProblemError) Error() string {
+	return "goint: invalid problem: " + e.Reason
+}
+
+// Unwrap returns ErrInvalidInput.
+func (e *ProblemError) Unwrap() error {
+	return ErrInvalidInput
+}
+
+// A Problem describes an integral declaratively: the integrand and
+// interval, and what is known about them, from which Solve chooses how
+// to integrate. It is built by NewProblem and its methods, each of which
+// returns the Problem so that calls can be chained, and checked by
+// Validate before any evaluation of the integrand.
+type Problem struct {
+	f, weight      Function
+	a, b           float64
+	singularities  []float64
+	symmetry       Symmetry
+	absTol, relTol float64
+	method         Method
+	opts           []Option
+}
+
+// NewProblem returns the problem of integrating f over [a, b], either of
+// which may be infinite, with an absolute tolerance of 1e-10.
+func NewProblem(f Function, a, b float64) *Problem {
+	return &Problem{f: f, a: a, b: b, absTol: 1e-10}
+}
+
+// Weight multiplies the integrand by w.
+func (p *Problem) Weight(w Function) *Problem {
+	p.weight = w
+	return p
+}
+
+// Singularities declares points of the interval, including its ends, at
+// which the integrand is singular, discontinuous or has a kink.
+func (p *Problem) Singularities(xs ...float64) *Problem {
+	p.singularities = append(p.singularities, xs...)
+	return p
+}
+
+// Symmetric declares a symmetry of the integrand about the center of the
+// interval.
+func (p *Problem) Symmetric(s Symmetry) *Problem {
+	p.symmetry = s
+	return p
+}
+
+// Tolerance sets the tolerances: the integral is accepted once its error
+// estimate is within abs, or within rel times the magnitude of the
+// estimate. Either may be zero, but not both.
+func (p *Problem) Tolerance(abs, rel float64) *Problem {
+	p.absTol, p.relTol = abs, rel
+	return p
+}
+
+// Method selects the integrator, overriding the default MethodAuto.
+func (p *Problem) Method(m Method) *Problem {
+	p.method = m
+	return p
+}
+
+// Options adds options for the integrators that accept them.
+func (p *Problem) Options(opts ...Option) *Problem {
+	p.opts = append(p.opts, opts...)
+	return p
+}
+
+// Validate reports the first inconsistency in the problem as a
+// *ProblemError, or nil. It never evaluates the integrand.
+func (p *Problem) Validate() error {
+	lo, hi := math.Min(p.a, p.b), math.Max(p.a, p.b)
+	infinite := math.IsInf(lo, 0) || math.IsInf(hi, 0)
+	switch {
+	case p.f == nil:
+		return &ProblemError{"nil integrand"}
+	case math.IsNaN(p.a) || math.IsNaN(p.b):
+		return &ProblemError{"NaN limit"}
+	case !(p.absTol >= 0) || !(p.relTol >= 0):
+		return &ProblemError{"negative or NaN tolerance"}
+	case p.absTol == 0 && p.relTol == 0:
+		return &ProblemError{"zero tolerance"}
+	case p.symmetry < NoSymmetry || p.symmetry > OddSymmetry:
+		return &ProblemError{"unknown symmetry"}
+	case p.method < MethodAuto || p.method > MethodPatterson:
+		return &ProblemError{"unknown method"}
+	}
+
+	for _, x := range p.singularities {
+		if !(x >= lo && x <= hi) || math.IsInf(x, 0) {
+			return &ProblemError{"singularity outside the interval"}
+		}
+	}
+	if p.symmetry != NoSymmetry && infinite && !(math.IsInf(lo, -1) && math.IsInf(hi, 1)) {
+		return &ProblemError{"symmetry on a half-infinite interval"}
+	}
+	if p.symmetry == OddSymmetry && p.absTol == 0 {
+		return &ProblemError{"relative tolerance for an integral that vanishes by symmetry"}
+	}
+
+	if p.method != MethodAuto && p.method != MethodGK {
+		switch {
+		case infinite:
+			return &ProblemError{"method needs a finite interval"}
+		case p.relTol != 0:
+			return &ProblemError{"method has no relative tolerance"}
+		case p.method != MethodTanhSinh && len(p.singularities) > 0:
+			return &ProblemError{"method cannot handle singularities"}
+		}
+	}
+	return nil
+}
+
+// Solve validates the problem and integrates it with the selected method.
+// An invalid problem gives a NaN estimate and its *ProblemError. With
+// OddSymmetry the integral is zero and the integrand is never evaluated.
+func (p *Problem) Solve() (Result, error) {
+	if err := p.Validate(); err != nil {
+		return Result{Value: math.NaN()}, err
+	}
+	if p.a > p.b {
+		q := *p
+		q.a, q.b = p.b, p.a
+		r, err := q.Solve()
+		r.Value = -r.Value
+		return r, err
+	}
+	if p.a == p.b || p.symmetry == OddSymmetry {
+		return Result{}, nil
+	}
+
+	f := p.f
+	if w := p.weight; w != nil {
+		f = func(x float64) float64 { return p.f(x) * w(x) }
+	}
+	a, b, sings := p.a, p.b, p.singularities
+	if p.symmetry == EvenSymmetry {
+		center := 0.0
+		if !math.IsInf(a, 0) {
+			center = a + (b-a)/2
+		}
+		a, sings = center, make([]float64, len(p.singularities))
+		for i, x := range p.singularities {
+			sings[i] = center + math.Abs(x-center)
+		}
+	}
+
+	r, err := p.solve(f, a, b, sings)
+	if p.symmetry == EvenSymmetry {
+		r.Value, r.Error = 2*r.Value, 2*r.Error
+	}
+	return r, err
+}
+
+// Integrates f over [a, b] with the singularities sings, with half the
+// tolerance for even symmetry, as the estimate will be doubled.
+func (p *Problem) solve(f Function, a, b float64, sings []float64) (Result, error) {
+	tol := p.absTol
+	if p.symmetry == EvenSymmetry {
+		tol /= 2
+	}
+
+	switch p.method {
+	case MethodTanhSinh:
+		points := []float64{a}
+		sorted := append([]float64(nil), sings...)
+		sort.Float64s(sorted)
+		for _, x := range sorted {
+			if x > points[len(points)-1] && x < b {
+				points = append(points, x)
+			}
+		}
+		points = append(points, b)
+
+		var r Result
+		var status error
+		pieces := float64(len(points) - 1)
+		for i := 1; i < len(points); i++ {
+			piece, err := TanhSinh(f, points[i-1], points[i], tol/pieces)
+			r.Value += piece.Value
+			r.Error += piece.Error
+			r.Evals += piece.Evals
+			r.Panels += piece.Panels
+			if err != nil {
+				status = err
+			}
+		}
+		return r, status
+	case MethodPeriodic:
+		return IntegratePeriodic(f, a, b, tol)
+	case MethodPatterson:
+		return IntegratePatterson(f, a, b, tol)
+	}
+
+	opts := p.opts
+	if p.method == MethodAuto && len(sings) > 0 {
+		opts = append(append([]Option(nil), opts...), WithBreakpoints(sings...), WithExtrapolation())
+	}
+	var stop func(value, err float64) bool
+	if p.relTol > 0 {
+		rel := p.relTol
+		stop = func(value, err float64) bool { return err <= rel*math.Abs(value) }
+	}
+	return integrateGK(f, a, b, tol, newConfig(opts), stop)
+}
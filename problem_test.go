@@ -0,0 +1,71 @@
+package goint
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestProblemValidate(t *testing.T) {
+	evals := 0
+	f := func(x float64) float64 { evals++; return x }
+
+	cases := []*Problem{
+		NewProblem(nil, 0, 1),
+		NewProblem(f, math.NaN(), 1),
+		NewProblem(f, 0, 1).Tolerance(-1, 0),
+		NewProblem(f, 0, 1).Tolerance(0, 0),
+		NewProblem(f, 0, 1).Singularities(.5, 2),
+		NewProblem(f, 0, math.Inf(1)).Symmetric(EvenSymmetry),
+		NewProblem(f, -1, 1).Symmetric(OddSymmetry).Tolerance(0, 1e-8),
+		NewProblem(f, 0, math.Inf(1)).Method(MethodPatterson),
+		NewProblem(f, 0, 1).Method(MethodPeriodic).Tolerance(0, 1e-8),
+		NewProblem(f, 0, 1).Method(MethodPeriodic).Singularities(.5),
+		NewProblem(f, 0, 1).Method(Method(17)),
+	}
+	for i, p := range cases {
+		err := p.Validate()
+		var perr *ProblemError
+		if !errors.As(err, &perr) || !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("case %d: got error %v", i, err)
+		}
+		if r, err2 := p.Solve(); err2 == nil || err2.Error() != err.Error() || !math.IsNaN(r.Value) {
+			t.Errorf("case %d: solved to %g, %v", i, r.Value, err2)
+		}
+	}
+	if evals != 0 {
+		t.Errorf("%d evaluations during validation", evals)
+	}
+
+	if err := NewProblem(f, 1, 0).Singularities(0, 1).Validate(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestProblemSolve(t *testing.T) {
+	gauss := func(x float64) float64 { return math.Exp(-x * x) }
+	logAbs := func(x float64) float64 { return math.Log(math.Abs(x - .3)) }
+	cases := []struct {
+		p       *Problem
+		correct float64
+	}{
+		{NewProblem(gauss, math.Inf(-1), math.Inf(1)).Symmetric(EvenSymmetry), math.Sqrt(math.Pi)},
+		{NewProblem(math.Sin, -2, 2).Symmetric(OddSymmetry), 0},
+		{NewProblem(logAbs, 0, 1).Singularities(.3), .3*math.Log(.3) + .7*math.Log(.7) - 1},
+		{NewProblem(logAbs, 1, 0).Singularities(.3).Method(MethodTanhSinh), -(.3*math.Log(.3) + .7*math.Log(.7) - 1)},
+		{NewProblem(math.Cos, 0, 1).Weight(math.Exp), (math.E*(math.Cos(1)+math.Sin(1)) - 1) / 2},
+		{NewProblem(func(x float64) float64 { return math.Exp(math.Sin(x)) }, 0, 2*math.Pi).Method(MethodPeriodic), 2 * math.Pi * 1.2660658777520083},
+		{NewProblem(math.Exp, 0, 1).Method(MethodPatterson), math.E - 1},
+		{NewProblem(func(x float64) float64 { return 1e12 * math.Exp(x) }, 0, 1).Tolerance(0, 1e-12), 1e12 * (math.E - 1)},
+	}
+
+	for i, c := range cases {
+		r, err := c.p.Solve()
+		if err != nil {
+			t.Errorf("case %d: %v", i, err)
+		}
+		if msg, ok := checkValue(r.Value, c.correct, 1e-9*math.Max(1, math.Abs(c.correct))); !ok {
+			t.Errorf("case %d: %s", i, msg)
+		}
+	}
+}
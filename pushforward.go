@@ -0,0 +1,42 @@
+package goint
+
+import (
+	"math"
+)
+
+// PushforwardDensity returns the density of Y = g(X) when X has density
+// pdf and g is strictly monotone with inverse gInv, by the change of
+// variables formula pdf(gInv(y)) |gInv'(y)|. If gInvPrime is nil, the
+// Jacobian is instead found as 1/|g'(x)| by differentiating g numerically.
+// Where gInv(y) is NaN, outside the range of g, the density is zero.
+func PushforwardDensity(pdf, g, gInv, gInvPrime Function) Function {
+	return func(y float64) float64 {
+		x := gInv(y)
+		if math.IsNaN(x) {
+			return 0
+		}
+		if gInvPrime != nil {
+			return pdf(x) * math.Abs(gInvPrime(y))
+		}
+		return pdf(x) / math.Abs(Derivative(g, x))
+	}
+}
+
+// PushforwardProbability computes P(lo <= Y <= hi) for Y = g(X), where X
+// has density pdf and g is strictly monotone with inverse gInv, to within
+// err. The probability is integrated over the preimage in X, so no
+// Jacobian is needed.
+func PushforwardProbability(pdf, gInv Function, lo, hi, err float64) float64 {
+	a, b := gInv(lo), gInv(hi)
+	if a > b {
+		a, b = b, a
+	}
+	return Integrate(pdf, a, b, err)
+}
+
+// PushforwardExpectation computes the expectation of h(Y) for Y = g(X),
+// where X has density pdf supported on [a, b], to within err, by
+// integrating h(g(x)) pdf(x) over X. g need not be monotone.
+func PushforwardExpectation(pdf, g, h Function, a, b, err float64) float64 {
+	return Integrate(func(x float64) float64 { return h(g(x)) * pdf(x) }, a, b, err)
+}
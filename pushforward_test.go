@@ -0,0 +1,51 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPushforward(t *testing.T) {
+	const tol = 1e-9
+
+	// If X is standard normal, exp(X) is lognormal
+	normal := func(x float64) float64 { return math.Exp(-x*x/2) / math.Sqrt(2*math.Pi) }
+	logInv := func(y float64) float64 {
+		if y <= 0 {
+			return math.NaN()
+		}
+		return math.Log(y)
+	}
+	lognormal := func(y float64) float64 { return normal(math.Log(y)) / y }
+
+	exact := PushforwardDensity(normal, math.Exp, logInv, func(y float64) float64 { return 1 / y })
+	numeric := PushforwardDensity(normal, math.Exp, logInv, nil)
+	for _, y := range []float64{.1, 1, 2.5, 10} {
+		if msg, ok := checkValue(exact(y), lognormal(y), 1e-15); !ok {
+			t.Error(msg)
+		}
+		if msg, ok := checkValue(numeric(y), lognormal(y), 1e-10); !ok {
+			t.Error(msg)
+		}
+	}
+	if exact(-1) != 0 {
+		t.Error("expected zero density outside the range")
+	}
+
+	// P(1 <= exp(X) <= e) = P(0 <= X <= 1)
+	p := PushforwardProbability(normal, logInv, 1, math.E, tol)
+	if msg, ok := checkValue(p, math.Erf(1/math.Sqrt2)/2, 10*tol); !ok {
+		t.Error(msg)
+	}
+
+	// E[exp(X)] = exp(1/2), and E[X^2] = 1 through a non-monotone map
+	mean := PushforwardExpectation(normal, math.Exp, func(y float64) float64 { return y }, -12, 12, tol)
+	if msg, ok := checkValue(mean, math.Exp(.5), 10*tol); !ok {
+		t.Error(msg)
+	}
+	square := func(x float64) float64 { return x * x }
+	second := PushforwardExpectation(normal, square, func(y float64) float64 { return y }, -12, 12, tol)
+	if msg, ok := checkValue(second, 1, 10*tol); !ok {
+		t.Error(msg)
+	}
+}
@@ -0,0 +1,91 @@
+package goint
+
+import (
+	"math"
+	"math/rand"
+)
+
+// A MultiFunction is a function of several variables.
+type MultiFunction func(x []float64) float64
+
+const (
+	// The number of random shifts used to estimate the error of a
+	// quasi-Monte Carlo estimate.
+	qmcShifts = 16
+
+	// The largest number of lattice points per shift.
+	qmcMaxPoints = 1 << 20
+)
+
+// The first primes, whose square roots generate the Richtmyer sequence.
+var qmcPrimes = []float64{
+	2, 3, 5, 7, 11, 13, 17, 19, 23, 29, 31, 37, 41, 43, 47, 53, 59, 61, 67,
+	71, 73, 79, 83, 89, 97, 101, 103, 107, 109, 113, 127, 131, 137, 139, 149,
+	151, 157, 163, 167, 173, 179, 181, 191, 193, 197, 199, 211, 223, 227, 229,
+}
+
+// IntegrateQMC integrates f over the unit cube [0, 1]^dim with a randomly
+// shifted Richtmyer lattice, folded with the tent (baker's) transform so
+// that non-periodic integrands converge quickly, doubling the number of
+// points until the estimated error is below tol or the point budget is
+// exhausted. The estimate and its error, three standard errors over the
+// random shifts, are returned. The shifts are seeded deterministically, so
+// repeated calls give identical results. dim may be at most
+// len(qmcPrimes).
+func IntegrateQMC(f MultiFunction, dim int, tol float64) (float64, float64) {
+	if dim == 0 {
+		return f(nil), 0
+	}
+	if dim > len(qmcPrimes) {
+		return math.NaN(), math.Inf(1)
+	}
+
+	alpha := make([]float64, dim)
+	for i := range alpha {
+		alpha[i] = math.Sqrt(qmcPrimes[i])
+		alpha[i] -= math.Floor(alpha[i])
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	shifts := make([][]float64, qmcShifts)
+	for s := range shifts {
+		shifts[s] = make([]float64, dim)
+		for i := range shifts[s] {
+			shifts[s][i] = rng.Float64()
+		}
+	}
+
+	sums := make([]float64, qmcShifts)
+	x := make([]float64, dim)
+	var value, err float64
+	for n, next := 0, 1<<8; next <= qmcMaxPoints; next *= 2 {
+		for s, shift := range shifts {
+			for k := n + 1; k <= next; k++ {
+				for i := range x {
+					v := float64(k)*alpha[i] + shift[i]
+					x[i] = 1 - math.Abs(2*(v-math.Floor(v))-1)
+				}
+				sums[s] += f(x)
+			}
+		}
+		n = next
+
+		mean, sq := 0.0, 0.0
+		for _, sum := range sums {
+			mean += sum / float64(n)
+		}
+		mean /= qmcShifts
+		for _, sum := range sums {
+			d := sum/float64(n) - mean
+			sq += d * d
+		}
+
+		value = mean
+		err = 3 * math.Sqrt(sq/(qmcShifts*(qmcShifts-1)))
+		if err <= tol {
+			break
+		}
+	}
+
+	return value, err
+}
@@ -0,0 +1,93 @@
+// Package quadgold records the Results of integrations to golden files
+// and compares later runs against them, so that code embedding goint can
+// catch changes in its behaviour across upgrades. Run the tests with the
+// environment variable QUADGOLD_UPDATE=1 to record or refresh the golden
+// values.
+package quadgold
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"goint"
+)
+
+// The environment variable that switches Check to recording.
+const updateEnv = "QUADGOLD_UPDATE"
+
+// A Tolerance bounds how far a Result may drift from its golden value.
+type Tolerance struct {
+	// Abs and Rel widen the allowed difference in Value beyond the sum of
+	// the two error estimates, by Abs plus Rel times the golden value.
+	Abs, Rel float64
+
+	// Evals, if positive, is the largest factor by which the number of
+	// evaluations may grow or shrink; zero leaves it unchecked.
+	Evals float64
+}
+
+// A record is the golden copy of a Result.
+type record struct {
+	Value  float64 `json:"value"`
+	Error  float64 `json:"error"`
+	Evals  int     `json:"evals"`
+	Panels int     `json:"panels"`
+}
+
+// Check compares r against the golden result stored under name in the
+// JSON file at path, reporting a test error if r differs by more than
+// tol allows. The values are consistent if they differ by no more than
+// the sum of their error estimates, widened by tol. A missing file or
+// entry is an error, unless QUADGOLD_UPDATE=1, in which case r is
+// recorded in place of the golden result.
+func Check(t testing.TB, path, name string, r goint.Result, tol Tolerance) {
+	t.Helper()
+
+	golden := make(map[string]record)
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(data, &golden); err != nil {
+			t.Fatalf("quadgold: %s: %v", path, err)
+			return
+		}
+	case !os.IsNotExist(err) || os.Getenv(updateEnv) != "1":
+		t.Fatalf("quadgold: %v", err)
+		return
+	}
+
+	if os.Getenv(updateEnv) == "1" {
+		golden[name] = record{r.Value, r.Error, r.Evals, r.Panels}
+		data, err := json.MarshalIndent(golden, "", "\t")
+		if err == nil {
+			err = os.MkdirAll(filepath.Dir(path), 0755)
+		}
+		if err == nil {
+			err = os.WriteFile(path, append(data, '\n'), 0644)
+		}
+		if err != nil {
+			t.Fatalf("quadgold: %v", err)
+		}
+		return
+	}
+
+	want, ok := golden[name]
+	if !ok {
+		t.Errorf("quadgold: %s: no golden result for %q", path, name)
+		return
+	}
+	allowed := want.Error + r.Error + tol.Abs + tol.Rel*math.Abs(want.Value)
+	if diff := math.Abs(r.Value - want.Value); !(diff <= allowed) {
+		t.Errorf("quadgold: %s: value %v differs from golden %v by %v, more than %v",
+			name, r.Value, want.Value, diff, allowed)
+	}
+	if tol.Evals > 0 {
+		ratio := float64(r.Evals) / float64(want.Evals)
+		if !(ratio <= tol.Evals && ratio >= 1/tol.Evals) {
+			t.Errorf("quadgold: %s: %d evaluations against golden %d", name, r.Evals, want.Evals)
+		}
+	}
+}
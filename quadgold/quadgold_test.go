@@ -0,0 +1,65 @@
+package quadgold
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"testing"
+
+	"goint"
+)
+
+// A recorder captures the failures reported to it.
+type recorder struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Fatalf(format string, args ...interface{}) {
+	r.Errorf(format, args...)
+}
+
+func TestCheck(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "golden", "exp.json")
+	r, _ := goint.IntegrateGK(math.Exp, 0, 1, 1e-10)
+
+	rec := &recorder{TB: t}
+	Check(rec, path, "exp", r, Tolerance{})
+	if len(rec.failures) != 1 {
+		t.Errorf("expected a missing file to fail, got %v", rec.failures)
+	}
+
+	t.Setenv(updateEnv, "1")
+	Check(t, path, "exp", r, Tolerance{})
+	t.Setenv(updateEnv, "")
+
+	// The same result, and one within its error, pass
+	Check(t, path, "exp", r, Tolerance{Evals: 1})
+	near := r
+	near.Value += r.Error / 2
+	Check(t, path, "exp", near, Tolerance{})
+
+	// Drift beyond the error estimates, or in the cost, fails
+	rec = &recorder{TB: t}
+	far := r
+	far.Value += 1e-3
+	Check(rec, path, "exp", far, Tolerance{})
+	far = r
+	far.Evals *= 3
+	Check(rec, path, "exp", far, Tolerance{Evals: 2})
+	Check(rec, path, "missing", r, Tolerance{})
+	if len(rec.failures) != 3 {
+		t.Errorf("expected three failures, got %v", rec.failures)
+	}
+
+	// Widening the tolerance admits the drift
+	far = r
+	far.Value += 1e-3
+	Check(t, path, "exp", far, Tolerance{Rel: 1e-3})
+}
@@ -0,0 +1,193 @@
+package goint
+
+import (
+	"math"
+)
+
+const (
+	// The smallest and largest number of geometrically growing shells
+	// summed by tailIntegral.
+	minShells = 4
+	maxShells = 64
+
+	// A tail integral is declared divergent once divergentShells
+	// consecutive shell contributions each exceed divergentRatio times the
+	// previous one; tails decaying more slowly than about x^(-1.15) are
+	// treated as divergent.
+	divergentShells = 8
+	divergentRatio  = .9
+
+	// Each shell starts from a partition into shellPanels panels, so that
+	// mass narrower than about a thousandth of the shell's width is still
+	// sampled.
+	shellPanels = 64
+
+	// Tolerances are raised to at least minTailTol, below which the error
+	// estimates of IntegrateGK cannot fall for integrals of order one.
+	minTailTol = 1e-13
+)
+
+// Integrates f over [a, b], a < b, to within tol with IntegrateGK,
+// starting from a partition into panels no wider than h, so that features
+// about as narrow as h are not stepped over. As with Integrate, the best
+// estimate is returned even if the tolerance is not met.
+func integrateFine(f Function, a, b, h, tol float64) float64 {
+	n := int(math.Min(math.Ceil((b-a)/h), shellPanels))
+	points := make([]float64, 0, n)
+	for i := 1; i < n; i++ {
+		points = append(points, a+(b-a)*float64(i)/float64(n))
+	}
+	r, _ := IntegrateGK(f, a, b, math.Max(tol, minTailTol), WithBreakpoints(points...))
+	return r.Value
+}
+
+// Computes the integral of f over [x, +Inf) if dir > 0, or over
+// (-Inf, x] if dir < 0, to within tol. The tail is summed over shells of
+// geometrically growing width, which guards against heavy tails: if the
+// shell contributions stop decreasing the integral is declared divergent
+// and an infinity of the appropriate sign is returned, rather than
+// refining forever. Shell tolerances shrink geometrically but are never
+// tightened below a small fraction of tol, and tol is raised to at least
+// minTailTol. NaN is returned if tol is negative or NaN.
+func tailIntegral(f Function, x float64, dir int, tol float64) float64 {
+	if !(tol >= 0) {
+		return math.NaN()
+	}
+	tol = math.Max(tol, minTailTol)
+
+	width := math.Max(1, math.Abs(x))
+	step := 1.0
+	if dir < 0 {
+		step = -1
+	}
+
+	ret := 0.0
+	prev := math.Inf(1)
+	growing := 0
+	L := x
+	for k := 0; k < maxShells; k++ {
+		R := L + step*width
+		shellTol := math.Max(tol/math.Exp2(float64(k+2)), tol*1e-4)
+		c := integrateFine(f, math.Min(L, R), math.Max(L, R), width/shellPanels, shellTol)
+		ret += c
+
+		if math.Abs(c) >= divergentRatio*prev && math.Abs(c) > tol {
+			growing++
+			if growing >= divergentShells {
+				return math.Copysign(math.Inf(1), ret)
+			}
+		} else {
+			growing = 0
+		}
+
+		if k+1 >= minShells && math.Abs(c) <= tol/4 && prev <= tol/2 {
+			return ret
+		}
+
+		prev = math.Abs(c)
+		L = R
+		width *= 2
+	}
+
+	// The tail did not settle within any representable distance
+	return math.Copysign(math.Inf(1), ret)
+}
+
+// CDF computes the cumulative distribution function of the density pdf at
+// x, the integral of pdf over (-Inf, x], to within tol. NaN is returned if
+// tol is negative or NaN.
+func CDF(pdf Function, x, tol float64) float64 {
+	return tailIntegral(pdf, x, -1, tol)
+}
+
+// Quantile returns the point x at which the cumulative distribution
+// function of the density pdf equals p, to within tol in probability.
+// The mass of pdf is first located by integrating it over shells of
+// doubling width on both sides of the origin, each split into 64 panels
+// before it is refined, until the shells hold all but tol of the unit
+// mass; mass narrower than about a thousandth of its distance from the
+// origin can still be missed. The root is then refined within its shell
+// with the Illinois variant of regula falsi, integrating pdf only between
+// successive iterates. +Inf is returned if the shells never accumulate
+// p, and NaN unless 0 < p < 1 and tol is nonnegative.
+func Quantile(pdf Function, p, tol float64) float64 {
+	if !(p > 0 && p < 1) || !(tol >= 0) {
+		return math.NaN()
+	}
+	tol = math.Max(tol, minTailTol)
+
+	// The shells are kept in order from the leftmost to the rightmost
+	type shell struct{ lo, hi, mass float64 }
+	var left, right []shell
+	total := 0.0
+	lo, hi := 0.0, 0.0
+	width := 1.0
+	for k := 0; k < maxShells && 1-total > tol/2; k++ {
+		shellTol := math.Max(tol/math.Exp2(float64(k+4)), tol*1e-4)
+		l := shell{lo - width, lo, integrateFine(pdf, lo-width, lo, width/shellPanels, shellTol)}
+		r := shell{hi, hi + width, integrateFine(pdf, hi, hi+width, width/shellPanels, shellTol)}
+		left = append([]shell{l}, left...)
+		right = append(right, r)
+		total += l.mass + r.mass
+		lo, hi = lo-width, hi+width
+		width *= 2
+	}
+
+	// F(x) = F(from) + integral of pdf over [from, x] within the shell
+	var bracket shell
+	Flo := 0.0
+	for i, s := range append(left, right...) {
+		if Flo+s.mass >= p {
+			bracket = s
+			break
+		}
+		if i == len(left)+len(right)-1 {
+			return math.Inf(1)
+		}
+		Flo += s.mass
+	}
+	h := (bracket.hi - bracket.lo) / shellPanels
+	F := func(from, Ffrom, to float64) float64 {
+		if to >= from {
+			return Ffrom + integrateFine(pdf, from, to, h, tol/4)
+		}
+		return Ffrom - integrateFine(pdf, to, from, h, tol/4)
+	}
+	lo, hi = bracket.lo, bracket.hi
+	Fhi := Flo + bracket.mass
+
+	// Illinois weights: the function values used for interpolation are
+	// damped on the side that is retained twice in a row
+	wlo, whi := Flo-p, Fhi-p
+	side := 0
+	for iter := 0; iter < 200; iter++ {
+		x := lo + (hi-lo)/2
+		if whi > wlo {
+			x = lo - wlo*(hi-lo)/(whi-wlo)
+		}
+		if !(x > lo && x < hi) {
+			x = lo + (hi-lo)/2
+		}
+		Fx := F(lo, Flo, x)
+
+		if math.Abs(Fx-p) <= tol || hi-lo <= 1e-15*(1+math.Abs(x)) {
+			return x
+		}
+
+		if Fx < p {
+			lo, Flo, wlo = x, Fx, Fx-p
+			if side < 0 {
+				whi /= 2
+			}
+			side = -1
+		} else {
+			hi, Fhi, whi = x, Fx, Fx-p
+			if side > 0 {
+				wlo /= 2
+			}
+			side = 1
+		}
+	}
+
+	return lo + (hi-lo)/2
+}
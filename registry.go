@@ -0,0 +1,86 @@
+package goint
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// ErrDuplicateRule is returned by Register when the name is already taken.
+var ErrDuplicateRule = errors.New("goint: rule already registered")
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Rule{}
+)
+
+// The rules registered under their names from the start, built on first
+// lookup.
+var builtinRules = map[string]func() Rule{
+	"trapezoid": func() Rule { return NewtonCotes(2) },
+	"simpson":   func() Rule { return NewtonCotes(3) },
+	"boole":     func() Rule { return NewtonCotes(5) },
+	"milne":     func() Rule { return Milne() },
+	"weddle":    func() Rule { return Weddle() },
+}
+
+func init() {
+	for points := range kronrodOrders {
+		points := points
+		builtinRules["gk"+strconv.Itoa(points)] = func() Rule { return GaussKronrod(points) }
+	}
+}
+
+// Register makes r available to Lookup under name, so that applications
+// can select rules by strings from configuration files and comparison
+// harnesses can enumerate them. The Gauss-Kronrod pairs are registered
+// as "gk15", "gk21", ..., "gk61", the closed Newton-Cotes rules of 2,
+// 3 and 5 points as "trapezoid", "simpson" and "boole", and Milne's and
+// Weddle's rules as "milne" and "weddle". An empty name or a nil rule
+// gives ErrInvalidInput, and a name already registered ErrDuplicateRule.
+// It is safe for concurrent use.
+func Register(name string, r Rule) error {
+	if fr, ok := r.(*FixedRule); name == "" || r == nil || (ok && fr == nil) {
+		return ErrInvalidInput
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, ok := registry[name]; ok {
+		return ErrDuplicateRule
+	}
+	if _, ok := builtinRules[name]; ok {
+		return ErrDuplicateRule
+	}
+	registry[name] = r
+	return nil
+}
+
+// Lookup returns the rule registered under name, and whether there is one.
+func Lookup(name string) (Rule, bool) {
+	if build, ok := builtinRules[name]; ok {
+		return build(), true
+	}
+
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	r, ok := registry[name]
+	return r, ok
+}
+
+// RuleNames returns the names of the registered rules in increasing order.
+func RuleNames() []string {
+	registryMu.RLock()
+	names := make([]string, 0, len(registry)+len(builtinRules))
+	for name := range registry {
+		names = append(names, name)
+	}
+	registryMu.RUnlock()
+	for name := range builtinRules {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,56 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	for _, name := range []string{"gk15", "gk61", "simpson"} {
+		r, ok := Lookup(name)
+		if !ok || r == nil {
+			t.Fatalf("%s: not found", name)
+		}
+		got, err := IntegrateGK(math.Exp, 0, 1, 1e-12, WithRule(r))
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+		if msg, ok := checkValue(got.Value, math.E-1, 1e-11); !ok {
+			t.Errorf("%s: %s", name, msg)
+		}
+	}
+	if r, _ := Lookup("gk21"); r != GaussKronrod(21) {
+		t.Error("gk21 is not the 21-point pair")
+	}
+
+	if err := Register("test-simpson-pair", simpsonPair{}); err != nil {
+		t.Fatal(err)
+	}
+	if r, ok := Lookup("test-simpson-pair"); !ok || r != (simpsonPair{}) {
+		t.Errorf("got %v, %v", r, ok)
+	}
+	if err := Register("test-simpson-pair", simpsonPair{}); err != ErrDuplicateRule {
+		t.Errorf("got error %v, expected %v", err, ErrDuplicateRule)
+	}
+	if err := Register("gk15", simpsonPair{}); err != ErrDuplicateRule {
+		t.Errorf("got error %v, expected %v", err, ErrDuplicateRule)
+	}
+	if err := Register("nil", NewtonCotes(100)); err != ErrInvalidInput {
+		t.Errorf("got error %v, expected %v", err, ErrInvalidInput)
+	}
+	if _, ok := Lookup("missing"); ok {
+		t.Error("found a missing rule")
+	}
+
+	names := RuleNames()
+	found := false
+	for i, name := range names {
+		found = found || name == "test-simpson-pair"
+		if i > 0 && names[i-1] >= name {
+			t.Errorf("names out of order: %q", names)
+		}
+	}
+	if !found || len(names) < 9 {
+		t.Errorf("names %q", names)
+	}
+}
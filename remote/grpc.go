@@ -0,0 +1,464 @@
+package remote
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"goint"
+)
+
+// The path of the Evaluate method of the Integrand service.
+const grpcMethod = "/goint.remote.Integrand/Evaluate"
+
+// The largest gRPC message accepted: a full batch, its field tag and its
+// length.
+const maxMessage = 8*MaxBatch + 16
+
+// The gRPC status codes used by this package.
+const (
+	statusOK                = 0
+	statusCanceled          = 1
+	statusUnknown           = 2
+	statusInvalidArgument   = 3
+	statusDeadlineExceeded  = 4
+	statusResourceExhausted = 8
+	statusUnimplemented     = 12
+)
+
+// A StatusError reports a gRPC call that ended with a status other than
+// OK. Calls ended for exceeding their deadline or for being canceled
+// match context.DeadlineExceeded and context.Canceled under errors.Is.
+type StatusError struct {
+	Code    int
+	Message string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("remote: gRPC status %d: %s", e.Code, e.Message)
+}
+
+func (e *StatusError) Is(target error) bool {
+	switch e.Code {
+	case statusDeadlineExceeded:
+		return target == context.DeadlineExceeded
+	case statusCanceled:
+		return target == context.Canceled
+	}
+	return false
+}
+
+// Carries gRPC calls over HTTP/2 without TLS, using prior knowledge of the
+// server's support for it.
+var h2cTransport = &http.Transport{Protocols: h2cProtocols()}
+
+// Returns the protocol set of cleartext HTTP/2 alone.
+func h2cProtocols() *http.Protocols {
+	var p http.Protocols
+	p.SetUnencryptedHTTP2(true)
+	return &p
+}
+
+// DialGRPC opens an Evaluate call of the Integrand service in
+// evaluate.proto at addr, a host:port serving gRPC over cleartext HTTP/2,
+// and returns a client evaluating over it. ctx bounds the whole call: its
+// deadline is sent to the server as the call's timeout, and the call is
+// abandoned when it is done. As with NewClient, a batch is sent only once
+// the previous one is answered. The connection is made in the background,
+// so a server that cannot be reached is reported by the first evaluation.
+// The client must be closed to end the call.
+func DialGRPC(ctx context.Context, addr string) (*Client, error) {
+	body, w := io.Pipe()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "http://"+addr+grpcMethod, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/grpc")
+	req.Header.Set("Te", "trailers")
+	if deadline, ok := ctx.Deadline(); ok {
+		req.Header.Set("Grpc-Timeout", encodeTimeout(time.Until(deadline)))
+	}
+
+	// gRPC servers may hold back the response headers until the first
+	// reply, so the request is sent while the first batch is written
+	s := &grpcStream{body: w, done: make(chan struct{})}
+	go func() {
+		defer close(s.done)
+		s.resp, s.err = h2cTransport.RoundTrip(req)
+		if s.err == nil && s.resp.StatusCode != http.StatusOK {
+			s.resp.Body.Close()
+			s.err = &StatusError{Code: statusUnknown, Message: s.resp.Status}
+		}
+	}()
+
+	return &Client{ctx: ctx, s: s}, nil
+}
+
+// IntegrateGRPC integrates the integrand served at addr by the Integrand
+// service across [a, b] to within tol, as Integrate does over a byte
+// stream, and then ends the call. A failed call, including one ended with
+// a status other than OK, is reported in place of the integrator's error.
+func IntegrateGRPC(ctx context.Context, addr string, a, b, tol float64, opts ...goint.Option) (goint.Result, error) {
+	c, err := DialGRPC(ctx, addr)
+	if err != nil {
+		return goint.Result{Value: math.NaN()}, err
+	}
+	return integrate(c, a, b, tol, opts)
+}
+
+// The gRPC form of the protocol: an Evaluate call whose request body is
+// written through a pipe as batches are sent.
+type grpcStream struct {
+	body *io.PipeWriter
+	done chan struct{} // closed once resp or err is set
+	resp *http.Response
+	err  error
+}
+
+func (s *grpcStream) send(xs []float64) error {
+	if _, err := s.body.Write(appendMessage(nil, xs)); err != nil {
+		// The transport closes the body when the call fails
+		<-s.done
+		if s.err != nil {
+			return s.err
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *grpcStream) recv() ([]float64, error) {
+	<-s.done
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	msg, err := readMessage(s.resp.Body)
+	if err == io.EOF {
+		// The server ended the call without answering
+		if err := s.status(); err != nil {
+			return nil, err
+		}
+		return nil, ErrProtocol
+	}
+	if err != nil {
+		return nil, err
+	}
+	return decodeDoubles(msg)
+}
+
+func (s *grpcStream) close() error {
+	s.body.Close()
+	<-s.done
+	if s.err != nil {
+		return s.err
+	}
+	defer s.resp.Body.Close()
+
+	// The status follows whatever the server sent after the last reply
+	if _, err := io.Copy(io.Discard, s.resp.Body); err != nil {
+		return err
+	}
+	return s.status()
+}
+
+// Returns the error for the status the server ended the call with, which
+// comes in the trailers, or in the headers if the server sent nothing
+// else. The body must have been read to its end.
+func (s *grpcStream) status() error {
+	code, msg := s.resp.Trailer.Get("Grpc-Status"), s.resp.Trailer.Get("Grpc-Message")
+	if code == "" {
+		code, msg = s.resp.Header.Get("Grpc-Status"), s.resp.Header.Get("Grpc-Message")
+	}
+	n, err := strconv.Atoi(code)
+	if err != nil {
+		return ErrProtocol
+	}
+	if n == statusOK {
+		return nil
+	}
+	if m, err := url.PathUnescape(msg); err == nil {
+		msg = m
+	}
+	return &StatusError{Code: n, Message: msg}
+}
+
+// GRPCHandler returns an http.Handler serving Evaluate calls of the
+// Integrand service in evaluate.proto with f. Each batch is answered
+// before the next is read, and the timeout a client sends bounds its
+// call. gRPC runs over HTTP/2, which the handler must be served with, as
+// ServeGRPC does.
+func GRPCHandler(f goint.BatchFunction) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || !strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc") {
+			http.Error(w, "expected a gRPC request", http.StatusUnsupportedMediaType)
+			return
+		}
+		w.Header().Set("Content-Type", "application/grpc")
+		code, msg := serveEvaluate(w, r, f)
+		w.Header().Set(http.TrailerPrefix+"Grpc-Status", strconv.Itoa(code))
+		if msg != "" {
+			w.Header().Set(http.TrailerPrefix+"Grpc-Message", url.PathEscape(msg))
+		}
+	})
+}
+
+// Answers the batches of one call, returning the status to end it with.
+func serveEvaluate(w http.ResponseWriter, r *http.Request, f goint.BatchFunction) (int, string) {
+	if r.URL.Path != grpcMethod {
+		return statusUnimplemented, "unknown method " + r.URL.Path
+	}
+
+	ctx := r.Context()
+	if t := r.Header.Get("Grpc-Timeout"); t != "" {
+		d, ok := decodeTimeout(t)
+		if !ok {
+			return statusInvalidArgument, "malformed timeout " + t
+		}
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d)
+		defer cancel()
+	}
+
+	w.WriteHeader(http.StatusOK)
+	rc := http.NewResponseController(w)
+	if err := rc.Flush(); err != nil {
+		return statusOf(err)
+	}
+
+	// Each batch is read in the background so that the deadline can end
+	// the call while the server waits for one; the read left pending
+	// fails once the handler returns
+	type message struct {
+		msg []byte
+		err error
+	}
+	next := make(chan message, 1)
+	read := func() {
+		msg, err := readMessage(r.Body)
+		next <- message{msg, err}
+	}
+	go read()
+	for {
+		var m message
+		select {
+		case <-ctx.Done():
+			return statusOf(ctx.Err())
+		case m = <-next:
+		}
+		if m.err == io.EOF {
+			return statusOK, ""
+		}
+		if m.err != nil {
+			return statusOf(m.err)
+		}
+		xs, err := decodeDoubles(m.msg)
+		if err != nil {
+			return statusOf(err)
+		}
+
+		ys := make([]float64, len(xs))
+		f(xs, ys)
+		if err := ctx.Err(); err != nil {
+			return statusOf(err)
+		}
+		if _, err := w.Write(appendMessage(nil, ys)); err != nil {
+			return statusOf(err)
+		}
+		if err := rc.Flush(); err != nil {
+			return statusOf(err)
+		}
+		go read()
+	}
+}
+
+// Returns the status ending a call that failed with err.
+func statusOf(err error) (int, string) {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return statusDeadlineExceeded, "deadline exceeded"
+	case errors.Is(err, errMessageSize):
+		return statusResourceExhausted, err.Error()
+	case errors.Is(err, ErrProtocol):
+		return statusInvalidArgument, err.Error()
+	}
+	return statusCanceled, err.Error()
+}
+
+// ServeGRPC serves GRPCHandler(f) over cleartext HTTP/2 on l until ctx is
+// done, when it closes l, abandons the calls in progress and returns ctx's
+// error. Other failures of l are returned as they happen.
+func ServeGRPC(ctx context.Context, l net.Listener, f goint.BatchFunction) error {
+	srv := &http.Server{Handler: GRPCHandler(f), Protocols: h2cProtocols()}
+	stop := context.AfterFunc(ctx, func() { srv.Close() })
+	defer stop()
+
+	if err := srv.Serve(l); err != http.ErrServerClosed {
+		return err
+	}
+	return ctx.Err()
+}
+
+// errMessageSize is returned for a gRPC message longer than maxMessage.
+var errMessageSize = errors.New("remote: gRPC message too large")
+
+// Appends the gRPC message holding vs, a Batch or Values message with vs
+// as its packed repeated double field, to buf: a zero byte marking it
+// uncompressed, the big-endian uint32 length and the protobuf encoding.
+func appendMessage(buf []byte, vs []float64) []byte {
+	size := 0
+	if len(vs) > 0 {
+		size = 1 + len(binary.AppendUvarint(nil, uint64(8*len(vs)))) + 8*len(vs)
+	}
+	buf = append(buf, 0)
+	buf = binary.BigEndian.AppendUint32(buf, uint32(size))
+	if len(vs) == 0 {
+		return buf
+	}
+
+	buf = append(buf, 1<<3|2)
+	buf = binary.AppendUvarint(buf, uint64(8*len(vs)))
+	for _, v := range vs {
+		buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(v))
+	}
+	return buf
+}
+
+// Reads the protobuf encoding of one gRPC message, returning io.EOF if
+// the stream ends before it starts. Compressed messages are refused, as
+// no compression is ever negotiated.
+func readMessage(r io.Reader) ([]byte, error) {
+	var header [5]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, err
+	}
+	if header[0] != 0 {
+		return nil, ErrProtocol
+	}
+	n := binary.BigEndian.Uint32(header[1:])
+	if n > maxMessage {
+		return nil, errMessageSize
+	}
+
+	msg := make([]byte, n)
+	if _, err := io.ReadFull(r, msg); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return msg, nil
+}
+
+// Decodes the repeated double field 1 of a Batch or Values message, packed
+// or not, skipping any other fields as protobuf requires.
+func decodeDoubles(msg []byte) ([]float64, error) {
+	var vs []float64
+	for len(msg) > 0 {
+		key, n := binary.Uvarint(msg)
+		if n <= 0 {
+			return nil, ErrProtocol
+		}
+		msg = msg[n:]
+
+		var field []byte
+		switch key & 7 {
+		case 0:
+			if _, n = binary.Uvarint(msg); n <= 0 {
+				return nil, ErrProtocol
+			}
+			msg = msg[n:]
+			continue
+		case 1:
+			if len(msg) < 8 {
+				return nil, ErrProtocol
+			}
+			field, msg = msg[:8], msg[8:]
+		case 2:
+			size, n := binary.Uvarint(msg)
+			if n <= 0 || size > uint64(len(msg)-n) {
+				return nil, ErrProtocol
+			}
+			field, msg = msg[n:n+int(size)], msg[n+int(size):]
+		case 5:
+			if len(msg) < 4 {
+				return nil, ErrProtocol
+			}
+			msg = msg[4:]
+			continue
+		default:
+			return nil, ErrProtocol
+		}
+
+		if key>>3 != 1 {
+			continue
+		}
+		if key&7 == 2 && len(field)%8 != 0 {
+			return nil, ErrProtocol
+		}
+		for ; len(field) > 0; field = field[8:] {
+			vs = append(vs, math.Float64frombits(binary.LittleEndian.Uint64(field)))
+		}
+	}
+
+	if len(vs) > MaxBatch {
+		return nil, errMessageSize
+	}
+	return vs, nil
+}
+
+// The units of a grpc-timeout header, from the finest.
+var timeoutUnits = []struct {
+	unit   time.Duration
+	suffix byte
+}{
+	{time.Nanosecond, 'n'},
+	{time.Microsecond, 'u'},
+	{time.Millisecond, 'm'},
+	{time.Second, 'S'},
+	{time.Minute, 'M'},
+	{time.Hour, 'H'},
+}
+
+// Formats d as a grpc-timeout header value, at most eight digits in the
+// finest unit that holds it, rounded up so that the server's deadline
+// falls no earlier than the client's.
+func encodeTimeout(d time.Duration) string {
+	d = max(d, 0)
+	for _, u := range timeoutUnits {
+		if n := (d + u.unit - 1) / u.unit; n < 1e8 {
+			return strconv.FormatInt(int64(n), 10) + string(u.suffix)
+		}
+	}
+	return "99999999H"
+}
+
+// Parses a grpc-timeout header value, reporting whether it is well formed.
+// Timeouts beyond the range of a time.Duration are capped.
+func decodeTimeout(s string) (time.Duration, bool) {
+	if len(s) < 2 || len(s) > 9 {
+		return 0, false
+	}
+	n, err := strconv.ParseUint(s[:len(s)-1], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	for _, u := range timeoutUnits {
+		if u.suffix == s[len(s)-1] {
+			if n > uint64(math.MaxInt64/u.unit) {
+				return math.MaxInt64, true
+			}
+			return time.Duration(n) * u.unit, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,236 @@
+package remote
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// Serves h over cleartext HTTP/2 on a local port until the test ends,
+// returning its address.
+func serveH2C(t *testing.T, h http.Handler) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := &http.Server{Handler: h, Protocols: h2cProtocols()}
+	go srv.Serve(l)
+	t.Cleanup(func() { srv.Close() })
+	return l.Addr().String()
+}
+
+func TestIntegrateGRPC(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() {
+		served <- ServeGRPC(ctx, l, func(xs, ys []float64) {
+			for i, x := range xs {
+				ys[i] = math.Exp(-x * x)
+			}
+		})
+	}()
+
+	r, err := IntegrateGRPC(context.Background(), l.Addr().String(), math.Inf(-1), math.Inf(1), 1e-10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(r.Value-math.SqrtPi) > 1e-10 {
+		t.Errorf("got %g, want %g", r.Value, math.SqrtPi)
+	}
+
+	cancel()
+	if err := <-served; err != context.Canceled {
+		t.Errorf("server: %v", err)
+	}
+}
+
+func TestGRPCBackpressure(t *testing.T) {
+	// A server that reads batches as they arrive but answers each only
+	// after a pause, recording when it did both
+	var arrived, answered []time.Time
+	addr := serveH2C(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/grpc")
+		w.Header().Set(http.TrailerPrefix+"Grpc-Status", "0")
+		rc := http.NewResponseController(w)
+
+		batches := make(chan []float64)
+		go func() {
+			defer close(batches)
+			for {
+				msg, err := readMessage(r.Body)
+				if err != nil {
+					return
+				}
+				arrived = append(arrived, time.Now())
+				xs, _ := decodeDoubles(msg)
+				batches <- xs
+			}
+		}()
+		for xs := range batches {
+			time.Sleep(20 * time.Millisecond)
+			answered = append(answered, time.Now())
+			w.Write(appendMessage(nil, xs))
+			rc.Flush()
+		}
+	}))
+
+	c, err := DialGRPC(context.Background(), addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ys := make([]float64, 2)
+	for i := 0; i < 3; i++ {
+		c.Evaluate([]float64{float64(i), 1}, ys)
+		if c.Err() != nil || ys[0] != float64(i) {
+			t.Fatalf("batch %d: got %v, %v", i, ys, c.Err())
+		}
+	}
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i < len(arrived); i++ {
+		if arrived[i].Before(answered[i-1]) {
+			t.Errorf("batch %d was sent before batch %d was answered", i, i-1)
+		}
+	}
+}
+
+func TestGRPCDeadline(t *testing.T) {
+	// The client's deadline is sent to the server and ends the call
+	timeouts := make(chan string, 1)
+	stall := GRPCHandler(func(xs, ys []float64) { time.Sleep(time.Second) })
+	addr := serveH2C(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case timeouts <- r.Header.Get("Grpc-Timeout"):
+		default:
+		}
+		stall.ServeHTTP(w, r)
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	r, err := IntegrateGRPC(ctx, addr, 0, 1, 1e-10)
+	if !errors.Is(err, context.DeadlineExceeded) || !math.IsNaN(r.Value) {
+		t.Errorf("got %g, %v", r.Value, err)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Error("deadline not applied")
+	}
+	if d, ok := decodeTimeout(<-timeouts); !ok || d <= 0 || d > 50*time.Millisecond {
+		t.Errorf("sent a timeout of %v", d)
+	}
+
+	// The server applies a timeout on its own, ending the call with
+	// DEADLINE_EXCEEDED even if the client never sends a batch
+	addr = serveH2C(t, GRPCHandler(func(xs, ys []float64) {}))
+	body, w := io.Pipe()
+	defer w.Close()
+	req, _ := http.NewRequest(http.MethodPost, "http://"+addr+grpcMethod, body)
+	req.Header.Set("Content-Type", "application/grpc")
+	req.Header.Set("Grpc-Timeout", "50m")
+	resp, err := h2cTransport.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	s := &grpcStream{resp: resp}
+	if _, err := io.Copy(io.Discard, resp.Body); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.status(); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("server ended the call with %v", err)
+	}
+}
+
+func TestGRPCStatus(t *testing.T) {
+	addr := serveH2C(t, GRPCHandler(func(xs, ys []float64) {}))
+
+	// An unknown method
+	req, _ := http.NewRequest(http.MethodPost, "http://"+addr+"/goint.remote.Integrand/Other", http.NoBody)
+	req.Header.Set("Content-Type", "application/grpc")
+	resp, err := h2cTransport.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	var status *StatusError
+	if err := (&grpcStream{resp: resp}).status(); !errors.As(err, &status) || status.Code != statusUnimplemented {
+		t.Errorf("unknown method gave %v", err)
+	}
+
+	// No server at all
+	l, _ := net.Listen("tcp", "127.0.0.1:0")
+	closed := l.Addr().String()
+	l.Close()
+	if _, err := IntegrateGRPC(context.Background(), closed, 0, 1, 1e-6); err == nil {
+		t.Error("integrated without a server")
+	}
+}
+
+func TestGRPCMessages(t *testing.T) {
+	for _, vs := range [][]float64{nil, {1}, {0, -2.5, math.Inf(1)}} {
+		msg, err := readMessage(bytes.NewReader(appendMessage(nil, vs)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := decodeDoubles(msg)
+		if err != nil || len(got) != len(vs) {
+			t.Fatalf("%v decoded as %v, %v", vs, got, err)
+		}
+		for i := range vs {
+			if got[i] != vs[i] {
+				t.Errorf("%v decoded as %v", vs, got)
+			}
+		}
+	}
+
+	// Unpacked values and unknown fields of every wire type are accepted
+	msg := []byte{
+		1<<3 | 1, 0, 0, 0, 0, 0, 0, 0xf0, 0x3f, // 1.0, unpacked
+		2<<3 | 0, 0x96, 0x01, // a varint
+		3<<3 | 2, 2, 'h', 'i', // a string
+		4<<3 | 5, 1, 2, 3, 4, // a fixed32
+		1<<3 | 2, 8, 0, 0, 0, 0, 0, 0, 0, 0x40, // 2.0, packed
+	}
+	if got, err := decodeDoubles(msg); err != nil || len(got) != 2 || got[0] != 1 || got[1] != 2 {
+		t.Errorf("got %v, %v", got, err)
+	}
+	if _, err := decodeDoubles([]byte{1<<3 | 2, 3, 0, 0, 0}); err != ErrProtocol {
+		t.Errorf("a partial double gave %v", err)
+	}
+	if _, err := readMessage(bytes.NewReader([]byte{1, 0, 0, 0, 0})); err != ErrProtocol {
+		t.Errorf("a compressed message gave %v", err)
+	}
+	if _, err := readMessage(bytes.NewReader([]byte{0, 0xff, 0xff, 0xff, 0xff})); err != errMessageSize {
+		t.Errorf("an oversized message gave %v", err)
+	}
+}
+
+func TestGRPCTimeouts(t *testing.T) {
+	for _, d := range []time.Duration{0, time.Nanosecond, 1500 * time.Microsecond, 3 * time.Second, 200 * time.Hour} {
+		got, ok := decodeTimeout(encodeTimeout(d))
+		if !ok || got < d || got > d+d/1000+time.Nanosecond {
+			t.Errorf("%v round trips to %v via %q", d, got, encodeTimeout(d))
+		}
+	}
+	for _, s := range []string{"", "5", "123456789m", "5x", "-5m"} {
+		if _, ok := decodeTimeout(s); ok {
+			t.Errorf("accepted %q", s)
+		}
+	}
+	if d, ok := decodeTimeout("99999999H"); !ok || d != math.MaxInt64 {
+		t.Errorf("an overlong timeout gave %v", d)
+	}
+}
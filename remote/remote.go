@@ -0,0 +1,233 @@
+// Package remote lets the integrand of an integration live in another
+// process, language or machine while goint drives the adaptivity. The
+// integrator streams batches of abscissas to the integrand's server and
+// reads back the values, one batch at a time, so the server is never
+// asked for more than one batch ahead of what it has answered.
+//
+// The protocol comes in two forms. The framed form runs over any ordered
+// byte stream, such as a net.Conn: each batch is a frame of a
+// little-endian uint32 count followed by that many little-endian IEEE 754
+// float64 abscissas, answered by a frame of the same form holding the
+// values. The gRPC form is the bidirectional stream of the Integrand
+// service in evaluate.proto, so servers may be written with any gRPC
+// tooling; DialGRPC, IntegrateGRPC and ServeGRPC speak it over cleartext
+// HTTP/2 using only the standard library.
+package remote
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"time"
+
+	"goint"
+)
+
+// The most abscissas sent in one frame. Larger batches are split.
+const MaxBatch = 1 << 16
+
+// ErrProtocol is returned when a frame is malformed or a reply does not
+// match its batch.
+var ErrProtocol = errors.New("remote: protocol error")
+
+// A deadliner is a stream that can bound the time spent blocked on it, as
+// a net.Conn can.
+type deadliner interface {
+	SetDeadline(t time.Time) error
+}
+
+// A Client evaluates an integrand served at the other end of a stream.
+type Client struct {
+	ctx   context.Context
+	s     stream
+	err   error
+	evals int
+}
+
+// A stream carries batches of abscissas to a server and their values back
+// in one of the forms of the protocol.
+type stream interface {
+	send(xs []float64) error
+	recv() ([]float64, error)
+	close() error
+}
+
+// The framed form of the protocol over a byte stream.
+type frameStream struct {
+	ctx context.Context
+	rw  io.ReadWriter
+}
+
+func (s frameStream) send(xs []float64) error {
+	if err := setDeadline(s.ctx, s.rw); err != nil {
+		return err
+	}
+	return writeFrame(s.rw, xs)
+}
+
+func (s frameStream) recv() ([]float64, error) {
+	return readFrame(s.rw)
+}
+
+// The byte stream belongs to the caller, so there is nothing to close.
+func (s frameStream) close() error {
+	return nil
+}
+
+// NewClient returns a client evaluating over rw. Evaluation stops when
+// ctx is done; if rw has a SetDeadline method, as a net.Conn does, ctx's
+// deadline is also applied to every read and write, so that a stalled
+// server cannot block the integration beyond it.
+func NewClient(ctx context.Context, rw io.ReadWriter) *Client {
+	return &Client{ctx: ctx, s: frameStream{ctx, rw}}
+}
+
+// Evaluate sends xs to the server and stores the values it returns in ys.
+// Its method value is a goint.BatchFunction. After the first failure,
+// which Err reports, every value is NaN and nothing more is sent.
+func (c *Client) Evaluate(xs, ys []float64) {
+	for i := 0; i < len(xs) && c.err == nil; i += MaxBatch {
+		n := min(len(xs)-i, MaxBatch)
+		c.err = c.exchange(xs[i:i+n], ys[i:i+n])
+	}
+	if c.err != nil {
+		for i := range ys {
+			ys[i] = math.NaN()
+		}
+	}
+}
+
+// Err returns the first error met by Evaluate, or nil.
+func (c *Client) Err() error {
+	return c.err
+}
+
+// Evals returns the number of values received from the server.
+func (c *Client) Evals() int {
+	return c.evals
+}
+
+// Close ends the stream. For a client from DialGRPC it ends the call and
+// returns the status the server ended it with, as a *StatusError unless
+// it is OK; a client from NewClient leaves its byte stream to the caller,
+// and Close does nothing.
+func (c *Client) Close() error {
+	return c.s.close()
+}
+
+// Sends one batch and reads its reply.
+func (c *Client) exchange(xs, ys []float64) error {
+	if err := c.ctx.Err(); err != nil {
+		return err
+	}
+
+	if err := c.s.send(xs); err != nil {
+		return err
+	}
+	values, err := c.s.recv()
+	if err != nil {
+		return err
+	}
+	if len(values) != len(xs) {
+		return ErrProtocol
+	}
+	copy(ys, values)
+	c.evals += len(values)
+	return nil
+}
+
+// Integrate integrates the integrand served over rw across [a, b] to
+// within tol with goint.IntegrateBatch and opts. If the exchange fails,
+// as when ctx is done first, its error is returned in place of the
+// integrator's, with the estimate made from the values received.
+func Integrate(ctx context.Context, rw io.ReadWriter, a, b, tol float64, opts ...goint.Option) (goint.Result, error) {
+	return integrate(NewClient(ctx, rw), a, b, tol, opts)
+}
+
+// Implements Integrate and IntegrateGRPC with the client c, which it
+// closes.
+func integrate(c *Client, a, b, tol float64, opts []goint.Option) (goint.Result, error) {
+	r, err := goint.IntegrateBatch(c.Evaluate, a, b, tol, opts...)
+	if cerr := c.Close(); c.err == nil {
+		c.err = cerr
+	}
+	if c.err != nil {
+		return r, c.err
+	}
+	return r, err
+}
+
+// Serve answers the batches read from rw with f until the client ends
+// the stream, when it returns nil, ctx is done, or an exchange fails.
+// Deadlines are applied as by NewClient.
+func Serve(ctx context.Context, rw io.ReadWriter, f goint.BatchFunction) error {
+	if err := setDeadline(ctx, rw); err != nil {
+		return err
+	}
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		xs, err := readFrame(rw)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		ys := make([]float64, len(xs))
+		f(xs, ys)
+		if err := writeFrame(rw, ys); err != nil {
+			return err
+		}
+	}
+}
+
+// Applies the deadline of ctx, if it has one, to rw, if it can take one.
+func setDeadline(ctx context.Context, rw io.ReadWriter) error {
+	d, ok := rw.(deadliner)
+	deadline, has := ctx.Deadline()
+	if !ok || !has {
+		return nil
+	}
+	return d.SetDeadline(deadline)
+}
+
+// Writes vs as one frame.
+func writeFrame(w io.Writer, vs []float64) error {
+	buf := make([]byte, 4+8*len(vs))
+	binary.LittleEndian.PutUint32(buf, uint32(len(vs)))
+	for i, v := range vs {
+		binary.LittleEndian.PutUint64(buf[4+8*i:], math.Float64bits(v))
+	}
+	_, err := w.Write(buf)
+	return err
+}
+
+// Reads one frame, returning io.EOF if the stream ends before it starts.
+func readFrame(r io.Reader) ([]float64, error) {
+	var header [4]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, err
+	}
+	n := binary.LittleEndian.Uint32(header[:])
+	if n > MaxBatch {
+		return nil, ErrProtocol
+	}
+
+	buf := make([]byte, 8*n)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	vs := make([]float64, n)
+	for i := range vs {
+		vs[i] = math.Float64frombits(binary.LittleEndian.Uint64(buf[8*i:]))
+	}
+	return vs, nil
+}
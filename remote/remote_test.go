@@ -0,0 +1,89 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"goint"
+)
+
+func TestIntegrate(t *testing.T) {
+	client, server := net.Pipe()
+	served := make(chan error, 1)
+	go func() {
+		served <- Serve(context.Background(), server, func(xs, ys []float64) {
+			for i, x := range xs {
+				ys[i] = math.Exp(-x * x)
+			}
+		})
+	}()
+
+	r, err := Integrate(context.Background(), client, math.Inf(-1), math.Inf(1), 1e-10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(r.Value-math.SqrtPi) > 1e-10 {
+		t.Errorf("got %g, want %g", r.Value, math.SqrtPi)
+	}
+
+	client.Close()
+	if err := <-served; err != nil {
+		t.Errorf("server: %v", err)
+	}
+}
+
+func TestDeadline(t *testing.T) {
+	client, server := net.Pipe()
+	defer server.Close()
+
+	// A server that reads but never answers
+	go func() {
+		for {
+			if _, err := readFrame(server); err != nil {
+				return
+			}
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	r, err := Integrate(ctx, client, 0, 1, 1e-10)
+	if !errors.Is(err, os.ErrDeadlineExceeded) || !math.IsNaN(r.Value) {
+		t.Errorf("got %g, %v", r.Value, err)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Error("deadline not applied")
+	}
+}
+
+func TestFrames(t *testing.T) {
+	client, server := net.Pipe()
+	defer server.Close()
+	go func() {
+		readFrame(server)
+		writeFrame(server, []float64{1})
+	}()
+
+	c := NewClient(context.Background(), client)
+	ys := make([]float64, 2)
+	c.Evaluate([]float64{0, 1}, ys)
+	if c.Err() != ErrProtocol || !math.IsNaN(ys[0]) {
+		t.Errorf("got %v, %v for a short reply", ys, c.Err())
+	}
+
+	// Once failed, the client sends nothing more
+	c.Evaluate([]float64{2}, ys[:1])
+	if !math.IsNaN(ys[0]) || c.Evals() != 0 {
+		t.Errorf("got %v after a failure", ys)
+	}
+
+	if _, err := goint.IntegrateBatch(c.Evaluate, 0, 1, 1e-6); err == nil {
+		t.Error("converged on NaN values")
+	}
+}
@@ -0,0 +1,44 @@
+package goint
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrMissingEvaluation is returned by Replay when the integrator asks for
+// a point that the evaluation log does not contain.
+var ErrMissingEvaluation = errors.New("goint: evaluation missing from log")
+
+// Replay reruns IntegrateGK over [a, b] with tolerance tol and opts,
+// answering every evaluation of the integrand from records, such as those
+// written by WithEvaluationLog, instead of calling it again. With the
+// options of the recorded run the reduction is reproduced exactly. Runs
+// that need only a subset of the recorded points can be made without
+// repeating the experiment: those changing the summation, as Deterministic
+// does, or stopping sooner, with a looser tolerance, a smaller memory
+// allowance or WithExtrapolation. Diagnostics that evaluate elsewhere,
+// such as WithPrecisionCheck and WithMonotone, need those points recorded
+// too. If a point is asked for that the log lacks, it is treated as NaN,
+// so that refinement stops, and ErrMissingEvaluation is returned.
+func Replay(records []EvaluationRecord, a, b, tol float64, opts ...Option) (Result, error) {
+	table := make(map[float64]float64, len(records))
+	for _, r := range records {
+		table[r.X] = r.Y
+	}
+
+	missing := false
+	f := func(x float64) float64 {
+		y, ok := table[x]
+		if !ok {
+			missing = true
+			return math.NaN()
+		}
+		return y
+	}
+
+	r, err := IntegrateGK(f, a, b, tol, opts...)
+	if missing {
+		return r, ErrMissingEvaluation
+	}
+	return r, err
+}
@@ -0,0 +1,46 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestReplay(t *testing.T) {
+	f := func(x float64) float64 { return math.Log(x) / math.Sqrt(x) }
+	const tol = 1e-10
+
+	var log recordSlice
+	recorded, err := IntegrateGK(f, 0, 1, tol, WithEvaluationLog(&log))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := Replay(log.records, 0, 1, tol)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Value != recorded.Value || r.Error != recorded.Error || r.Evals != recorded.Evals {
+		t.Errorf("replayed %+v, recorded %+v", r, recorded)
+	}
+
+	// Other reductions of the same evaluations
+	r, err = Replay(log.records, 0, 1, tol, Deterministic())
+	if err != nil {
+		t.Error(err)
+	}
+	if msg, ok := checkValue(r.Value, recorded.Value, 1e-14); !ok {
+		t.Error(msg)
+	}
+	r, err = Replay(log.records, 0, 1, 1e-6)
+	if err != nil {
+		t.Error(err)
+	}
+	if msg, ok := checkValue(r.Value, -4, 1e-6); !ok {
+		t.Error(msg)
+	}
+
+	// A tighter tolerance needs points that were never recorded
+	if _, err := Replay(log.records, 0, 1, 1e-14); err != ErrMissingEvaluation {
+		t.Errorf("got error %v, expected %v", err, ErrMissingEvaluation)
+	}
+}
@@ -0,0 +1,49 @@
+package goint
+
+import (
+	"math"
+)
+
+// PartialMoment computes the lower partial moment of order k of the
+// density pdf about threshold,
+//
+//	integral over (-Inf, threshold] of (threshold - x)^k pdf(x) dx,
+//
+// to within tol. This is the shortfall probability for k = 0, the expected
+// shortfall below the threshold for k = 1, and the semivariance for k = 2.
+// +Inf is returned if the moment does not exist, and NaN if tol is
+// negative or NaN.
+func PartialMoment(pdf Function, k, threshold, tol float64) float64 {
+	g := func(x float64) float64 {
+		if x > threshold {
+			return 0
+		}
+		return math.Pow(threshold-x, k) * pdf(x)
+	}
+
+	return tailIntegral(g, threshold, -1, tol)
+}
+
+// ExpectedShortfall computes the expected shortfall (conditional value at
+// risk) at level alpha of a loss with density pdf, the mean loss given
+// that the loss exceeds its alpha-quantile,
+//
+//	integral over [VaR, +Inf) of x pdf(x) dx / (1 - alpha),
+//
+// to within roughly tol. +Inf is returned if the loss distribution has too
+// heavy a tail for its mean to exist, and NaN unless 0 < alpha < 1 and
+// tol is nonnegative.
+func ExpectedShortfall(pdf Function, alpha, tol float64) float64 {
+	if !(alpha > 0 && alpha < 1) || !(tol >= 0) {
+		return math.NaN()
+	}
+
+	tail := 1 - alpha
+	v := Quantile(pdf, alpha, tol*tail/4)
+	if math.IsInf(v, 0) {
+		return v
+	}
+
+	g := func(x float64) float64 { return x * pdf(x) }
+	return tailIntegral(g, v, 1, tol*tail/2) / tail
+}
@@ -0,0 +1,120 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func standardNormal(x float64) float64 {
+	return math.Exp(-x*x/2) / math.Sqrt(2*math.Pi)
+}
+
+func TestQuantile(t *testing.T) {
+	const (
+		tol = 1e-10
+	)
+
+	for _, p := range []float64{.01, .3, .5, .9, .999} {
+		want := normalQuantile(p)
+		if msg, ok := checkValue(Quantile(standardNormal, p, tol), want, 1e-7); !ok {
+			t.Errorf("p = %g: %s", p, msg)
+		}
+	}
+
+	if msg, ok := checkValue(CDF(standardNormal, 1.5, tol), normalCDF(1.5), 1e-9); !ok {
+		t.Error(msg)
+	}
+	if !math.IsNaN(Quantile(standardNormal, 1, tol)) {
+		t.Error("expected NaN for p = 1")
+	}
+
+	// Narrow densities far from the origin are still found
+	for _, c := range []struct{ mu, sigma float64 }{{-300, 2}, {1000, 1}, {50, 1e-2}} {
+		pdf := func(x float64) float64 { return standardNormal((x-c.mu)/c.sigma) / c.sigma }
+		for _, p := range []float64{.05, .5, .95} {
+			want := c.mu + c.sigma*normalQuantile(p)
+			if msg, ok := checkValue(Quantile(pdf, p, tol), want, 1e-6*c.sigma); !ok {
+				t.Errorf("N(%g, %g), p = %g: %s", c.mu, c.sigma, p, msg)
+			}
+		}
+		if msg, ok := checkValue(CDF(pdf, c.mu, tol), .5, 1e-9); !ok {
+			t.Errorf("N(%g, %g): %s", c.mu, c.sigma, msg)
+		}
+	}
+
+	for _, bad := range []float64{-1, math.NaN()} {
+		if !math.IsNaN(Quantile(standardNormal, .5, bad)) || !math.IsNaN(CDF(standardNormal, 0, bad)) {
+			t.Errorf("expected NaN for tol = %g", bad)
+		}
+	}
+	if msg, ok := checkValue(Quantile(standardNormal, .9, 0), normalQuantile(.9), 1e-9); !ok {
+		t.Errorf("tol = 0: %s", msg)
+	}
+}
+
+func TestPartialMoment(t *testing.T) {
+	const (
+		tol = 1e-9
+	)
+
+	// For the standard normal about zero: 1/2, phi(0) and 1/2
+	want := []float64{.5, 1 / math.Sqrt(2*math.Pi), .5}
+	for k, w := range want {
+		if msg, ok := checkValue(PartialMoment(standardNormal, float64(k), 0, tol), w, 1e-8); !ok {
+			t.Errorf("k = %d: %s", k, msg)
+		}
+	}
+
+	// The Cauchy distribution has no first partial moment
+	cauchy := func(x float64) float64 { return 1 / (math.Pi * (1 + x*x)) }
+	if got := PartialMoment(cauchy, 1, 0, 1e-6); !math.IsInf(got, 1) {
+		t.Errorf("expected +Inf for a Cauchy partial moment, got %g", got)
+	}
+
+	if !math.IsNaN(PartialMoment(standardNormal, 1, 0, -1)) {
+		t.Error("expected NaN for a negative tolerance")
+	}
+}
+
+func TestExpectedShortfall(t *testing.T) {
+	// For the standard normal, ES = phi(VaR) / (1 - alpha)
+	for _, alpha := range []float64{.9, .975, .99} {
+		want := standardNormal(normalQuantile(alpha)) / (1 - alpha)
+		if msg, ok := checkValue(ExpectedShortfall(standardNormal, alpha, 1e-8), want, 1e-6); !ok {
+			t.Errorf("alpha = %g: %s", alpha, msg)
+		}
+	}
+
+	// A Pareto loss with shape 3/2 on [1, Inf) has ES = 3 (1 - alpha)^(-2/3)
+	pareto := func(x float64) float64 {
+		if x < 1 {
+			return 0
+		}
+		return 1.5 * math.Pow(x, -2.5)
+	}
+	want := 3 * math.Pow(.05, -2./3)
+	if msg, ok := checkValue(ExpectedShortfall(pareto, .95, 1e-6), want, 1e-3*want); !ok {
+		t.Error(msg)
+	}
+
+	// With shape 1 the mean does not exist
+	heavy := func(x float64) float64 {
+		if x < 1 {
+			return 0
+		}
+		return 1 / (x * x)
+	}
+	if got := ExpectedShortfall(heavy, .95, 1e-6); !math.IsInf(got, 1) {
+		t.Errorf("expected +Inf for a shape-1 Pareto loss, got %g", got)
+	}
+
+	// A narrow loss far from the origin
+	far := func(x float64) float64 { return standardNormal(x - 1000) }
+	want = 1000 + standardNormal(normalQuantile(.95))/.05
+	if msg, ok := checkValue(ExpectedShortfall(far, .95, 1e-8), want, 1e-5); !ok {
+		t.Error(msg)
+	}
+	if !math.IsNaN(ExpectedShortfall(standardNormal, .95, math.NaN())) {
+		t.Error("expected NaN for a NaN tolerance")
+	}
+}
@@ -0,0 +1,58 @@
+package goint
+
+import (
+	"math"
+)
+
+const (
+	// The fewest trapezoid halvings Romberg performs before testing for
+	// convergence, so that a coarse grid cannot agree with itself by
+	// accident.
+	rombergMinLevels = 4
+
+	// The most trapezoid halvings Romberg performs.
+	rombergMaxLevels = 24
+)
+
+// Romberg integrates f over the finite interval [a, b] to within err by
+// Romberg's method: the trapezoid rule is applied with successively halved
+// steps, reusing every earlier evaluation, and the sequence is accelerated
+// by Richardson extrapolation. It converges very quickly for smooth
+// integrands and is independent of Boole-based refinement, which makes it
+// a useful cross-check on Integrate. If either bound is infinite or err is
+// NaN, the result is NaN.
+func Romberg(f Function, a, b, err float64) float64 {
+	if math.IsInf(a, 0) || math.IsInf(b, 0) || math.IsNaN(err) {
+		return math.NaN()
+	}
+	if a == b {
+		return 0
+	}
+
+	h := b - a
+	prev := []float64{h * (f(a) + f(b)) / 2}
+	for level := 1; level <= rombergMaxLevels; level++ {
+		// Add the midpoints of the previous grid
+		h /= 2
+		sum := 0.0
+		n := 1 << uint(level-1)
+		for i := 0; i < n; i++ {
+			sum += f(a + float64(2*i+1)*h)
+		}
+
+		row := make([]float64, level+1)
+		row[0] = prev[0]/2 + h*sum
+		factor := 1.0
+		for k := 1; k <= level; k++ {
+			factor *= 4
+			row[k] = row[k-1] + (row[k-1]-prev[k-1])/(factor-1)
+		}
+
+		if level >= rombergMinLevels && math.Abs(row[level]-prev[level-1]) < err {
+			return row[level]
+		}
+		prev = row
+	}
+
+	return prev[len(prev)-1]
+}
@@ -0,0 +1,38 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRomberg(t *testing.T) {
+	const tol = 1e-10
+
+	cases := []struct {
+		f       Function
+		a, b    float64
+		correct float64
+	}{
+		{math.Exp, 0, 1, math.E - 1},
+		{math.Exp, 1, 0, 1 - math.E},
+		{math.Sin, 0, math.Pi, 2},
+		{func(x float64) float64 { return 1 / (1 + x*x) }, 0, 1, math.Pi / 4},
+		{func(x float64) float64 { return x * x * x }, -2, 3, (81 - 16) / 4.0},
+	}
+
+	for i, c := range cases {
+		if msg, ok := checkValue(Romberg(c.f, c.a, c.b, tol), c.correct, tol); !ok {
+			t.Errorf("case %d: %s", i, msg)
+		}
+		if msg, ok := checkValue(Romberg(c.f, c.a, c.b, tol), Integrate(c.f, c.a, c.b, tol), 2*tol); !ok {
+			t.Errorf("case %d against Integrate: %s", i, msg)
+		}
+	}
+
+	if !math.IsNaN(Romberg(math.Exp, math.Inf(-1), 0, tol)) {
+		t.Error("expected NaN for an infinite bound")
+	}
+	if !math.IsNaN(Romberg(math.Exp, 0, 1, math.NaN())) {
+		t.Error("expected NaN for a NaN tolerance")
+	}
+}
@@ -0,0 +1,55 @@
+package goint
+
+// A Rule estimates the integral of a function over a panel, along with
+// the absolute error of the estimate, so that an adaptive integrator can
+// decide where to refine. Passed to WithRule, it replaces the
+// Gauss-Kronrod pair of IntegrateGK and the integrators built on it,
+// letting problem-specific or vendor rules drive the adaptive partition
+// without changes to this package. The integrator counts the evaluations
+// of f itself.
+type Rule interface {
+	Estimate(f Function, a, b float64) (value, err float64)
+}
+
+// GaussKronrod returns the Gauss-Kronrod pair with the given number of
+// Kronrod points, one of those accepted by WithKronrodOrder, or nil. Its
+// error estimate is that of QUADPACK.
+func GaussKronrod(points int) Rule {
+	if !kronrodOrders[points] {
+		return nil
+	}
+	return kronrodOrder(points)
+}
+
+// Estimate implements Rule.
+func (r *kronrodRule) Estimate(f Function, a, b float64) (value, err float64) {
+	return r.panel(f, a, b)
+}
+
+// GaussKronrodNodes returns the nodes of the Gauss-Kronrod pair with the
+// given number of Kronrod points on [-1, 1], in increasing order, with
+// the Kronrod weights and the weights of the embedded Gauss rule, which
+// are zero at the nodes of the Kronrod extension alone. All three are nil
+// for an unsupported number of points.
+func GaussKronrodNodes(points int) (nodes, kronrod, gauss []float64) {
+	if !kronrodOrders[points] {
+		return nil, nil, nil
+	}
+
+	r := kronrodOrder(points)
+	last := len(r.nodes) - 1
+	nodes = make([]float64, 0, 2*last+1)
+	kronrod = make([]float64, 0, 2*last+1)
+	gauss = make([]float64, 0, 2*last+1)
+	for i := 0; i < last; i++ {
+		nodes = append(nodes, -r.nodes[i])
+		kronrod = append(kronrod, r.weights[i])
+		gauss = append(gauss, r.gauss[i])
+	}
+	for i := last; i >= 0; i-- {
+		nodes = append(nodes, r.nodes[i])
+		kronrod = append(kronrod, r.weights[i])
+		gauss = append(gauss, r.gauss[i])
+	}
+	return nodes, kronrod, gauss
+}
@@ -0,0 +1,64 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+// A Rule applying Simpson's rule on the panel and its halves, with the
+// Richardson-corrected difference as the error.
+type simpsonPair struct{}
+
+func (simpsonPair) Estimate(f Function, a, b float64) (value, err float64) {
+	whole := Simpson(f, a, b, 1)
+	halves := Simpson(f, a, b, 2)
+	return halves, math.Abs(halves-whole) / 15
+}
+
+func TestCustomRule(t *testing.T) {
+	f := func(x float64) float64 { return 1 / (1e-2 + x*x) }
+	correct := 20 * math.Atan(10)
+
+	r, err := IntegrateGK(f, -1, 1, 1e-9, WithRule(simpsonPair{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg, ok := checkValue(r.Value, correct, 1e-8); !ok {
+		t.Error(msg)
+	}
+
+	// Rules that cannot be recomputed in extended precision are summed
+	// as they are
+	r, err = IntegrateGK(f, -1, 1, 1e-9, WithRule(simpsonPair{}), WithPrecisionCheck())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Rounding > 1e-12 {
+		t.Errorf("rounding %g", r.Rounding)
+	}
+
+	// A nil FixedRule is ignored
+	plain, _ := IntegrateGK(f, -1, 1, 1e-9)
+	r, _ = IntegrateGK(f, -1, 1, 1e-9, WithRule(NewtonCotes(100)))
+	if r != plain {
+		t.Errorf("got %+v, expected %+v", r, plain)
+	}
+}
+
+func TestGaussKronrod(t *testing.T) {
+	f := func(x float64) float64 { return math.Cos(40 * x) }
+	for points := range kronrodOrders {
+		byOrder, _ := IntegrateGK(f, 0, 3, 1e-10, WithKronrodOrder(points))
+		byRule, _ := IntegrateGK(f, 0, 3, 1e-10, WithRule(GaussKronrod(points)), WithPrecisionCheck())
+		if byRule.Value != byOrder.Value || byRule.Panels != byOrder.Panels {
+			t.Errorf("%d points: got %+v, expected %+v", points, byRule, byOrder)
+		}
+		if byRule.Rounding > 1e-13 {
+			t.Errorf("%d points: rounding %g", points, byRule.Rounding)
+		}
+	}
+
+	if GaussKronrod(17) != nil {
+		t.Error("expected nil rule")
+	}
+}
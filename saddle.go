@@ -0,0 +1,67 @@
+package goint
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+const (
+	// The most Newton steps taken when locating a saddle.
+	saddleSteps = 100
+
+	// The deformed contour extends until the Gaussian approximation of the
+	// integrand has decayed by exp(-saddleDecay).
+	saddleDecay = 40
+)
+
+// SaddlePoint estimates the integral of h(z) exp(lambda g(z)) along a
+// contour through the real line, for large lambda, by the method of
+// steepest descent. The saddle of g, where g' vanishes, is located by
+// Newton's method from z0 using the supplied derivatives dg and d2g. The
+// contour is then deformed locally into the line through the saddle along
+// the direction of steepest descent, oriented left to right, and the
+// integrand is integrated numerically along it to within tol relative to
+// exp(lambda g) at the saddle. The value is returned with the saddle.
+//
+// Contributions away from the saddle, such as those of the original
+// contour's endpoints, are assumed to be exponentially smaller and are
+// neglected. If Newton's method fails, both results are NaN.
+func SaddlePoint(h, g, dg, d2g ComplexFunction, z0 complex128, lambda, tol float64) (value, saddle complex128) {
+	z := z0
+	converged := false
+	for i := 0; i < saddleSteps; i++ {
+		step := dg(z) / d2g(z)
+		z -= step
+		if cmplx.IsNaN(z) || cmplx.IsInf(z) {
+			break
+		}
+		if cmplx.Abs(step) <= 1e-14*math.Max(1, cmplx.Abs(z)) {
+			converged = true
+			break
+		}
+	}
+	if !converged {
+		return cmplx.NaN(), cmplx.NaN()
+	}
+
+	// g(z) ~ g(s) + g''(s) (z - s)^2 / 2 decreases fastest along the
+	// direction theta with g''(s) exp(2 i theta) negative real
+	g2 := d2g(z)
+	theta := (math.Pi - cmplx.Phase(g2)) / 2
+	if math.Cos(theta) < 0 {
+		theta -= math.Pi
+	}
+	dir := cmplx.Rect(1, theta)
+	width := math.Sqrt(2 * saddleDecay / (lambda * cmplx.Abs(g2)))
+
+	gs := g(z)
+	s := z
+	f := func(w complex128) complex128 {
+		return h(w) * cmplx.Exp(complex(lambda, 0)*(g(w)-gs))
+	}
+	path := func(t float64) (complex128, complex128) {
+		return s + complex(t, 0)*dir, dir
+	}
+
+	return ContourIntegral(f, path, -width, width, tol/2) * cmplx.Exp(complex(lambda, 0)*gs), s
+}
@@ -0,0 +1,48 @@
+package goint
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+)
+
+func TestContourIntegral(t *testing.T) {
+	// The integral of z^2 from 0 to 1 + i is independent of the path
+	square := func(z complex128) complex128 { return z * z }
+	got := ContourIntegral(square, LinePath(0, 1+1i), 0, 1, 1e-12)
+	want := cmplx.Pow(1+1i, 3) / 3
+	if d := cmplx.Abs(got - want); d > 1e-11 {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestSaddlePoint(t *testing.T) {
+	// g(z) = i z - z^2 / 2 has its saddle at i; along the real line,
+	// exp(lambda g) integrates to sqrt(2 pi / lambda) exp(-lambda / 2)
+	g := func(z complex128) complex128 { return 1i*z - z*z/2 }
+	dg := func(z complex128) complex128 { return 1i - z }
+	d2g := func(z complex128) complex128 { return -1 }
+	lambda := 20.0
+	scale := math.Sqrt(2*math.Pi/lambda) * math.Exp(-lambda/2)
+
+	one := func(z complex128) complex128 { return 1 }
+	v, s := SaddlePoint(one, g, dg, d2g, 0, lambda, 1e-10)
+	if cmplx.Abs(s-1i) > 1e-12 {
+		t.Errorf("saddle %v, want i", s)
+	}
+	if d := cmplx.Abs(v - complex(scale, 0)); d > 1e-10*scale {
+		t.Errorf("got %v, want %v", v, scale)
+	}
+
+	// With h(z) = z the integral is i times as large
+	v, _ = SaddlePoint(func(z complex128) complex128 { return z }, g, dg, d2g, 0, lambda, 1e-10)
+	if d := cmplx.Abs(v - complex(0, scale)); d > 1e-10*scale {
+		t.Errorf("got %v, want %v", v, complex(0, scale))
+	}
+
+	// Newton's method cannot start where g'' vanishes
+	v, _ = SaddlePoint(one, g, dg, func(z complex128) complex128 { return 0 }, 0, lambda, 1e-10)
+	if !cmplx.IsNaN(v) {
+		t.Errorf("expected NaN, got %v", v)
+	}
+}
@@ -0,0 +1,74 @@
+package goint
+
+import (
+	"math"
+	"sort"
+)
+
+// The number of panels SampleAdaptive starts from, so that features are not
+// missed entirely by the first round of evaluations.
+const samplePanels = 16
+
+// SampleAdaptive returns a sampling of f over the finite interval [a, b]
+// suitable for plotting. The interval is refined with the adaptive engine
+// until f deviates from linear interpolation across every panel by at most
+// tol, so that sharp features are resolved while flat regions stay coarse.
+// The abscissae are returned in increasing order along with f at each. If
+// either bound is not finite, both slices are nil.
+func SampleAdaptive(f Function, a, b float64, tol float64) (xs, ys []float64) {
+	if math.IsInf(a, 0) || math.IsInf(b, 0) || math.IsNaN(a) || math.IsNaN(b) {
+		return nil, nil
+	}
+	if a > b {
+		a, b = b, a
+	}
+
+	values := make(map[float64]float64)
+	g := func(x float64) float64 {
+		if y, ok := values[x]; ok {
+			return y
+		}
+		y := f(x)
+		values[x] = y
+		return y
+	}
+
+	points := make([]float64, samplePanels+1)
+	for i := range points {
+		points[i] = a + (b-a)*float64(i)/samplePanels
+	}
+	points[samplePanels] = b
+
+	done := func(value, total, worst float64) bool { return worst <= tol }
+	adapt(g, points, linearPanel, maxPanels, nil, done)
+
+	xs = make([]float64, 0, len(values))
+	for x := range values {
+		xs = append(xs, x)
+	}
+	sort.Float64s(xs)
+
+	ys = make([]float64, len(xs))
+	for i, x := range xs {
+		ys[i] = values[x]
+	}
+
+	return xs, ys
+}
+
+// Estimates the integral over [a, b] with Boole's rule, reporting as the
+// error the largest deviation of f from the chord between the endpoints.
+func linearPanel(f Function, a, b float64) (float64, float64) {
+	h := (b - a) / 4.0
+	fa := f(a)
+	fb := f(b)
+	slope := (fb - fa) / (b - a)
+
+	dev := 0.0
+	for i := 1; i <= 3; i++ {
+		x := a + float64(i)*h
+		dev = math.Max(dev, math.Abs(f(x)-(fa+slope*(x-a))))
+	}
+
+	return boolesrule(f, a, b), dev
+}
@@ -0,0 +1,47 @@
+package goint
+
+import (
+	"math"
+	"sort"
+	"testing"
+)
+
+func TestSampleAdaptive(t *testing.T) {
+	const (
+		tol = 1e-3
+	)
+
+	// A narrow bump near x = 1 on an otherwise flat function
+	f := func(x float64) float64 {
+		d := (x - 1) / .1
+		return math.Exp(-d * d)
+	}
+
+	xs, ys := SampleAdaptive(f, -5, 5, tol)
+	if len(xs) != len(ys) || len(xs) < 5 {
+		t.Fatalf("got %d abscissae and %d values", len(xs), len(ys))
+	}
+	if !sort.Float64sAreSorted(xs) || xs[0] != -5 || xs[len(xs)-1] != 5 {
+		t.Errorf("abscissae do not span [-5, 5] in order")
+	}
+
+	// The bump should be resolved much more finely than the flat region
+	near, far := 0, 0
+	for i, x := range xs {
+		if ys[i] != f(x) {
+			t.Errorf("ys[%d] = %g, want f(%g) = %g", i, ys[i], x, f(x))
+		}
+		if math.Abs(x-1) < .2 {
+			near++
+		} else if math.Abs(x+3) < .2 {
+			far++
+		}
+	}
+	if near <= 10*(far+1) {
+		t.Errorf("%d samples near the bump and %d in a flat region", near, far)
+	}
+
+	if xs, ys := SampleAdaptive(f, 0, math.Inf(1), tol); xs != nil || ys != nil {
+		t.Errorf("expected no samples over an infinite interval")
+	}
+}
@@ -0,0 +1,140 @@
+package goint
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// ErrIncompleteMerge is returned by Merge when the partial results do not
+// cover every Subproblem of a Split exactly once.
+var ErrIncompleteMerge = errors.New("goint: partial results do not cover the domain")
+
+// A Domain is the hyperrectangle whose i-th axis runs from Lower[i] to
+// Upper[i]; a one-dimensional domain has slices of length one. Bounds may
+// be infinite.
+type Domain struct {
+	Lower, Upper []float64
+}
+
+// A Subproblem is one shard of a Domain produced by Split: its own
+// Domain, to be integrated to within Tol, for example by IntegrateGK or
+// IntegrateCubature, as the Index-th of Count shards. It holds only plain
+// values, so it can be serialized and sent to another machine.
+type Subproblem struct {
+	Domain       Domain
+	Tol          float64
+	Index, Count int
+}
+
+// A PartialResult is the outcome of integrating a Subproblem: its Result,
+// and whether the integrator met the Subproblem's tolerance.
+type PartialResult struct {
+	Subproblem Subproblem
+	Result     Result
+	Converged  bool
+}
+
+// Split divides the integral over domain to within tol into n
+// subproblems whose results Merge recombines. The domain is cut into n
+// slabs along its widest axis, counting an infinite axis as wider than
+// any finite one. A finite axis is cut into equal widths; an infinite
+// one is cut where the substitution x = t/(1-t), or t/(1-t^2) for the
+// whole line, maps equal widths of t, so that the outer slabs reach to
+// infinity. Each slab is allotted tol/n, so the errors of the shards sum
+// to within tol. Split returns nil if n < 1, the bounds are NaN, reversed
+// or of different lengths, or tol is NaN or negative.
+func Split(domain Domain, tol float64, n int) []Subproblem {
+	dim := len(domain.Lower)
+	if n < 1 || dim == 0 || len(domain.Upper) != dim || !(tol >= 0) {
+		return nil
+	}
+	axis, width := 0, -1.0
+	for i := 0; i < dim; i++ {
+		a, b := domain.Lower[i], domain.Upper[i]
+		if !(a <= b) {
+			return nil
+		}
+		if w := b - a; w > width {
+			axis, width = i, w
+		}
+	}
+
+	a, b := domain.Lower[axis], domain.Upper[axis]
+	cuts := make([]float64, 0, n+1)
+	switch {
+	case math.IsInf(a, -1) && math.IsInf(b, 1):
+		x := uncompact(a, b)
+		for k := 1; k < n; k++ {
+			cuts = append(cuts, x(-1+2*float64(k)/float64(n)))
+		}
+	case math.IsInf(a, 0) || math.IsInf(b, 0):
+		x := uncompact(a, b)
+		for k := 1; k < n; k++ {
+			cuts = append(cuts, x(float64(k)/float64(n)))
+		}
+	default:
+		for k := 1; k < n; k++ {
+			cuts = append(cuts, a+(b-a)*float64(k)/float64(n))
+		}
+	}
+	sort.Float64s(cuts)
+	cuts = append(append([]float64{a}, cuts...), b)
+
+	shards := make([]Subproblem, n)
+	for k := range shards {
+		lower := append([]float64(nil), domain.Lower...)
+		upper := append([]float64(nil), domain.Upper...)
+		lower[axis], upper[axis] = cuts[k], cuts[k+1]
+		shards[k] = Subproblem{Domain{lower, upper}, tol / float64(n), k, n}
+	}
+
+	return shards
+}
+
+// Merge recombines the partial results of the subproblems of one Split
+// into the Result for the whole domain. Values, error estimates, bounds
+// and counts are summed in the order of the shards, so the result does
+// not depend on the order in which they arrive; the value and error are
+// summed pairwise. Merge returns ErrIncompleteMerge, with a NaN value, if
+// a shard is missing or repeated, and ErrNotConverged if any shard did
+// not converge.
+func Merge(parts []PartialResult) (Result, error) {
+	if len(parts) == 0 {
+		return Result{Value: math.NaN()}, ErrIncompleteMerge
+	}
+	count := parts[0].Subproblem.Count
+	ordered := make([]*PartialResult, count)
+	for i := range parts {
+		p := &parts[i]
+		k := p.Subproblem.Index
+		if p.Subproblem.Count != count || k < 0 || k >= count || ordered[k] != nil {
+			return Result{Value: math.NaN()}, ErrIncompleteMerge
+		}
+		ordered[k] = p
+	}
+	if len(parts) != count {
+		return Result{Value: math.NaN()}, ErrIncompleteMerge
+	}
+
+	var r Result
+	values := make([]float64, count)
+	errs := make([]float64, count)
+	converged := true
+	for k, p := range ordered {
+		values[k], errs[k] = p.Result.Value, p.Result.Error
+		r.Evals += p.Result.Evals
+		r.Panels += p.Result.Panels
+		r.Precise += p.Result.Precise
+		r.Rounding += p.Result.Rounding
+		r.Lower += p.Result.Lower
+		r.Upper += p.Result.Upper
+		converged = converged && p.Converged
+	}
+	r.Value, r.Error = pairwiseSum(values), pairwiseSum(errs)
+
+	if !converged {
+		return r, ErrNotConverged
+	}
+	return r, nil
+}
@@ -0,0 +1,61 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSplitMerge(t *testing.T) {
+	const tol = 1e-9
+
+	// A one-dimensional integral over the whole line
+	f := func(x float64) float64 { return math.Exp(-x * x) }
+	inf := math.Inf(1)
+	shards := Split(Domain{[]float64{-inf}, []float64{inf}}, tol, 5)
+	if len(shards) != 5 || !math.IsInf(shards[0].Domain.Lower[0], -1) || !math.IsInf(shards[4].Domain.Upper[0], 1) {
+		t.Fatalf("bad shards %v", shards)
+	}
+	parts := make([]PartialResult, len(shards))
+	for i, s := range shards {
+		r, err := IntegrateGK(f, s.Domain.Lower[0], s.Domain.Upper[0], s.Tol)
+		// Deliver the results out of order
+		parts[len(parts)-1-i] = PartialResult{s, r, err == nil}
+	}
+	r, err := Merge(parts)
+	if err != nil {
+		t.Error(err)
+	}
+	if msg, ok := checkValue(r.Value, math.Sqrt(math.Pi), tol); !ok {
+		t.Error(msg)
+	}
+
+	// A two-dimensional integral split along its wider axis
+	g := func(x []float64) float64 { return math.Exp(x[0] + x[1]) }
+	shards = Split(Domain{[]float64{0, 0}, []float64{1, 3}}, tol, 3)
+	parts = parts[:0]
+	for _, s := range shards {
+		if s.Domain.Upper[0] != 1 {
+			t.Errorf("shard %d split the narrower axis", s.Index)
+		}
+		r, err := IntegrateCubature(g, s.Domain.Lower, s.Domain.Upper, s.Tol)
+		parts = append(parts, PartialResult{s, r, err == nil})
+	}
+	r, err = Merge(parts)
+	if err != nil {
+		t.Error(err)
+	}
+	if msg, ok := checkValue(r.Value, (math.E-1)*(math.Exp(3)-1), 10*tol); !ok {
+		t.Error(msg)
+	}
+
+	if _, err := Merge(parts[:2]); err != ErrIncompleteMerge {
+		t.Errorf("missing shard gave %v", err)
+	}
+	parts[1].Converged = false
+	if _, err := Merge(parts); err != ErrNotConverged {
+		t.Errorf("unconverged shard gave %v", err)
+	}
+	if Split(Domain{[]float64{1}, []float64{0}}, tol, 2) != nil {
+		t.Error("expected nil for reversed bounds")
+	}
+}
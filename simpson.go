@@ -0,0 +1,59 @@
+package goint
+
+import (
+	"math"
+)
+
+const (
+	// The deepest bisection AdaptiveSimpson performs.
+	simpsonMaxDepth = 50
+
+	// The most evaluations AdaptiveSimpson makes; once they are spent,
+	// every remaining panel is accepted as it stands.
+	simpsonMaxEvals = 1 << 22
+)
+
+// AdaptiveSimpson integrates f over [a, b] to within tol by the classic
+// recursive bisection scheme: Simpson's rule on a panel is compared with
+// Simpson's rule on its two halves, and the panel is accepted, with the
+// Richardson correction (S2 - S1)/15 applied, once the difference is
+// within its share of the tolerance. Endpoint and midpoint evaluations are
+// passed down, so each bisection costs two new evaluations, and converged
+// panels are never revisited. Either limit may be infinite, in which case
+// the interval is first mapped onto a finite one. The result is NaN if a
+// bound, tol or the integrand is NaN.
+func AdaptiveSimpson(f Function, a, b, tol float64) float64 {
+	if math.IsNaN(a) || math.IsNaN(b) || math.IsNaN(tol) {
+		return math.NaN()
+	}
+	if a > b {
+		return -AdaptiveSimpson(f, b, a, tol)
+	}
+	g, lo, hi := compactify(f, a, b)
+
+	m := lo + (hi-lo)/2
+	fa, fm, fb := g(lo), g(m), g(hi)
+	whole := (hi - lo) * (fa + 4*fm + fb) / 6
+
+	budget := simpsonMaxEvals - 3
+	return simpsonRecurse(g, lo, m, hi, fa, fm, fb, whole, tol, 0, &budget)
+}
+
+// Returns the integral over [a, b], with midpoint m and the given
+// evaluations and Simpson estimate over the whole panel, drawing
+// evaluations from budget.
+func simpsonRecurse(f Function, a, m, b, fa, fm, fb, whole, tol float64, depth int, budget *int) float64 {
+	lm, rm := a+(m-a)/2, m+(b-m)/2
+	flm, frm := f(lm), f(rm)
+	*budget -= 2
+	left := (m - a) * (fa + 4*flm + fm) / 6
+	right := (b - m) * (fm + 4*frm + fb) / 6
+	diff := left + right - whole
+
+	if depth >= simpsonMaxDepth || *budget <= 0 || !(math.Abs(diff) > 15*tol) || lm <= a || rm >= b {
+		return left + right + diff/15
+	}
+
+	return simpsonRecurse(f, a, lm, m, fa, flm, fm, left, tol/2, depth+1, budget) +
+		simpsonRecurse(f, m, rm, b, fm, frm, fb, right, tol/2, depth+1, budget)
+}
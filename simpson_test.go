@@ -0,0 +1,48 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAdaptiveSimpson(t *testing.T) {
+	const tol = 1e-9
+
+	inf := math.Inf(1)
+	cases := []struct {
+		f       Function
+		a, b    float64
+		correct float64
+	}{
+		{math.Exp, 0, 1, math.E - 1},
+		{math.Exp, 1, 0, 1 - math.E},
+		{func(x float64) float64 { return math.Sqrt(x) }, 0, 1, 2.0 / 3},
+		{func(x float64) float64 { return 1 / (1e-4 + x*x) }, -1, 1, 200 * math.Atan(100)},
+		{func(x float64) float64 { return math.Exp(-x) }, 0, inf, 1},
+	}
+
+	for i, c := range cases {
+		if msg, ok := checkValue(AdaptiveSimpson(c.f, c.a, c.b, tol), c.correct, 10*tol); !ok {
+			t.Errorf("case %d: %s", i, msg)
+		}
+	}
+
+	// A localized feature costs far fewer evaluations than under global
+	// refinement
+	evals := 0
+	peak := func(x float64) float64 {
+		evals++
+		return 1 / (1e-4 + x*x)
+	}
+	Integrate(peak, -1, 1, 1e-6)
+	global := evals
+	evals = 0
+	AdaptiveSimpson(peak, -1, 1, 1e-6)
+	if evals*4 > global {
+		t.Errorf("%d evaluations against %d for Integrate", evals, global)
+	}
+
+	if !math.IsNaN(AdaptiveSimpson(math.Exp, 0, 1, math.NaN())) {
+		t.Error("expected NaN for a NaN tolerance")
+	}
+}
@@ -0,0 +1,81 @@
+package goint
+
+import (
+	"math"
+	"sort"
+)
+
+// Estimates the effective smoothness of a function from its values at
+// the increasing points xs spanning a panel of half-width half: the rate
+// at which its divided differences, scaled by half^k, decay with the order
+// k from m to 2m, for 2m + 1 or 2m + 2 points. A function well resolved by
+// polynomials on the panel has scaled differences near half^k f^(k) / k!,
+// so the rate is small, growing towards one as the panel becomes too wide
+// to resolve it. Kinks, jumps and noise make the differences grow like
+// the inverse spacing to the power k, so the rate is well above one.
+// Returns 0 if the differences of order m vanish, as for polynomials of
+// lower degree.
+func smoothness(xs, values []float64, half float64) float64 {
+	diffs := append([]float64(nil), values...)
+	m := (len(xs) - 1) / 2
+	var middle, last float64
+	for k := 1; k <= 2*m; k++ {
+		last = 0
+		for i := 0; i < len(diffs)-k; i++ {
+			diffs[i] = (diffs[i+1] - diffs[i]) / (xs[i+k] - xs[i]) * half
+			last = math.Max(last, math.Abs(diffs[i]))
+		}
+		if k == m {
+			middle = last
+		}
+	}
+
+	if middle == 0 {
+		return 0
+	}
+	return math.Pow(last/middle, 1/float64(m))
+}
+
+// The thresholds used by autoPanel: the relative error below which the
+// 15-point estimate is taken as resolved, and the smoothness rate from
+// which a panel is treated as rough.
+const (
+	autoResolved = 1e-4
+	autoRough    = .7
+)
+
+// Estimates the integral over [a, b] with the 15-point Gauss-Kronrod pair
+// and probes the smoothness of f from the same 15 values. Where f is
+// smooth but the panel too wide to resolve it, the estimate is replaced
+// by that of the 61-point pair, which saves several bisections. Near kinks
+// and other rough features, where higher order gains nothing, and where
+// the 15-point estimate is nearly resolved, it is kept at no extra cost.
+func autoPanel(f Function, a, b float64) (float64, float64) {
+	value, err, _ := autoSelect(f, a, b)
+	return value, err
+}
+
+// Implements autoPanel, also returning the rule whose estimate was kept.
+func autoSelect(f Function, a, b float64) (float64, float64, *kronrodRule) {
+	xs := make([]float64, 0, 15)
+	values := make([]float64, 0, 15)
+	recorded := func(x float64) float64 {
+		fx := f(x)
+		xs = append(xs, x)
+		values = append(values, fx)
+		return fx
+	}
+
+	value, err := gk15.panel(recorded, a, b)
+	if err <= autoResolved*math.Abs(value) {
+		return value, err, gk15
+	}
+
+	sort.Sort(ruleSorter{xs, values})
+	if smoothness(xs, values, (b-a)/2) < autoRough {
+		high := kronrodOrder(61)
+		value, err = high.panel(f, a, b)
+		return value, err, high
+	}
+	return value, err, gk15
+}
@@ -0,0 +1,52 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSmoothness(t *testing.T) {
+	probe := func(f Function, a, b float64) float64 {
+		xs := make([]float64, 9)
+		values := make([]float64, len(xs))
+		for i := range xs {
+			xs[i] = a + (b-a)*float64(i)/float64(len(xs)-1)
+			values[i] = f(xs[i])
+		}
+		return smoothness(xs, values, (b-a)/2)
+	}
+
+	if rate := probe(math.Exp, 0, 1); rate > .2 {
+		t.Errorf("exp has rate %v", rate)
+	}
+	if rate := probe(math.Abs, -1, 1.1); rate < 1 {
+		t.Errorf("|x| has rate %v", rate)
+	}
+	if rate := probe(func(x float64) float64 { return x * x * x }, -3, 7); rate != 0 {
+		t.Errorf("cubic has rate %v", rate)
+	}
+}
+
+func TestWithAutoOrder(t *testing.T) {
+	const tol = 1e-10
+
+	// A kink beside a fast oscillation
+	f := func(x float64) float64 { return math.Abs(x-.5) + math.Sin(300*x) }
+	correct := .25/2 + 1.5*1.5/2 + (1-math.Cos(600))/300
+
+	auto, err := IntegrateGK(f, 0, 2, tol, WithAutoOrder())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg, ok := checkValue(auto.Value, correct, tol); !ok {
+		t.Error(msg)
+	}
+
+	plain, err := IntegrateGK(f, 0, 2, tol)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if auto.Evals >= plain.Evals {
+		t.Errorf("%d evaluations with WithAutoOrder, %d without", auto.Evals, plain.Evals)
+	}
+}
@@ -0,0 +1,67 @@
+package goint
+
+import (
+	"math"
+)
+
+// A LagWindow tapers an autocovariance function at the normalized lag
+// u = |tau| / M, where M is the truncation point. Windows are evaluated
+// for u in [0, 1] and should satisfy w(0) == 1.
+type LagWindow func(u float64) float64
+
+// TruncatedWindow keeps every lag up to the truncation point unchanged.
+func TruncatedWindow(u float64) float64 {
+	return 1
+}
+
+// BartlettWindow is the triangular window 1 - u.
+func BartlettWindow(u float64) float64 {
+	return 1 - u
+}
+
+// TukeyWindow is the Tukey-Hanning window (1 + cos(pi u)) / 2.
+func TukeyWindow(u float64) float64 {
+	return (1 + math.Cos(math.Pi*u)) / 2
+}
+
+// ParzenWindow is the Parzen window, a cubic spline in u.
+func ParzenWindow(u float64) float64 {
+	if u <= .5 {
+		return 1 - 6*u*u + 6*u*u*u
+	}
+	v := 1 - u
+	return 2 * v * v * v
+}
+
+// SpectralDensity estimates
+//
+//	S(omega) = integral over [-M, M] of w(|tau|/M) c(tau) cos(omega tau) dtau
+//
+// for an even autocovariance function c, to within err. No 1/(2 pi)
+// normalization is applied. A nil window is treated as TruncatedWindow.
+// M must be finite and positive; otherwise NaN is returned.
+func SpectralDensity(c Function, w LagWindow, M, omega, err float64) float64 {
+	if !(M > 0) || math.IsInf(M, 1) {
+		return math.NaN()
+	}
+	if w == nil {
+		w = TruncatedWindow
+	}
+
+	windowed := func(tau float64) float64 {
+		return w(tau/M) * c(tau)
+	}
+
+	// c is even, so integrate over [0, M] and double
+	return 2 * CosineTransform(windowed, 0, M, omega, err/2)
+}
+
+// SpectralDensities evaluates SpectralDensity at each frequency in omegas.
+func SpectralDensities(c Function, w LagWindow, M float64, omegas []float64, err float64) []float64 {
+	ret := make([]float64, len(omegas))
+	for i, omega := range omegas {
+		ret[i] = SpectralDensity(c, w, M, omega, err)
+	}
+
+	return ret
+}
@@ -0,0 +1,61 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCosineTransform(t *testing.T) {
+	const (
+		h = 1e-8
+	)
+
+	// The integral of cos(3 x) over [0, 2] is sin(6) / 3
+	one := func(x float64) float64 { return 1 }
+	if msg, ok := checkValue(CosineTransform(one, 0, 2, 3, h), math.Sin(6)/3, h); !ok {
+		t.Error(msg)
+	}
+
+	// The integral of x sin(x) over [0, pi] is pi
+	x := func(x float64) float64 { return x }
+	if msg, ok := checkValue(SineTransform(x, 0, math.Pi, 1, h), math.Pi, h); !ok {
+		t.Error(msg)
+	}
+
+	// Infinite limits are rejected rather than split forever
+	for _, b := range []float64{math.Inf(1), math.Inf(-1), math.NaN()} {
+		if got := CosineTransform(one, 0, b, 3, h); !math.IsNaN(got) {
+			t.Errorf("got %g over [0, %g]", got, b)
+		}
+	}
+}
+
+/* Test the spectral density of an exponential autocovariance */
+func TestSpectralDensity(t *testing.T) {
+	const (
+		h = 1e-6
+		M = 40
+	)
+
+	// The transform of e^(-|tau|) is 2 / (1 + omega^2)
+	c := func(tau float64) float64 { return math.Exp(-math.Abs(tau)) }
+
+	for _, omega := range []float64{0, .5, 2} {
+		got := SpectralDensity(c, nil, M, omega, h)
+		if msg, ok := checkValue(got, 2/(1+omega*omega), 1e-5); !ok {
+			t.Error(msg)
+		}
+	}
+
+	// A Bartlett window with M = 1 on c = 1 integrates the triangle
+	one := func(x float64) float64 { return 1 }
+	if msg, ok := checkValue(SpectralDensity(one, BartlettWindow, 1, 0, h), 1, h); !ok {
+		t.Error(msg)
+	}
+
+	for _, w := range []LagWindow{TruncatedWindow, BartlettWindow, TukeyWindow, ParzenWindow} {
+		if w(0) != 1 {
+			t.Errorf("window has w(0) = %g", w(0))
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package goint
+
+import (
+	"math"
+)
+
+// IntegrateSphere integrates f(theta, phi) over the unit sphere, with theta
+// the polar angle and phi the azimuth, using the product of the n-point
+// Gauss-Legendre rule in cos theta and the 2n-point trapezoid rule in phi.
+// The result is exact for spherical polynomials of degree below 2n, and is
+// NaN if n < 1.
+func IntegrateSphere(f func(theta, phi float64) float64, n int) float64 {
+	if n < 1 {
+		return math.NaN()
+	}
+
+	xs, ws := gaussLegendre(n)
+	sum := 0.0
+	for i, x := range xs {
+		theta := math.Acos(x)
+		ring := 0.0
+		for k := 0; k < 2*n; k++ {
+			ring += f(theta, math.Pi*float64(k)/float64(n))
+		}
+		sum += ws[i] * ring
+	}
+	return sum * math.Pi / float64(n)
+}
+
+// An SHExpansion holds the coefficients of a function on the unit sphere
+// in the orthonormal real spherical harmonics Y_lm, without the
+// Condon-Shortley phase: Y_lm is proportional to P_l^m(cos theta)
+// cos(m phi) for m > 0 and to P_l^|m|(cos theta) sin(|m| phi) for m < 0.
+type SHExpansion struct {
+	// Lmax is the largest degree.
+	Lmax int
+
+	// Coeffs[l][m+l] is the coefficient of Y_lm.
+	Coeffs [][]float64
+
+	// Error is the largest change in any coefficient when the quadrature
+	// grid is doubled, an estimate of aliasing from higher degrees.
+	Error float64
+}
+
+// SHCoefficients computes the spherical harmonic coefficients of f up to
+// degree lmax by quadrature on a Gauss-Legendre by uniform grid that is
+// exact for band-limited f of degree lmax. The coefficients are computed
+// again on a grid twice as fine to report their accuracy.
+func SHCoefficients(f func(theta, phi float64) float64, lmax int) *SHExpansion {
+	if lmax < 0 {
+		return nil
+	}
+
+	coarse := shProject(f, lmax, lmax+1)
+	fine := shProject(f, lmax, 2*(lmax+1))
+
+	e := &SHExpansion{Lmax: lmax, Coeffs: fine}
+	for l := range fine {
+		for i := range fine[l] {
+			e.Error = math.Max(e.Error, math.Abs(fine[l][i]-coarse[l][i]))
+		}
+	}
+	return e
+}
+
+// Projects f onto the harmonics up to lmax using n Gauss-Legendre nodes in
+// cos theta and 2n uniform nodes in phi.
+func shProject(f func(theta, phi float64) float64, lmax, n int) [][]float64 {
+	coeffs := make([][]float64, lmax+1)
+	for l := range coeffs {
+		coeffs[l] = make([]float64, 2*l+1)
+	}
+
+	xs, ws := gaussLegendre(n)
+	nphi := 2 * n
+	ring := make([]float64, nphi)
+	for i, x := range xs {
+		theta := math.Acos(x)
+		for k := range ring {
+			ring[k] = f(theta, 2*math.Pi*float64(k)/float64(nphi))
+		}
+
+		// The Fourier coefficients of the ring, by direct summation
+		cosines := make([]float64, lmax+1)
+		sines := make([]float64, lmax+1)
+		for m := 0; m <= lmax; m++ {
+			for k, v := range ring {
+				phi := 2 * math.Pi * float64(k) / float64(nphi)
+				cosines[m] += v * math.Cos(float64(m)*phi)
+				sines[m] += v * math.Sin(float64(m)*phi)
+			}
+			cosines[m] *= 2 * math.Pi / float64(nphi)
+			sines[m] *= 2 * math.Pi / float64(nphi)
+		}
+
+		p := normalizedLegendre(lmax, x)
+		for l := 0; l <= lmax; l++ {
+			coeffs[l][l] += ws[i] * p[l][0] * cosines[0]
+			for m := 1; m <= l; m++ {
+				coeffs[l][l+m] += ws[i] * math.Sqrt2 * p[l][m] * cosines[m]
+				coeffs[l][l-m] += ws[i] * math.Sqrt2 * p[l][m] * sines[m]
+			}
+		}
+	}
+
+	return coeffs
+}
+
+// Returns p[l][m], the associated Legendre function P_l^m(x) for
+// 0 <= m <= l <= lmax, scaled so that p[l][0] and sqrt(2) p[l][m] cos(m phi)
+// have unit norm over the sphere, computed by the standard stable
+// recurrences.
+func normalizedLegendre(lmax int, x float64) [][]float64 {
+	s := math.Sqrt(math.Max(0, 1-x*x))
+	p := make([][]float64, lmax+1)
+	for l := range p {
+		p[l] = make([]float64, l+1)
+	}
+
+	p[0][0] = math.Sqrt(1 / (4 * math.Pi))
+	for m := 1; m <= lmax; m++ {
+		p[m][m] = math.Sqrt(float64(2*m+1)/float64(2*m)) * s * p[m-1][m-1]
+	}
+	for m := 0; m < lmax; m++ {
+		p[m+1][m] = math.Sqrt(float64(2*m+3)) * x * p[m][m]
+	}
+	for m := 0; m <= lmax; m++ {
+		for l := m + 2; l <= lmax; l++ {
+			lf, mf := float64(l), float64(m)
+			a := math.Sqrt((4*lf*lf - 1) / (lf*lf - mf*mf))
+			b := math.Sqrt(((lf-1)*(lf-1) - mf*mf) / (4*(lf-1)*(lf-1) - 1))
+			p[l][m] = a * (x*p[l-1][m] - b*p[l-2][m])
+		}
+	}
+
+	return p
+}
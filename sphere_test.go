@@ -0,0 +1,63 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestIntegrateSphere(t *testing.T) {
+	one := func(theta, phi float64) float64 { return 1 }
+	if msg, ok := checkValue(IntegrateSphere(one, 1), 4*math.Pi, 1e-14); !ok {
+		t.Error(msg)
+	}
+
+	// z^2 x^2 integrates to 4 pi / 15
+	zx := func(theta, phi float64) float64 {
+		z, x := math.Cos(theta), math.Sin(theta)*math.Cos(phi)
+		return z * z * x * x
+	}
+	if msg, ok := checkValue(IntegrateSphere(zx, 3), 4*math.Pi/15, 1e-14); !ok {
+		t.Error(msg)
+	}
+}
+
+func TestSHCoefficients(t *testing.T) {
+	// Y_21 = sqrt(15 / 4 pi) sin theta cos theta cos phi, plus a constant
+	f := func(theta, phi float64) float64 {
+		return math.Sqrt(15/(4*math.Pi))*math.Sin(theta)*math.Cos(theta)*math.Cos(phi) + 3
+	}
+
+	e := SHCoefficients(f, 4)
+	for l := 0; l <= 4; l++ {
+		for m := -l; m <= l; m++ {
+			want := 0.0
+			switch {
+			case l == 0:
+				want = 3 * math.Sqrt(4*math.Pi)
+			case l == 2 && m == 1:
+				want = 1
+			}
+			if msg, ok := checkValue(e.Coeffs[l][m+l], want, 1e-13); !ok {
+				t.Errorf("l = %d, m = %d: %s", l, m, msg)
+			}
+		}
+	}
+	if e.Error > 1e-13 {
+		t.Errorf("error %g for a band-limited function", e.Error)
+	}
+
+	// Orthonormality, through the harmonics' own coefficients
+	y := func(theta, phi float64) float64 {
+		return math.Sqrt2 * normalizedLegendre(3, math.Cos(theta))[3][2] * math.Sin(2*phi)
+	}
+	e = SHCoefficients(y, 3)
+	if msg, ok := checkValue(e.Coeffs[3][1], 1, 1e-13); !ok {
+		t.Error(msg)
+	}
+
+	// A function that is not band-limited reports aliasing
+	e = SHCoefficients(func(theta, phi float64) float64 { return math.Exp(3 * math.Cos(theta)) }, 2)
+	if e.Error < 1e-6 {
+		t.Errorf("error %g should reflect aliasing", e.Error)
+	}
+}
@@ -0,0 +1,92 @@
+package goint
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// A taskRange is a double-ended queue over a contiguous range of task
+// indices [head, tail), packed into one word so that its owner taking
+// from the front and thieves taking from the back can both proceed with a
+// single compare-and-swap.
+type taskRange struct {
+	bounds uint64
+}
+
+func newTaskRange(head, tail int) *taskRange {
+	return &taskRange{uint64(head)<<32 | uint64(tail)}
+}
+
+// Takes the first task, for the owning worker.
+func (r *taskRange) pop() (int, bool) {
+	for {
+		old := atomic.LoadUint64(&r.bounds)
+		head, tail := old>>32, old&0xffffffff
+		if head >= tail {
+			return 0, false
+		}
+		if atomic.CompareAndSwapUint64(&r.bounds, old, (head+1)<<32|tail) {
+			return int(head), true
+		}
+	}
+}
+
+// Takes the last task, for a worker whose own range is exhausted.
+func (r *taskRange) steal() (int, bool) {
+	for {
+		old := atomic.LoadUint64(&r.bounds)
+		head, tail := old>>32, old&0xffffffff
+		if head >= tail {
+			return 0, false
+		}
+		if atomic.CompareAndSwapUint64(&r.bounds, old, head<<32|(tail-1)) {
+			return int(tail - 1), true
+		}
+	}
+}
+
+// Runs task(i, w) for every i in [0, n) on up to workers goroutines, where
+// w identifies the worker running the task. Each worker starts with a
+// contiguous share of the tasks and, once that is exhausted, steals from
+// the back of the others' shares, so uneven task costs do not leave
+// workers idle. If workers is not positive, GOMAXPROCS workers are used.
+// Tasks must write their results to disjoint locations.
+func parallelFor(n, workers int, task func(i, w int)) {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > n {
+		workers = n
+	}
+	if workers <= 1 {
+		for i := 0; i < n; i++ {
+			task(i, 0)
+		}
+		return
+	}
+
+	ranges := make([]*taskRange, workers)
+	for w := range ranges {
+		ranges[w] = newTaskRange(w*n/workers, (w+1)*n/workers)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func(w int) {
+			defer wg.Done()
+			for {
+				i, ok := ranges[w].pop()
+				for v := 1; !ok && v < workers; v++ {
+					i, ok = ranges[(w+v)%workers].steal()
+				}
+				if !ok {
+					return
+				}
+				task(i, w)
+			}
+		}(w)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,146 @@
+package goint
+
+import (
+	"math"
+)
+
+// The step of the trapezoid rule in the double exponential variable used
+// by GaussWeighted to discretize the weight.
+const stieltjesStep = 1.0 / 64
+
+// GaussWeighted returns the n-point Gaussian rule for integrals over
+// [a, b], either of which may be infinite, against the nonnegative weight
+// w, or nil if n < 1, a >= b or the rule cannot be formed. This extends
+// the classical rules, GaussLaguerre, GaussJacobi and the like, to any
+// measure. The weight is discretized by the trapezoid rule after the
+// double exponential substitution of TanhSinh, with about a thousand
+// nodes, which is exact to rounding for weights analytic inside the
+// interval, including those with algebraic or logarithmic singularities
+// at its ends. As for TanhSinh, though, a singularity at a nonzero end is
+// resolved only as far as w can be computed from the rounded x, so such
+// singularities are best placed at zero. The discretized Stieltjes
+// procedure then builds the recurrence of the orthogonal polynomials, from
+// which the Golub-Welsch algorithm finds the nodes and weights. Weights
+// with kinks or jumps inside the interval are discretized only roughly; n
+// should stay well below a hundred.
+func GaussWeighted(w Function, a, b float64, n int) *WeightedRule {
+	if n < 1 || !(a < b) {
+		return nil
+	}
+
+	phi := conformalMap(a, b, nativeMath)
+	var xs, ws []float64
+	steps := int(conformalMaxT / stieltjesStep)
+	for k := -steps; k <= steps; k++ {
+		x, dx := phi(float64(k) * stieltjesStep)
+		if !(x > a && x < b) || dx == 0 || math.IsInf(dx, 0) {
+			continue
+		}
+		if mass := stieltjesStep * dx * w(x); mass > 0 && !math.IsInf(mass, 0) {
+			xs = append(xs, x)
+			ws = append(ws, mass)
+		}
+	}
+	if len(xs) <= n {
+		return nil
+	}
+
+	alpha, beta, mu0 := stieltjes(xs, ws, n)
+	return gaussFromRecurrence(alpha, beta, mu0, w)
+}
+
+// GaussFromMoments returns the Gaussian rule with len(moments) / 2 nodes
+// for the measure whose ordinary moments, the integrals of x^k against
+// it, are moments[k], or nil if there are fewer than two moments or they
+// are not those of a positive measure. The recurrence of the orthogonal
+// polynomials is found by Chebyshev's algorithm. The map from moments to
+// rules is badly conditioned, losing roughly a digit per node for
+// measures on [-1, 1] and faster elsewhere, so this suits small rules
+// from exactly known moments; GaussWeighted is stable given the weight.
+// The rule has no weight function, so ApplyFull gives NaN.
+func GaussFromMoments(moments []float64) *WeightedRule {
+	n := len(moments) / 2
+	if n < 1 {
+		return nil
+	}
+
+	alpha := make([]float64, n)
+	beta := make([]float64, n)
+	prev := make([]float64, 2*n)
+	sigma := append([]float64(nil), moments[:2*n]...)
+	alpha[0], beta[0] = sigma[1]/sigma[0], sigma[0]
+	for k := 1; k < n; k++ {
+		next := make([]float64, 2*n)
+		for l := k; l < 2*n-k; l++ {
+			next[l] = sigma[l+1] - alpha[k-1]*sigma[l] - beta[k-1]*prev[l]
+		}
+		alpha[k] = next[k+1]/next[k] - sigma[k]/sigma[k-1]
+		beta[k] = next[k] / sigma[k-1]
+		prev, sigma = sigma, next
+	}
+
+	return gaussFromRecurrence(alpha, beta, moments[0], nil)
+}
+
+// Computes the first n recurrence coefficients of the monic polynomials
+// orthogonal with respect to the discrete measure with masses ws at the
+// points xs by the Stieltjes procedure, carried out on the orthonormal
+// polynomials so that they neither overflow nor underflow, along with
+// the total mass.
+func stieltjes(xs, ws []float64, n int) (alpha, beta []float64, mu0 float64) {
+	alpha = make([]float64, n)
+	beta = make([]float64, n)
+	for _, m := range ws {
+		mu0 += m
+	}
+
+	q := make([]float64, len(xs))
+	prev := make([]float64, len(xs))
+	for i := range q {
+		q[i] = 1 / math.Sqrt(mu0)
+	}
+	beta[0] = mu0
+	for k := 0; k < n; k++ {
+		for i, x := range xs {
+			alpha[k] += ws[i] * x * q[i] * q[i]
+		}
+		if k == n-1 {
+			break
+		}
+
+		norm := 0.0
+		root := 0.0
+		if k > 0 {
+			root = math.Sqrt(beta[k])
+		}
+		for i, x := range xs {
+			prev[i] = (x-alpha[k])*q[i] - root*prev[i]
+			norm += ws[i] * prev[i] * prev[i]
+		}
+		beta[k+1] = norm
+		scale := 1 / math.Sqrt(norm)
+		for i := range prev {
+			prev[i] *= scale
+		}
+		q, prev = prev, q
+	}
+
+	return alpha, beta, mu0
+}
+
+// Returns the Gaussian rule with weight w for the recurrence coefficients
+// alpha and beta and total mass mu0, or nil unless they are those of a
+// positive measure.
+func gaussFromRecurrence(alpha, beta []float64, mu0 float64, w Function) *WeightedRule {
+	if !(mu0 > 0) || math.IsInf(mu0, 0) {
+		return nil
+	}
+	for k := range alpha {
+		if math.IsNaN(alpha[k]) || math.IsInf(alpha[k], 0) || (k > 0 && !(beta[k] > 0)) || math.IsInf(beta[k], 0) {
+			return nil
+		}
+	}
+
+	nodes, weights := golubWelsch(alpha, beta, mu0)
+	return &WeightedRule{nodes: nodes, weights: weights, weight: w}
+}
@@ -0,0 +1,78 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+// Reports whether two rules have the same nodes and weights to within tol.
+func sameRule(t *testing.T, name string, got, want *WeightedRule, tol float64) {
+	t.Helper()
+	if got == nil || len(got.nodes) != len(want.nodes) {
+		t.Fatalf("%s: got %v", name, got)
+	}
+	for i := range want.nodes {
+		if msg, ok := checkValue(got.nodes[i], want.nodes[i], tol); !ok {
+			t.Errorf("%s: node %d: %s", name, i, msg)
+		}
+		if msg, ok := checkValue(got.weights[i], want.weights[i], tol); !ok {
+			t.Errorf("%s: weight %d: %s", name, i, msg)
+		}
+	}
+}
+
+func TestGaussWeighted(t *testing.T) {
+	one := func(x float64) float64 { return 1 }
+	sameRule(t, "Legendre", GaussWeighted(one, -1, 1, 10), GaussJacobi(10, 0, 0, -1, 1), 1e-13)
+
+	sameRule(t, "Laguerre", GaussWeighted(func(x float64) float64 { return math.Exp(-x) }, 0, math.Inf(1), 8),
+		GaussLaguerre(8), 1e-11)
+	sameRule(t, "Hermite", GaussWeighted(func(x float64) float64 { return math.Exp(-x * x) }, math.Inf(-1), math.Inf(1), 12),
+		GaussHermite(12), 1e-12)
+	sameRule(t, "Jacobi", GaussWeighted(func(x float64) float64 { return 1 / math.Sqrt(x) }, 0, 2, 6),
+		GaussJacobi(6, 0, -.5, 0, 2), 1e-12)
+
+	// A non-classical weight with a logarithmic singularity:
+	// the integral of -x^k log x over [0, 1] is 1 / (k + 1)^2
+	r := GaussWeighted(func(x float64) float64 { return -math.Log(x) }, 0, 1, 6)
+	for k := 0; k < 12; k++ {
+		got := r.Apply(func(x float64) float64 { return math.Pow(x, float64(k)) })
+		if msg, ok := checkValue(got, 1/float64((k+1)*(k+1)), 1e-14); !ok {
+			t.Errorf("degree %d: %s", k, msg)
+		}
+	}
+	if msg, ok := checkValue(r.ApplyFull(func(x float64) float64 { return -math.Log(x) * x * x }), 1.0/9, 1e-14); !ok {
+		t.Error(msg)
+	}
+
+	if GaussWeighted(one, 1, 0, 4) != nil || GaussWeighted(one, 0, 1, 0) != nil {
+		t.Error("expected nil rules")
+	}
+	if GaussWeighted(func(x float64) float64 { return -1 }, 0, 1, 4) != nil {
+		t.Error("expected nil rule for a negative weight")
+	}
+}
+
+func TestGaussFromMoments(t *testing.T) {
+	moments := make([]float64, 10)
+	for k := 0; k < len(moments); k += 2 {
+		moments[k] = 2 / float64(k+1)
+	}
+	r := GaussFromMoments(moments)
+	sameRule(t, "Legendre", r, GaussJacobi(5, 0, 0, -1, 1), 1e-12)
+	if !math.IsNaN(r.ApplyFull(math.Exp)) {
+		t.Error("expected NaN without a weight function")
+	}
+
+	// The moments of the Laguerre weight are k!
+	moments = []float64{1, 1, 2, 6, 24, 120, 720, 5040}
+	sameRule(t, "Laguerre", GaussFromMoments(moments), GaussLaguerre(4), 1e-11)
+
+	// Not the moments of a positive measure
+	if GaussFromMoments([]float64{1, 0, -1, 0}) != nil {
+		t.Error("expected nil rule")
+	}
+	if GaussFromMoments([]float64{1}) != nil {
+		t.Error("expected nil rule")
+	}
+}
@@ -0,0 +1,143 @@
+package goint
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"os"
+)
+
+// A Store is a key-value store of the values of an integrand by abscissa,
+// which WithCache consults before evaluating it. Backed by a file or a
+// database, it lets repeated integrations of the same expensive,
+// deterministic integrand reuse evaluations across runs; a Store must
+// only ever hold the values of one integrand.
+type Store interface {
+	// Get returns the value stored for x, and whether there is one.
+	Get(x float64) (y float64, ok bool, err error)
+
+	// Put stores the value y for x.
+	Put(x, y float64) error
+}
+
+// WithCache makes IntegrateGK and IntegrateBatch look each abscissa up in
+// s before evaluating the integrand there, and store the values of those
+// it evaluates. Result.Evals counts only the evaluations, not the values
+// found in s. After the first error from s it is no longer consulted, and
+// the error is returned in place of a nil one.
+func WithCache(s Store) Option {
+	return func(c *config) { c.cache = s }
+}
+
+// A MapStore is a Store held in memory.
+type MapStore map[float64]float64
+
+// Get returns the value stored for x.
+func (m MapStore) Get(x float64) (float64, bool, error) {
+	y, ok := m[x]
+	return y, ok, nil
+}
+
+// Put stores y for x.
+func (m MapStore) Put(x, y float64) error {
+	m[x] = y
+	return nil
+}
+
+// A FileStore is a Store persisted to a file, so that it survives the
+// process. The file holds a record of x and y as little-endian float64s
+// for every value put, appended as it is put; it is read into memory when
+// opened. A FileStore is not safe for concurrent use, including by
+// several processes sharing the file.
+type FileStore struct {
+	values MapStore
+	f      *os.File
+}
+
+// OpenFileStore opens the FileStore in the file at path, creating it if
+// it does not exist. A partial record at the end of the file, left by a
+// process that stopped while writing it, is ignored and overwritten.
+func OpenFileStore(path string) (*FileStore, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &FileStore{values: MapStore{}, f: f}
+	var record [16]byte
+	size := int64(0)
+	for {
+		if _, err := io.ReadFull(f, record[:]); err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		} else if err != nil {
+			f.Close()
+			return nil, err
+		}
+		x := math.Float64frombits(binary.LittleEndian.Uint64(record[:8]))
+		s.values[x] = math.Float64frombits(binary.LittleEndian.Uint64(record[8:]))
+		size += int64(len(record))
+	}
+	if _, err := f.Seek(size, io.SeekStart); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// Get returns the value stored for x.
+func (s *FileStore) Get(x float64) (float64, bool, error) {
+	return s.values.Get(x)
+}
+
+// Put stores y for x, appending it to the file.
+func (s *FileStore) Put(x, y float64) error {
+	var record [16]byte
+	binary.LittleEndian.PutUint64(record[:8], math.Float64bits(x))
+	binary.LittleEndian.PutUint64(record[8:], math.Float64bits(y))
+	if _, err := s.f.Write(record[:]); err != nil {
+		return err
+	}
+	s.values[x] = y
+	return nil
+}
+
+// Len returns the number of values stored.
+func (s *FileStore) Len() int {
+	return len(s.values)
+}
+
+// Close closes the file.
+func (s *FileStore) Close() error {
+	return s.f.Close()
+}
+
+// A cache answers evaluations from a Store where it can.
+type cache struct {
+	s   Store
+	err error
+
+	// Reports whether the values returned by f are not yet real, as while
+	// a batcher is recording, and so must not be stored
+	skip func() bool
+}
+
+// Returns f wrapped to consult and fill the store.
+func (c *cache) record(f Function) Function {
+	return func(x float64) float64 {
+		if c.err != nil {
+			return f(x)
+		}
+		y, ok, err := c.s.Get(x)
+		if c.err = err; ok || c.err != nil {
+			if c.err != nil {
+				return f(x)
+			}
+			return y
+		}
+		y = f(x)
+		if c.skip == nil || !c.skip() {
+			c.err = c.s.Put(x, y)
+		}
+		return y
+	}
+}
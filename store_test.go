@@ -0,0 +1,91 @@
+package goint
+
+import (
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "exp.cache")
+	calls := 0
+	f := func(x float64) float64 {
+		calls++
+		return math.Exp(x)
+	}
+
+	s, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, err := IntegrateGK(f, 0, 1, 1e-12, WithCache(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Len() != first.Evals || calls != first.Evals {
+		t.Errorf("%d stored, %d calls, %d evaluations", s.Len(), calls, first.Evals)
+	}
+	s.Close()
+
+	// A new run, with a partial record left at the end of the file, reuses
+	// every evaluation
+	file, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	file.Write([]byte{1, 2, 3})
+	file.Close()
+	s, err = OpenFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	calls = 0
+	again, err := IntegrateGK(f, 0, 1, 1e-12, WithCache(s))
+	if err != nil || again.Value != first.Value || again.Evals != 0 || calls != 0 {
+		t.Errorf("got %g, %v in %d evaluations", again.Value, err, again.Evals)
+	}
+
+	// Another rule evaluates only the points not yet stored
+	r, _ := IntegrateGK(f, 0, 1, 1e-12, WithCache(s), WithKronrodOrder(21))
+	if r.Evals != calls || s.Len() != first.Evals+calls {
+		t.Errorf("%d stored after %d more calls", s.Len(), calls)
+	}
+}
+
+func TestCacheBatch(t *testing.T) {
+	s := MapStore{}
+	batches := 0
+	f := func(xs, ys []float64) {
+		batches++
+		for i, x := range xs {
+			ys[i] = x * x
+		}
+	}
+	first, err := IntegrateBatch(f, 0, 1, 1e-12, WithCache(s))
+	if err != nil || len(s) != first.Evals {
+		t.Fatalf("%d stored of %d evaluations, %v", len(s), first.Evals, err)
+	}
+
+	batches = 0
+	r, err := IntegrateBatch(f, 0, 1, 1e-12, WithCache(s))
+	if err != nil || r.Value != first.Value || r.Evals != 0 || batches != 0 {
+		t.Errorf("got %g, %v in %d evaluations and %d batches", r.Value, err, r.Evals, batches)
+	}
+}
+
+// A failingStore fails every Put.
+type failingStore struct{ MapStore }
+
+var errStore = errors.New("store full")
+
+func (s failingStore) Put(x, y float64) error { return errStore }
+
+func TestCacheError(t *testing.T) {
+	r, err := IntegrateGK(math.Exp, 0, 1, 1e-12, WithCache(failingStore{MapStore{}}))
+	if err != errStore {
+		t.Errorf("got %v", err)
+	}
+	if msg, ok := checkValue(r.Value, math.E-1, 1e-12); !ok {
+		t.Error(msg)
+	}
+}
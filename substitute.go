@@ -0,0 +1,78 @@
+package goint
+
+import (
+	"math"
+)
+
+// Substitute returns the integrand g(t) = f(phi(t)) phi'(t) of the
+// substitution x = phi(t), where dphi is the derivative of phi, so that
+// the integral of f from phi(lo) to phi(hi) is that of g from lo to hi.
+// g is zero wherever phi' vanishes, so that a singularity of f swept into
+// a point of t, or to an infinite limit, does not evaluate to NaN.
+func Substitute(f, phi, dphi Function) Function {
+	return func(t float64) float64 {
+		w := dphi(t)
+		if w == 0 {
+			return 0
+		}
+		return f(phi(t)) * w
+	}
+}
+
+// A Transform is a change of variables x = Phi(t) with derivative DPhi and
+// inverse Inverse, which Apply turns into a new integrand and limits.
+// Transforms compose, so that, for example, a singularity at both ends
+// can be removed by stacking substitutions.
+type Transform struct {
+	Phi, DPhi, Inverse Function
+}
+
+// Apply returns the integrand and limits in t of the integral of f over
+// [a, b]; lo exceeds hi if Phi is decreasing, which the integrators
+// handle. The integrand is never needed at lo or hi themselves, where a
+// removed singularity may leave it undefined.
+func (tr Transform) Apply(f Function, a, b float64) (g Function, lo, hi float64) {
+	return Substitute(f, tr.Phi, tr.DPhi), tr.Inverse(a), tr.Inverse(b)
+}
+
+// Compose returns the transform x = tr.Phi(inner.Phi(s)), substituting
+// inner into the variable of tr.
+func (tr Transform) Compose(inner Transform) Transform {
+	return Transform{
+		Phi:     func(s float64) float64 { return tr.Phi(inner.Phi(s)) },
+		DPhi:    func(s float64) float64 { return tr.DPhi(inner.Phi(s)) * inner.DPhi(s) },
+		Inverse: func(x float64) float64 { return inner.Inverse(tr.Inverse(x)) },
+	}
+}
+
+// LogTransform returns x = c + e^t, which maps (c, b] onto (-inf, ln(b -
+// c)] and turns a singularity of f at c like a power of x - c or of its
+// logarithm into exponential decay.
+func LogTransform(c float64) Transform {
+	return Transform{
+		Phi:     func(t float64) float64 { return c + math.Exp(t) },
+		DPhi:    math.Exp,
+		Inverse: func(x float64) float64 { return math.Log(x - c) },
+	}
+}
+
+// SqrtTransform returns x = c + t^2, for t >= 0, which removes an inverse
+// square root singularity at c and makes a square root kink there smooth.
+func SqrtTransform(c float64) Transform {
+	return Transform{
+		Phi:     func(t float64) float64 { return c + t*t },
+		DPhi:    func(t float64) float64 { return 2 * t },
+		Inverse: func(x float64) float64 { return math.Sqrt(x - c) },
+	}
+}
+
+// ReciprocalTransform returns x = 1/t, which maps an infinite tail [a,
+// inf), a > 0, onto (0, 1/a], so that algebraic decay of f becomes
+// polynomial behavior at zero.
+func ReciprocalTransform() Transform {
+	return Transform{
+		Phi:     func(t float64) float64 { return 1 / t },
+		DPhi:    func(t float64) float64 { return -1 / (t * t) },
+		Inverse: func(x float64) float64 { return 1 / x },
+	}
+}
@@ -0,0 +1,53 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSubstitute(t *testing.T) {
+	// An inverse square root singularity becomes a constant
+	f := func(x float64) float64 { return 1 / math.Sqrt(x-1) }
+	g, lo, hi := SqrtTransform(1).Apply(f, 1, 5)
+	r, err := IntegrateGK(g, lo, hi, 1e-13)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg, ok := checkValue(r.Value, 4, 1e-13); !ok || r.Panels != 1 {
+		t.Errorf("%s in %d panels", msg, r.Panels)
+	}
+
+	// A logarithmic singularity becomes exponential decay
+	g, lo, hi = LogTransform(0).Apply(math.Log, 0, 1)
+	if !math.IsInf(lo, -1) || hi != 0 {
+		t.Errorf("limits %g, %g", lo, hi)
+	}
+	r, _ = IntegrateGK(g, lo, hi, 1e-12)
+	if msg, ok := checkValue(r.Value, -1, 1e-12); !ok {
+		t.Error(msg)
+	}
+
+	// An algebraic tail becomes a polynomial on a finite interval, with
+	// the limits reversed
+	g, lo, hi = ReciprocalTransform().Apply(func(x float64) float64 { return 1 / (x * x * x) }, 1, math.Inf(1))
+	if lo != 1 || hi != 0 {
+		t.Errorf("limits %g, %g", lo, hi)
+	}
+	r, _ = IntegrateGK(g, lo, hi, 1e-14)
+	if msg, ok := checkValue(r.Value, .5, 1e-14); !ok {
+		t.Error(msg)
+	}
+
+	// Composition: x = 1 + e^(2s) has derivative 2e^(2s)
+	tr := SqrtTransform(1).Compose(LogTransform(0))
+	s := .3
+	if msg, ok := checkValue(tr.Phi(s), 1+math.Exp(2*s), 1e-15); !ok {
+		t.Error(msg)
+	}
+	if msg, ok := checkValue(tr.DPhi(s), 2*math.Exp(2*s), 1e-15); !ok {
+		t.Error(msg)
+	}
+	if msg, ok := checkValue(tr.Inverse(tr.Phi(s)), s, 1e-15); !ok {
+		t.Error(msg)
+	}
+}
@@ -0,0 +1,46 @@
+package goint
+
+import (
+	"sort"
+)
+
+// A Table is a tabulated function. It is interpolated linearly between its
+// knots X, which must be increasing, and held constant beyond them. Its
+// method value At is a Function; its knots are the natural breakpoints
+// when integrating it.
+type Table struct {
+	X, Y []float64
+}
+
+// At evaluates the table at x.
+func (t *Table) At(x float64) float64 {
+	n := len(t.X)
+	switch {
+	case n == 0:
+		return 0
+	case x <= t.X[0]:
+		return t.Y[0]
+	case x >= t.X[n-1]:
+		return t.Y[n-1]
+	}
+
+	i := sort.SearchFloat64s(t.X, x)
+	if t.X[i] == x {
+		return t.Y[i]
+	}
+	x0, x1 := t.X[i-1], t.X[i]
+	y0, y1 := t.Y[i-1], t.Y[i]
+
+	return y0 + (y1-y0)*(x-x0)/(x1-x0)
+}
+
+// Knots returns the abscissae of the tables, merged into one slice, for
+// use as breakpoints.
+func Knots(tables ...*Table) []float64 {
+	var ret []float64
+	for _, t := range tables {
+		ret = append(ret, t.X...)
+	}
+
+	return ret
+}
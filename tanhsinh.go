@@ -0,0 +1,46 @@
+package goint
+
+import (
+	"math"
+)
+
+// TanhSinh integrates f over the finite interval [a, b] to within tol by
+// tanh-sinh quadrature: the substitution x = tanh(π/2 sinh t) turns the
+// integral into one over the whole line whose integrand decays double
+// exponentially, where the trapezoid rule converges exponentially fast.
+// Starting from unit step, the step is halved, reusing every earlier
+// evaluation, until successive estimates agree to within tol; for
+// integrands analytic inside the interval each halving roughly doubles
+// the number of correct digits. f is never evaluated at the endpoints,
+// and nodes near them are placed by their distance from the endpoint, so
+// integrable algebraic and logarithmic endpoint singularities cost little
+// more than smooth integrands. Near a nonzero endpoint, though, x itself
+// is rounded, so a singularity there is only resolved to the extent that
+// f can be computed from the rounded x: 1/sqrt(1 - x) near 1 loses about
+// the square root of the machine precision. Where possible, place such
+// singularities at zero. Result.Error is the difference between the
+// last two estimates, which bounds the error of the last in practice.
+// Infinite or NaN bounds, or a NaN or negative tolerance, give a NaN
+// estimate and ErrInvalidInput; IntegrateConformal handles infinite
+// intervals. If the tolerance cannot be met, the best estimate is
+// returned with ErrNotConverged.
+func TanhSinh(f Function, a, b, tol float64) (Result, error) {
+	if math.IsNaN(a) || math.IsNaN(b) || math.IsInf(a, 0) || math.IsInf(b, 0) || !(tol >= 0) {
+		return Result{Value: math.NaN()}, ErrInvalidInput
+	}
+	if a == b {
+		return Result{}, nil
+	}
+	if a > b {
+		r, err := TanhSinh(f, b, a, tol)
+		r.Value = -r.Value
+		return r, err
+	}
+
+	value, diff, evals := conformalTrapezoid(f, a, b, 1, tol)
+	r := Result{Value: value, Error: diff, Evals: evals}
+	if !(diff < tol) {
+		return r, ErrNotConverged
+	}
+	return r, nil
+}
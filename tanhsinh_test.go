@@ -0,0 +1,50 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTanhSinh(t *testing.T) {
+	const tol = 1e-12
+
+	cases := []struct {
+		f       Function
+		a, b    float64
+		correct float64
+	}{
+		{math.Exp, 0, 1, math.E - 1},
+		{math.Exp, 1, 0, 1 - math.E},
+		{func(x float64) float64 { return 1 / math.Sqrt(x) }, 0, 1, 2},
+		{func(x float64) float64 { return math.Log(x) }, 0, 1, -1},
+		{func(x float64) float64 { return 1 / math.Sqrt(x*(2-x)) }, 0, 1, math.Pi / 2},
+		{func(x float64) float64 { return math.Pow(x, -.9) }, 0, 1, 10},
+		{func(x float64) float64 { return math.Log(x) * math.Log(1-x) }, 0, 1, 2 - math.Pi*math.Pi/6},
+	}
+	for i, c := range cases {
+		r, err := TanhSinh(c.f, c.a, c.b, tol)
+		if err != nil {
+			t.Errorf("case %d: %v", i, err)
+		}
+		if msg, ok := checkValue(r.Value, c.correct, 100*tol*math.Max(1, math.Abs(c.correct))); !ok {
+			t.Errorf("case %d: %s", i, msg)
+		}
+	}
+
+	// Far fewer evaluations than Boole-based refinement
+	evals := 0
+	counted := func(x float64) float64 { evals++; return math.Exp(x) * math.Cos(x) }
+	r, _ := TanhSinh(counted, 0, 2, 1e-10)
+	if r.Evals != evals {
+		t.Errorf("reported %d evaluations, made %d", r.Evals, evals)
+	}
+	evals = 0
+	Integrate(counted, 0, 2, 1e-10)
+	if r.Evals*2 > evals {
+		t.Errorf("tanh-sinh used %d evaluations, Integrate %d", r.Evals, evals)
+	}
+
+	if _, err := TanhSinh(math.Exp, 0, math.Inf(1), tol); err != ErrInvalidInput {
+		t.Errorf("infinite bound gave %v", err)
+	}
+}
@@ -0,0 +1,137 @@
+package goint
+
+import (
+	"math"
+	"sort"
+)
+
+const (
+	// The deepest bisection used when placing path nodes.
+	maxPathDepth = 30
+
+	// The number of distinct lambdas estimated after which no more panels
+	// are bisected, bounding the work however small tol is.
+	maxPathSamples = 1 << 12
+)
+
+// ThermodynamicIntegration computes log Z(1) - log Z(0) as the integral of
+// the derivative d log Z / d lambda over lambda in [0, 1], to within tol.
+// Nodes are placed by adaptive Simpson bisection, so that they cluster
+// where the derivative changes quickly, typically near lambda = 0. The
+// result is returned with an estimate of its discretization error, and is
+// NaN if tol is negative or NaN.
+func ThermodynamicIntegration(logZgrad func(lambda float64) float64, tol float64) (logZ, errEst float64) {
+	logZ, errEst, _ = PathSampling(func(lambda float64) (float64, float64) {
+		return logZgrad(lambda), 0
+	}, tol)
+
+	return logZ, errEst
+}
+
+// PathSampling is ThermodynamicIntegration for derivatives that are
+// themselves Monte Carlo estimates: estimate returns the mean and standard
+// error of d log Z / d lambda at lambda, for example from an MCMC run at
+// that temperature. A panel is accepted once its discretization error is
+// within its share of tol or is statistically indistinguishable from the
+// noise, so refinement does not chase Monte Carlo error. The standard
+// errors are propagated through the quadrature weights, assuming
+// independent estimates, into statErr; discErr estimates the
+// discretization error. Each lambda is estimated only once, and once
+// about 4096 have been, the remaining panels are accepted as they are, so
+// a tol too small to meet bounds the work rather than the accuracy. All
+// three results are NaN if tol is negative or NaN.
+func PathSampling(estimate func(lambda float64) (mean, stderr float64), tol float64) (logZ, discErr, statErr float64) {
+	if !(tol >= 0) {
+		return math.NaN(), math.NaN(), math.NaN()
+	}
+
+	type sample struct{ mean, se float64 }
+	samples := make(map[float64]sample)
+	eval := func(lambda float64) sample {
+		if s, ok := samples[lambda]; ok {
+			return s
+		}
+		m, se := estimate(lambda)
+		s := sample{m, se}
+		samples[lambda] = s
+		return s
+	}
+
+	weights := make(map[float64]float64)
+
+	// A panel of width 2^-depth gets the same share of tol
+	type panel struct {
+		a, b, diff float64
+		depth      int
+		done       bool
+	}
+	measure := func(a, b float64, depth int) panel {
+		h := (b - a) / 4
+		xs := [5]float64{a, a + h, a + 2*h, a + 3*h, b}
+		var s [5]sample
+		for i, x := range xs {
+			s[i] = eval(x)
+		}
+
+		// Simpson's rule on the whole panel and on its two halves
+		coarse := [5]float64{2 * h / 3, 0, 8 * h / 3, 0, 2 * h / 3}
+		fine := [5]float64{h / 3, 4 * h / 3, 2 * h / 3, 4 * h / 3, h / 3}
+
+		diff, noise := 0.0, 0.0
+		for i := range xs {
+			d := fine[i] - coarse[i]
+			diff += d * s[i].mean
+			noise += d * d * s[i].se * s[i].se
+		}
+
+		done := depth >= maxPathDepth || math.Abs(diff)/15 <= tol*(b-a) ||
+			math.Abs(diff) <= 2*math.Sqrt(noise)
+		return panel{a, b, diff, depth, done}
+	}
+	accept := func(p panel) {
+		h := (p.b - p.a) / 4
+		xs := [5]float64{p.a, p.a + h, p.a + 2*h, p.a + 3*h, p.b}
+		fine := [5]float64{h / 3, 4 * h / 3, 2 * h / 3, 4 * h / 3, h / 3}
+		for i, x := range xs {
+			weights[x] += fine[i]
+		}
+		discErr += math.Abs(p.diff) / 15
+	}
+
+	// Bisect the worst panel first, so that if the estimates run out the
+	// effort has gone where it was most needed
+	pending := []panel{measure(0, 1, 0)}
+	for len(pending) > 0 {
+		worst := 0
+		for i, p := range pending {
+			if math.Abs(p.diff) > math.Abs(pending[worst].diff) {
+				worst = i
+			}
+		}
+		p := pending[worst]
+		pending = append(pending[:worst], pending[worst+1:]...)
+
+		if p.done || len(samples) >= maxPathSamples {
+			accept(p)
+			continue
+		}
+		m := p.a + (p.b-p.a)/2
+		pending = append(pending, measure(p.a, m, p.depth+1), measure(m, p.b, p.depth+1))
+	}
+
+	// Sum in a fixed order, so that results do not vary from run to run
+	xs := make([]float64, 0, len(weights))
+	for x := range weights {
+		xs = append(xs, x)
+	}
+	sort.Float64s(xs)
+
+	variance := 0.0
+	for _, x := range xs {
+		w, s := weights[x], samples[x]
+		logZ += w * s.mean
+		variance += w * w * s.se * s.se
+	}
+
+	return logZ, discErr, math.Sqrt(variance)
+}
@@ -0,0 +1,88 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestThermodynamicIntegration(t *testing.T) {
+	const (
+		tol = 1e-9
+	)
+
+	// A derivative that varies rapidly near lambda = 0; its integral is
+	// (c / k) log(1 + k)
+	c, k := 2.0, 50.0
+	grad := func(lambda float64) float64 { return c / (1 + k*lambda) }
+
+	calls := 0
+	counted := func(lambda float64) float64 {
+		calls++
+		return grad(lambda)
+	}
+
+	logZ, errEst := ThermodynamicIntegration(counted, tol)
+	want := c / k * math.Log(1+k)
+	if msg, ok := checkValue(logZ, want, 10*tol); !ok {
+		t.Error(msg)
+	}
+	if errEst > tol {
+		t.Errorf("error estimate %g exceeds %g", errEst, tol)
+	}
+	if calls > 2000 {
+		t.Errorf("used %d evaluations", calls)
+	}
+}
+
+func TestPathSampling(t *testing.T) {
+	// With noisy estimates refinement stops at the noise level, and the
+	// statistical error is propagated
+	noisy := func(lambda float64) (float64, float64) {
+		return 1 + lambda, .01
+	}
+
+	logZ, discErr, statErr := PathSampling(noisy, 1e-12)
+	if msg, ok := checkValue(logZ, 1.5, 1e-12); !ok {
+		t.Error(msg)
+	}
+	if discErr > 1e-12 {
+		t.Errorf("discretization error %g for a linear integrand", discErr)
+	}
+
+	// A single Simpson panel pair over [0, 1]: the weights sum to one and
+	// the propagated error lies between .01 / sqrt(5) and .01
+	if statErr <= .01/math.Sqrt(5) || statErr >= .01 {
+		t.Errorf("statistical error %g out of range", statErr)
+	}
+
+	// A zero tolerance bounds the number of estimates, and an invalid one
+	// is rejected
+	calls := 0
+	exact := func(lambda float64) (float64, float64) {
+		calls++
+		return math.Exp(lambda), 0
+	}
+	logZ, _, _ = PathSampling(exact, 0)
+	if msg, ok := checkValue(logZ, math.E-1, 1e-12); !ok || calls > maxPathSamples+3*maxPathDepth {
+		t.Errorf("tol = 0: %s in %d estimates", msg, calls)
+	}
+	for _, bad := range []float64{-1, math.NaN()} {
+		if l, d, s := PathSampling(exact, bad); !math.IsNaN(l) || !math.IsNaN(d) || !math.IsNaN(s) {
+			t.Errorf("tol = %g gave %v, %v, %v", bad, l, d, s)
+		}
+	}
+}
+
+func TestPathSamplingRepeatable(t *testing.T) {
+	estimate := func(lambda float64) (float64, float64) {
+		return math.Exp(3*lambda) + math.Sin(40*lambda), 1e-9
+	}
+
+	logZ, discErr, statErr := PathSampling(estimate, 1e-8)
+	for i := 0; i < 5; i++ {
+		l, d, s := PathSampling(estimate, 1e-8)
+		if l != logZ || d != discErr || s != statErr {
+			t.Fatalf("run %d gave %v, %v, %v; want %v, %v, %v", i, l, d, s, logZ, discErr, statErr)
+		}
+	}
+}
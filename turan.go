@@ -0,0 +1,223 @@
+package goint
+
+import (
+	"math"
+)
+
+// The most nodes GaussTuran accepts.
+const maxGaussTuran = 12
+
+// A SmoothFunction is an integrand that can supply its first two
+// derivatives along with its value, as analytic integrands and those
+// evaluated by automatic differentiation can at little extra cost.
+type SmoothFunction interface {
+	Derivatives(x float64) (f, df, d2f float64)
+}
+
+// A SmoothFunc is a SmoothFunction given as a function returning the
+// value and first two derivatives at x.
+type SmoothFunc func(x float64) (f, df, d2f float64)
+
+// Derivatives returns s(x).
+func (s SmoothFunc) Derivatives(x float64) (f, df, d2f float64) {
+	return s(x)
+}
+
+// A TuranRule is a Gauss-Turan quadrature rule on [-1, 1], which uses the
+// integrand's value and first two derivatives at each of its n nodes to
+// integrate polynomials of degree 4n - 1 exactly, against 2n - 1 for the
+// Gauss rule on as many nodes. It is applied to other intervals by an
+// affine map.
+type TuranRule struct {
+	nodes, weights, slopes, curvatures []float64
+}
+
+// GaussTuran returns the Gauss-Turan rule with n nodes, or nil unless
+// 1 <= n <= 12. Its nodes are the zeros of the polynomial p of degree n
+// for which p^3 is orthogonal to every polynomial of lower degree, found
+// by Newton's method from the Gauss-Legendre nodes, and its weights
+// follow from exactness on polynomials of degree below 3n.
+func GaussTuran(n int) *TuranRule {
+	if n < 1 || n > maxGaussTuran {
+		return nil
+	}
+
+	nodes := turanNodes(n)
+	if nodes == nil {
+		return nil
+	}
+	return turanRule(nodes)
+}
+
+// Returns the zeros of the monic polynomial p of degree n with p^3
+// orthogonal to the Legendre polynomials P_0, ..., P_(n-1), or nil if
+// Newton's method fails. The orthogonality integrals, of degree 4n - 1,
+// are evaluated exactly by the 2n-point Gauss-Legendre rule.
+func turanNodes(n int) []float64 {
+	nodes, _ := gaussLegendre(n)
+	ts, ws := gaussLegendre(2 * n)
+
+	F := make([]float64, n)
+	J := newMatrix(n, n)
+	others := make([]float64, n)
+	for iter := 0; iter < 100; iter++ {
+		for k := range F {
+			F[k] = 0
+			for j := range J[k] {
+				J[k][j] = 0
+			}
+		}
+		for q, t := range ts {
+			// p(t) and, for each j, the product of t - x_i over i != j
+			p := 1.0
+			for j := range others {
+				others[j] = 1
+			}
+			for i, x := range nodes {
+				p *= t - x
+				for j := range others {
+					if j != i {
+						others[j] *= t - x
+					}
+				}
+			}
+
+			for k := range F {
+				pk, _ := legendre(k, t)
+				F[k] += ws[q] * p * p * p * pk
+				for j := range nodes {
+					J[k][j] -= 3 * ws[q] * p * p * others[j] * pk
+				}
+			}
+		}
+
+		for k := range F {
+			F[k] = -F[k]
+		}
+		dx, err := solve(J, F)
+		if err != nil {
+			return nil
+		}
+		step := 0.0
+		for j := range nodes {
+			nodes[j] += dx[j]
+			step = math.Max(step, math.Abs(dx[j]))
+		}
+		if step <= 4*epsilon {
+			return nodes
+		}
+	}
+
+	return nil
+}
+
+// Returns the rule on [-1, 1] with the given nodes that integrates every
+// polynomial of degree below 3 len(nodes) exactly from values and first
+// and second derivatives, posing the moment equations in the Chebyshev
+// basis as derivativeRule does.
+func turanRule(nodes []float64) *TuranRule {
+	n := len(nodes)
+	A := newMatrix(3*n, 3*n)
+	moments := make([]float64, 3*n)
+	for j, x := range nodes {
+		t0, t1 := 1.0, x
+		d0, d1 := 0.0, 1.0
+		c0, c1 := 0.0, 0.0
+		for i := 0; i < 3*n; i++ {
+			A[i][j], A[i][n+j], A[i][2*n+j] = t0, d0, c0
+			t0, t1, d0, d1, c0, c1 = t1, 2*x*t1-t0, d1, 2*t1+2*x*d1-d0, c1, 4*d1+2*x*c1-c0
+		}
+	}
+	for i := 0; i < 3*n; i += 2 {
+		moments[i] = 2 / (1 - float64(i*i))
+	}
+
+	w, err := solve(A, moments)
+	if err != nil {
+		return nil
+	}
+
+	return &TuranRule{
+		nodes:      append([]float64(nil), nodes...),
+		weights:    w[:n],
+		slopes:     w[n : 2*n],
+		curvatures: w[2*n:],
+	}
+}
+
+// Nodes returns a copy of the nodes of the rule on [-1, 1].
+func (r *TuranRule) Nodes() []float64 {
+	return append([]float64(nil), r.nodes...)
+}
+
+// Weights returns copies of the weights of the values, first derivatives
+// and second derivatives at the nodes on [-1, 1], matching Nodes. On
+// [a, b] they scale by (b - a) / 2 and its square and cube.
+func (r *TuranRule) Weights() (values, slopes, curvatures []float64) {
+	return append([]float64(nil), r.weights...),
+		append([]float64(nil), r.slopes...),
+		append([]float64(nil), r.curvatures...)
+}
+
+// Apply estimates the integral of f over the finite interval [a, b] with
+// the rule.
+func (r *TuranRule) Apply(f SmoothFunction, a, b float64) float64 {
+	center, half := (a+b)/2, (b-a)/2
+	sum, slope, curvature := 0.0, 0.0, 0.0
+	for i, x := range r.nodes {
+		y, dy, d2y := f.Derivatives(center + half*x)
+		sum += r.weights[i] * y
+		slope += r.slopes[i] * dy
+		curvature += r.curvatures[i] * d2y
+	}
+	return half * (sum + half*(slope+half*curvature))
+}
+
+// Estimate estimates the integral of f over the finite interval [a, b]
+// with the rule applied to both halves, and its error as the difference
+// from the rule on the whole, the counterpart for SmoothFunctions of
+// Rule.Estimate.
+func (r *TuranRule) Estimate(f SmoothFunction, a, b float64) (value, err float64) {
+	m := a + (b-a)/2
+	whole := r.Apply(f, a, b)
+	halves := r.Apply(f, a, m) + r.Apply(f, m, b)
+	return halves, math.Abs(halves - whole)
+}
+
+// IntegrateTuran integrates f over the finite interval [a, b] to within
+// tol by adaptive bisection as IntegrateGK does, estimating each panel
+// with r.Estimate. Result.Evals counts the calls of f.Derivatives.
+// Infinite or NaN limits, a NaN or negative tolerance or a nil rule give
+// a NaN estimate and ErrInvalidInput; if the tolerance cannot be met, the
+// best estimate is returned with ErrNotConverged.
+func IntegrateTuran(f SmoothFunction, a, b, tol float64, r *TuranRule) (Result, error) {
+	if r == nil || math.IsNaN(a) || math.IsNaN(b) || math.IsInf(a, 0) || math.IsInf(b, 0) || !(tol >= 0) {
+		return Result{Value: math.NaN()}, ErrInvalidInput
+	}
+	if a == b {
+		return Result{}, nil
+	}
+	if a > b {
+		res, err := IntegrateTuran(f, b, a, tol, r)
+		res.Value = -res.Value
+		return res, err
+	}
+
+	evals := 0
+	counted := SmoothFunc(func(x float64) (float64, float64, float64) {
+		evals++
+		return f.Derivatives(x)
+	})
+	panel := func(_ Function, a, b float64) (float64, float64) {
+		return r.Estimate(counted, a, b)
+	}
+	done := func(value, total, worst float64) bool { return total <= tol }
+	s := adapt(nil, []float64{a, b}, panel, maxPanels, nil, done)
+
+	res := Result{Evals: evals, Panels: len(s.lefts)}
+	res.Value, res.Error = s.sum()
+	if !(res.Error <= tol) {
+		return res, ErrNotConverged
+	}
+	return res, nil
+}
@@ -0,0 +1,77 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+// Returns x^d and its first two derivatives as a SmoothFunction.
+func monomial(d int) SmoothFunc {
+	return func(x float64) (float64, float64, float64) {
+		k := float64(d)
+		f, df, d2f := math.Pow(x, k), 0.0, 0.0
+		if d >= 1 {
+			df = k * math.Pow(x, k-1)
+		}
+		if d >= 2 {
+			d2f = k * (k - 1) * math.Pow(x, k-2)
+		}
+		return f, df, d2f
+	}
+}
+
+func TestGaussTuran(t *testing.T) {
+	// One node: 2 f(0) + f''(0) / 3
+	values, slopes, curvatures := GaussTuran(1).Weights()
+	if msg, ok := checkValue(values[0], 2, 1e-15); !ok {
+		t.Error(msg)
+	}
+	if msg, ok := checkValue(slopes[0], 0, 1e-15); !ok {
+		t.Error(msg)
+	}
+	if msg, ok := checkValue(curvatures[0], 1./3, 1e-15); !ok {
+		t.Error(msg)
+	}
+
+	// n nodes are exact to degree 4n - 1, on any interval
+	for n := 1; n <= maxGaussTuran; n++ {
+		r := GaussTuran(n)
+		if r == nil {
+			t.Fatalf("no rule with %d nodes", n)
+		}
+		for d := 0; d < 4*n; d++ {
+			got := r.Apply(monomial(d), 0, 2)
+			correct := math.Pow(2, float64(d+1)) / float64(d+1)
+			if msg, ok := checkValue(got, correct, 1e-12*correct); !ok {
+				t.Errorf("%d nodes, degree %d: %s", n, d, msg)
+			}
+		}
+	}
+
+	if GaussTuran(0) != nil || GaussTuran(maxGaussTuran+1) != nil {
+		t.Error("accepted an unsupported number of nodes")
+	}
+}
+
+func TestIntegrateTuran(t *testing.T) {
+	exp := SmoothFunc(func(x float64) (float64, float64, float64) {
+		y := math.Exp(x)
+		return y, y, y
+	})
+	r, err := IntegrateTuran(exp, 1, -2, 1e-13, GaussTuran(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg, ok := checkValue(r.Value, math.Exp(-2)-math.E, 1e-13); !ok {
+		t.Error(msg)
+	}
+	// Each panel estimate applies the 3-node rule three times, and every
+	// bisection makes two estimates
+	if r.Evals != 9*(2*r.Panels-1) {
+		t.Errorf("%d evaluations in %d panels", r.Evals, r.Panels)
+	}
+
+	if r, err := IntegrateTuran(exp, 0, 1, 1e-10, nil); err != ErrInvalidInput || !math.IsNaN(r.Value) {
+		t.Errorf("got %g, %v without a rule", r.Value, err)
+	}
+}
@@ -0,0 +1,21 @@
+package goint
+
+import (
+	"fmt"
+	"math"
+)
+
+// Determines if got is within h of correct, in the manner of
+// test_integral. Infinite values must match exactly.
+func checkValue(got, correct, h float64) (string, bool) {
+	if got == correct {
+		return "", true
+	}
+
+	if err := math.Abs(got - correct); !(err <= h) {
+		msg := fmt.Sprintf("%.10g differs from %.10g by more than %.3g", got, correct, h)
+		return msg, false
+	}
+
+	return "", true
+}
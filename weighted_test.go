@@ -0,0 +1,143 @@
+package goint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGaussLaguerre(t *testing.T) {
+	if GaussLaguerre(0) != nil {
+		t.Error("GaussLaguerre(0) should be nil")
+	}
+
+	for _, n := range []int{1, 2, 5, 10} {
+		r := GaussLaguerre(n)
+
+		// The integral of x^k e^-x over [0, ∞) is k!, exact for k < 2n;
+		// compare relatively as the moments grow quickly
+		factorial := 1.0
+		for k := 0; k < 2*n; k++ {
+			if k > 0 {
+				factorial *= float64(k)
+			}
+			pow := func(x float64) float64 { return math.Pow(x, float64(k)) }
+			if msg, ok := checkValue(r.Apply(pow)/factorial, 1, 1e-11); !ok {
+				t.Errorf("n = %d, moment %d: %s", n, k, msg)
+			}
+		}
+	}
+
+	// The full integrand e^-2x integrates to 1/2
+	r := GaussLaguerre(30)
+	full := func(x float64) float64 { return math.Exp(-2 * x) }
+	if msg, ok := checkValue(r.ApplyFull(full), .5, 1e-10); !ok {
+		t.Error(msg)
+	}
+}
+
+func TestGaussHermiteRule(t *testing.T) {
+	if GaussHermite(0) != nil {
+		t.Error("GaussHermite(0) should be nil")
+	}
+
+	// The full integrand e^(-x^2) cos x integrates to sqrt(π) e^(-1/4)
+	full := func(x float64) float64 { return math.Exp(-x*x) * math.Cos(x) }
+	if msg, ok := checkValue(GaussHermite(30).ApplyFull(full), math.SqrtPi*math.Exp(-.25), 1e-12); !ok {
+		t.Error(msg)
+	}
+}
+
+func TestExpectGaussian(t *testing.T) {
+	const mean, sigma = 1.5, .7
+
+	cases := []struct {
+		g       Function
+		correct float64
+	}{
+		{func(x float64) float64 { return 1 }, 1},
+		{func(x float64) float64 { return x }, mean},
+		{func(x float64) float64 { return (x - mean) * (x - mean) }, sigma * sigma},
+		{math.Exp, math.Exp(mean + sigma*sigma/2)},
+		{math.Cos, math.Cos(mean) * math.Exp(-sigma*sigma/2)},
+	}
+	for i, c := range cases {
+		if msg, ok := checkValue(ExpectGaussian(c.g, mean, sigma), c.correct, 1e-12); !ok {
+			t.Errorf("case %d: %s", i, msg)
+		}
+	}
+
+	if !math.IsNaN(ExpectGaussian(math.Exp, 0, -1)) {
+		t.Error("negative sigma should give NaN")
+	}
+}
+
+func TestGaussJacobi(t *testing.T) {
+	if GaussJacobi(3, -1, 0, -1, 1) != nil || GaussJacobi(3, 0, 0, 1, 1) != nil {
+		t.Error("invalid parameters should give nil")
+	}
+
+	beta := func(x, y float64) float64 {
+		lx, _ := math.Lgamma(x)
+		ly, _ := math.Lgamma(y)
+		lxy, _ := math.Lgamma(x + y)
+		return math.Exp(lx + ly - lxy)
+	}
+
+	// The integral of (b - x)^alpha (x - a)^(beta + k) over [a, b] is
+	// (b - a)^(alpha + beta + k + 1) B(alpha + 1, beta + k + 1)
+	const a, b = .5, 2.5
+	for _, p := range [][2]float64{{0, 0}, {-.5, -.5}, {.3, -.7}, {2.5, 1}} {
+		r := GaussJacobi(4, p[0], p[1], a, b)
+		for k := 0; k < 8; k++ {
+			pow := func(x float64) float64 { return math.Pow(x-a, float64(k)) }
+			correct := math.Pow(b-a, p[0]+p[1]+float64(k)+1) * beta(p[0]+1, p[1]+float64(k)+1)
+			if msg, ok := checkValue(r.Apply(pow)/correct, 1, 1e-12); !ok {
+				t.Errorf("alpha = %v, beta = %v, moment %d: %s", p[0], p[1], k, msg)
+			}
+		}
+	}
+
+	// The integral of cos(x) / sqrt(x) over [0, 1], from its series
+	correct, term := 0.0, 1.0
+	for k := 0; k < 20; k++ {
+		if k > 0 {
+			term /= -float64(2*k) * float64(2*k-1)
+		}
+		correct += term / (float64(2*k) + .5)
+	}
+	r := GaussJacobi(10, 0, -.5, 0, 1)
+	if msg, ok := checkValue(r.Apply(math.Cos), correct, 1e-14); !ok {
+		t.Error(msg)
+	}
+	full := func(x float64) float64 { return math.Cos(x) / math.Sqrt(x) }
+	if msg, ok := checkValue(r.ApplyFull(full), correct, 1e-14); !ok {
+		t.Error(msg)
+	}
+}
+
+func TestGaussChebyshev(t *testing.T) {
+	if GaussChebyshev1(0) != nil || GaussChebyshev2(0) != nil {
+		t.Error("n = 0 should give nil")
+	}
+
+	// Both rules agree with Gauss-Jacobi for alpha = beta = -1/2 and 1/2
+	for _, c := range []struct {
+		r, jacobi *WeightedRule
+	}{
+		{GaussChebyshev1(6), GaussJacobi(6, -.5, -.5, -1, 1)},
+		{GaussChebyshev2(6), GaussJacobi(6, .5, .5, -1, 1)},
+	} {
+		for k := 0; k < 12; k++ {
+			pow := func(x float64) float64 { return math.Pow(x, float64(k)) }
+			if msg, ok := checkValue(c.r.Apply(pow), c.jacobi.Apply(pow), 1e-13); !ok {
+				t.Errorf("moment %d: %s", k, msg)
+			}
+		}
+	}
+
+	// The integral of e^x / sqrt(1 - x^2) over [-1, 1] is π I_0(1)
+	const i0 = 1.2660658777520082
+	if msg, ok := checkValue(GaussChebyshev1(20).Apply(math.Exp), math.Pi*i0, 1e-14); !ok {
+		t.Error(msg)
+	}
+}